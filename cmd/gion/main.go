@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -11,6 +12,10 @@ import (
 
 func main() {
 	if err := cli.Run(); err != nil {
+		var exitErr *cli.ExitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		if isatty.IsTerminal(os.Stderr.Fd()) {
 			theme := ui.DefaultTheme()
 			renderer := ui.NewRenderer(os.Stderr, theme, true)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tasuku43/gion/internal/app/add"
@@ -15,6 +16,7 @@ import (
 	"github.com/tasuku43/gion/internal/domain/repo"
 	"github.com/tasuku43/gion/internal/domain/workspace"
 	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
 )
 
 type Options struct {
@@ -22,94 +24,212 @@ type Options struct {
 	AllowStatusError bool
 	PrefetchTimeout  time.Duration
 	PrefetchOK       bool
-	Step             func(text string)
+	// Concurrency bounds how many repo stores get worktrees created at once.
+	// Zero uses DefaultConcurrency; 1 creates worktrees sequentially.
+	Concurrency int
+	// Journal, when set, records every completed step and skips steps it already
+	// holds (used to resume an interrupted apply).
+	Journal *Journal
+	Step    func(text string)
 }
 
+// Apply reconciles the filesystem with plan. Hooks declared for the affected workspaces
+// run along the way; their failures are returned as *HookError once everything else was
+// applied (other errors stop the apply and leave the journal for --resume/--rollback).
 func Apply(ctx context.Context, rootDir string, plan manifestplan.Result, opts Options) error {
+	hooks := &hookRunner{rootDir: rootDir, plan: plan, step: opts.Step}
 	for _, change := range plan.Changes {
 		if change.Kind != manifestplan.WorkspaceRemove {
 			continue
 		}
+		if _, done := opts.Journal.completed(JournalRemoveWorkspace, change.WorkspaceID, ""); done {
+			continue
+		}
+		for _, repoEntry := range plan.Actual.Workspaces[change.WorkspaceID].Repos {
+			hooks.run(ctx, HookPreRemove, change.WorkspaceID, repoEntry.Alias)
+		}
 		logStep(opts.Step, fmt.Sprintf("remove workspace %s", change.WorkspaceID))
 		if err := rm.Remove(ctx, rootDir, change.WorkspaceID, opts.AllowDirty); err != nil {
 			return err
 		}
+		if err := opts.Journal.record(JournalStep{Kind: JournalRemoveWorkspace, WorkspaceID: change.WorkspaceID}); err != nil {
+			return err
+		}
+	}
+
+	for _, change := range plan.Changes {
+		if change.Kind != manifestplan.WorkspaceRename {
+			continue
+		}
+		if _, done := opts.Journal.completed(JournalMoveWorkspace, change.WorkspaceID, ""); done {
+			continue
+		}
+		logStep(opts.Step, fmt.Sprintf("move workspace %s -> %s", change.FromWorkspaceID, change.WorkspaceID))
+		if err := workspace.Move(ctx, rootDir, change.FromWorkspaceID, change.WorkspaceID); err != nil {
+			return err
+		}
+		if err := opts.Journal.record(JournalStep{Kind: JournalMoveWorkspace, WorkspaceID: change.WorkspaceID, FromWorkspaceID: change.FromWorkspaceID}); err != nil {
+			return err
+		}
 	}
 
 	for _, change := range plan.Changes {
 		if change.Kind != manifestplan.WorkspaceUpdate {
 			continue
 		}
-		if err := applyRepoRemovals(ctx, rootDir, change, opts); err != nil {
+		if err := applyRepoRemovals(ctx, rootDir, change, opts, hooks); err != nil {
 			return err
 		}
-		if err := applyRepoBranchRenames(ctx, rootDir, change, opts.Step); err != nil {
+		if err := applyRepoMoves(ctx, rootDir, change, opts); err != nil {
+			return err
+		}
+		if err := applyRepoBranchRenames(ctx, rootDir, change, opts); err != nil {
 			return err
 		}
 	}
 
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	var jobs []worktreeJob
 	for _, change := range plan.Changes {
 		switch change.Kind {
 		case manifestplan.WorkspaceAdd:
-			if err := applyWorkspaceAdd(ctx, rootDir, plan.Desired, change, opts); err != nil {
+			wsJobs, err := workspaceAddJobs(rootDir, plan.Desired, change, opts, concurrency > 1)
+			if err != nil {
 				return err
 			}
+			jobs = append(jobs, wsJobs...)
 		case manifestplan.WorkspaceUpdate:
-			if err := applyRepoAdds(ctx, rootDir, plan.Desired, change, opts); err != nil {
-				return err
-			}
+			jobs = append(jobs, repoAddJobs(rootDir, plan.Desired, change, opts, concurrency > 1)...)
+		}
+	}
+	results, err := runWorktreeJobs(ctx, jobs, concurrency, opts.Step)
+	if recordErr := recordBaseBranches(rootDir, jobs, results); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	// Worktrees created before a failure still get their post_create hooks: a resumed
+	// apply skips them.
+	for i, job := range jobs {
+		if results[i].done && !results[i].resumed {
+			hooks.run(ctx, HookPostCreate, job.workspaceID, job.alias)
 		}
 	}
-
-	return nil
-}
-
-func applyWorkspaceAdd(ctx context.Context, rootDir string, desired manifest.File, change manifestplan.WorkspaceChange, opts Options) error {
-	ws, ok := desired.Workspaces[change.WorkspaceID]
-	if !ok {
-		return fmt.Errorf("workspace not found in manifest: %s", change.WorkspaceID)
-	}
-	logStep(opts.Step, fmt.Sprintf("create workspace %s", change.WorkspaceID))
-	_, err := create.CreateWorkspace(ctx, rootDir, change.WorkspaceID, workspace.Metadata{
-		Description: ws.Description,
-		Mode:        ws.Mode,
-		PresetName:  ws.PresetName,
-		SourceURL:   ws.SourceURL,
-	})
 	if err != nil {
 		return err
 	}
-	baseBranchToRecord := ""
-	baseBranchMixed := false
-	fetch := true
-	if opts.PrefetchOK {
-		fetch = false
+	if err := applyMetadataChanges(rootDir, plan, opts); err != nil {
+		return err
 	}
-	for _, repoEntry := range ws.Repos {
-		logStep(opts.Step, fmt.Sprintf("worktree add %s", repoEntry.Alias))
-		if strings.EqualFold(strings.TrimSpace(ws.Mode), workspace.MetadataModeReview) {
-			if err := applyReviewRepoAdd(ctx, rootDir, change.WorkspaceID, repoEntry); err != nil {
-				return err
-			}
+	hooks.runPostApply(ctx)
+	return hooks.err()
+}
+
+// applyMetadataChanges rewrites .gion/metadata.json of existing workspaces whose metadata
+// differs from the manifest. It runs after worktrees are created so the desired base_ref
+// wins over the base branch recorded for newly created branches.
+func applyMetadataChanges(rootDir string, plan manifestplan.Result, opts Options) error {
+	for _, change := range plan.Changes {
+		if len(change.Metadata) == 0 {
+			continue
+		}
+		if _, done := opts.Journal.completed(JournalUpdateMetadata, change.WorkspaceID, ""); done {
 			continue
 		}
-		_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoEntry.RepoKey, repoEntry.Alias, repoEntry.Branch, repoEntry.BaseRef, fetch)
+		wsDir := workspace.WorkspaceDir(rootDir, change.WorkspaceID)
+		previous, err := workspace.LoadMetadata(wsDir)
 		if err != nil {
 			return err
 		}
-		if createdBranch {
-			baseBranchToRecord, baseBranchMixed = updateBaseBranchCandidate(baseBranchToRecord, baseBranchMixed, baseBranch)
+		meta := previous
+		for _, metadataChange := range change.Metadata {
+			switch metadataChange.Field {
+			case manifestplan.MetadataDescription:
+				meta.Description = metadataChange.To
+			case manifestplan.MetadataMode:
+				meta.Mode = metadataChange.To
+			case manifestplan.MetadataPresetName:
+				meta.PresetName = metadataChange.To
+			case manifestplan.MetadataSourceURL:
+				meta.SourceURL = metadataChange.To
+			case manifestplan.MetadataBaseRef:
+				meta.BaseBranch = metadataChange.To
+			}
+		}
+		logStep(opts.Step, fmt.Sprintf("update metadata %s", change.WorkspaceID))
+		if err := workspace.SaveMetadata(wsDir, meta); err != nil {
+			return err
+		}
+		if err := opts.Journal.record(JournalStep{Kind: JournalUpdateMetadata, WorkspaceID: change.WorkspaceID, Metadata: &previous}); err != nil {
+			return err
 		}
 	}
-	if baseBranchMixed {
-		// Workspace-level base_branch can't represent multiple different bases across repos.
-		// Keep it empty so `gion import` doesn't inject an incorrect base_ref into every repo.
-		baseBranchToRecord = ""
+	return nil
+}
+
+// workspaceAddJobs returns one job per repo of a new workspace. The workspace directory is
+// created by whichever of its jobs runs first.
+func workspaceAddJobs(rootDir string, desired manifest.File, change manifestplan.WorkspaceChange, opts Options, qualify bool) ([]worktreeJob, error) {
+	ws, ok := desired.Workspaces[change.WorkspaceID]
+	if !ok {
+		return nil, fmt.Errorf("workspace not found in manifest: %s", change.WorkspaceID)
+	}
+	var once sync.Once
+	var createErr error
+	ensureWorkspace := func(ctx context.Context, step func(text string)) error {
+		once.Do(func() {
+			if _, done := opts.Journal.completed(JournalCreateWorkspace, change.WorkspaceID, ""); done {
+				return
+			}
+			logStep(step, fmt.Sprintf("create workspace %s", change.WorkspaceID))
+			_, createErr = create.CreateWorkspace(ctx, rootDir, change.WorkspaceID, workspace.Metadata{
+				Description: ws.Description,
+				Mode:        ws.Mode,
+				PresetName:  ws.PresetName,
+				SourceURL:   ws.SourceURL,
+			})
+			if createErr == nil {
+				createErr = opts.Journal.record(JournalStep{Kind: JournalCreateWorkspace, WorkspaceID: change.WorkspaceID})
+			}
+		})
+		return createErr
 	}
-	if err := recordBaseBranchIfMissing(rootDir, change.WorkspaceID, baseBranchToRecord); err != nil {
-		return err
+	if len(ws.Repos) == 0 {
+		return []worktreeJob{{
+			workspaceID: change.WorkspaceID,
+			run: func(ctx context.Context, step func(text string)) (worktreeJobResult, error) {
+				return worktreeJobResult{}, ensureWorkspace(ctx, step)
+			},
+		}}, nil
 	}
-	return nil
+
+	fetch := !opts.PrefetchOK
+	review := strings.EqualFold(strings.TrimSpace(ws.Mode), workspace.MetadataModeReview)
+	jobs := make([]worktreeJob, 0, len(ws.Repos))
+	for _, repoEntry := range ws.Repos {
+		repoEntry := repoEntry
+		jobs = append(jobs, worktreeJob{
+			workspaceID: change.WorkspaceID,
+			alias:       repoEntry.Alias,
+			storeKey:    repoEntry.RepoKey,
+			run: journaledWorktreeAdd(rootDir, opts.Journal, change.WorkspaceID, repoEntry.Alias, repoEntry.RepoKey, repoEntry.Branch, func(ctx context.Context, step func(text string)) (worktreeJobResult, error) {
+				if err := ensureWorkspace(ctx, step); err != nil {
+					return worktreeJobResult{}, err
+				}
+				logStep(step, worktreeAddStep(change.WorkspaceID, repoEntry.Alias, qualify))
+				if review {
+					return worktreeJobResult{}, applyReviewRepoAdd(ctx, rootDir, change.WorkspaceID, repoEntry)
+				}
+				_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoEntry.RepoKey, repoEntry.Alias, repoEntry.Branch, repoEntry.BaseRef, fetch)
+				if err != nil {
+					return worktreeJobResult{}, err
+				}
+				return worktreeJobResult{createdBranch: createdBranch, baseBranch: baseBranch}, nil
+			}),
+		})
+	}
+	return jobs, nil
 }
 
 func applyReviewRepoAdd(ctx context.Context, rootDir, workspaceID string, repoEntry manifest.Repo) error {
@@ -136,7 +256,7 @@ func applyReviewRepoAdd(ctx context.Context, rootDir, workspaceID string, repoEn
 	if _, ok, err := gitcmd.ShowRef(ctx, store.StorePath, remoteRef); err != nil {
 		return err
 	} else if !ok {
-		gitcmd.Logf("git fetch origin %s", branch)
+		gitcmd.Logf(ctx, "git fetch origin %s", branch)
 		if _, err := gitcmd.Run(ctx, []string{"fetch", "origin", branch}, gitcmd.Options{Dir: store.StorePath}); err != nil {
 			return err
 		}
@@ -151,13 +271,17 @@ func applyReviewRepoAdd(ctx context.Context, rootDir, workspaceID string, repoEn
 	return err
 }
 
-func applyRepoRemovals(ctx context.Context, rootDir string, change manifestplan.WorkspaceChange, opts Options) error {
+func applyRepoRemovals(ctx context.Context, rootDir string, change manifestplan.WorkspaceChange, opts Options, hooks *hookRunner) error {
 	for _, repoChange := range change.Repos {
 		switch repoChange.Kind {
 		case manifestplan.RepoRemove, manifestplan.RepoUpdate:
 			if canRenameRepoBranchInPlace(repoChange) {
 				continue
 			}
+			if _, done := opts.Journal.completed(JournalRemoveWorktree, change.WorkspaceID, repoChange.Alias); done {
+				continue
+			}
+			hooks.run(ctx, HookPreRemove, change.WorkspaceID, repoChange.Alias)
 			logStep(opts.Step, fmt.Sprintf("worktree remove %s", repoChange.Alias))
 			if err := remove_repo.RemoveRepo(ctx, rootDir, change.WorkspaceID, repoChange.Alias, remove_repo.Options{
 				AllowDirty:       opts.AllowDirty,
@@ -165,16 +289,64 @@ func applyRepoRemovals(ctx context.Context, rootDir string, change manifestplan.
 			}); err != nil {
 				return err
 			}
+			if err := opts.Journal.record(JournalStep{Kind: JournalRemoveWorktree, WorkspaceID: change.WorkspaceID, Alias: repoChange.Alias}); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
-func applyRepoBranchRenames(ctx context.Context, rootDir string, change manifestplan.WorkspaceChange, step func(text string)) error {
+// applyRepoMoves renames repo aliases in place with `git worktree move`.
+func applyRepoMoves(ctx context.Context, rootDir string, change manifestplan.WorkspaceChange, opts Options) error {
+	for _, repoChange := range change.Repos {
+		if repoChange.Kind != manifestplan.RepoMove {
+			continue
+		}
+		if _, done := opts.Journal.completed(JournalMoveWorktree, change.WorkspaceID, repoChange.Alias); done {
+			continue
+		}
+		logStep(opts.Step, fmt.Sprintf("worktree move %s -> %s", repoChange.FromAlias, repoChange.Alias))
+		if err := workspace.MoveRepo(ctx, rootDir, change.WorkspaceID, repoChange.FromAlias, repoChange.Alias); err != nil {
+			return err
+		}
+		if err := renamePullRequest(rootDir, change.WorkspaceID, repoChange.FromAlias, repoChange.Alias); err != nil {
+			return err
+		}
+		if err := opts.Journal.record(JournalStep{
+			Kind:        JournalMoveWorktree,
+			WorkspaceID: change.WorkspaceID,
+			Alias:       repoChange.Alias,
+			FromAlias:   repoChange.FromAlias,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renamePullRequest re-keys the PR recorded in workspace metadata (`gion pr create`) from
+// one repo alias to another. It is a no-op when none is recorded, so it can be re-run.
+func renamePullRequest(rootDir, workspaceID, fromAlias, toAlias string) error {
+	wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+	meta, err := workspace.LoadMetadata(wsDir)
+	if err != nil {
+		return err
+	}
+	if !meta.RenamePullRequest(fromAlias, toAlias) {
+		return nil
+	}
+	return workspace.SaveMetadata(wsDir, meta)
+}
+
+func applyRepoBranchRenames(ctx context.Context, rootDir string, change manifestplan.WorkspaceChange, opts Options) error {
 	for _, repoChange := range change.Repos {
 		if !canRenameRepoBranchInPlace(repoChange) {
 			continue
 		}
+		if _, done := opts.Journal.completed(JournalRenameBranch, change.WorkspaceID, repoChange.Alias); done {
+			continue
+		}
 		worktreePath := workspace.WorktreePath(rootDir, change.WorkspaceID, repoChange.Alias)
 
 		currentBranch, err := gitcmd.RevParse(ctx, worktreePath, "--abbrev-ref", "HEAD")
@@ -185,53 +357,146 @@ func applyRepoBranchRenames(ctx context.Context, rootDir string, change manifest
 			return fmt.Errorf("cannot rename branch: repo %q is on %q, want %q", repoChange.Alias, currentBranch, repoChange.FromBranch)
 		}
 
-		logStep(step, fmt.Sprintf("branch rename %s", repoChange.Alias))
+		logStep(opts.Step, fmt.Sprintf("branch rename %s", repoChange.Alias))
 		if err := gitcmd.BranchMove(ctx, worktreePath, repoChange.FromBranch, repoChange.ToBranch); err != nil {
 			return err
 		}
+		if err := opts.Journal.record(JournalStep{
+			Kind:        JournalRenameBranch,
+			WorkspaceID: change.WorkspaceID,
+			Alias:       repoChange.Alias,
+			Branch:      strings.TrimSpace(repoChange.ToBranch),
+			FromBranch:  strings.TrimSpace(repoChange.FromBranch),
+		}); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func applyRepoAdds(ctx context.Context, rootDir string, desired manifest.File, change manifestplan.WorkspaceChange, opts Options) error {
-	baseBranchToRecord := ""
-	baseBranchMixed := false
-	fetch := true
-	if opts.PrefetchOK {
-		fetch = false
-	}
+func repoAddJobs(rootDir string, desired manifest.File, change manifestplan.WorkspaceChange, opts Options, qualify bool) []worktreeJob {
+	fetch := !opts.PrefetchOK
+	var jobs []worktreeJob
 	for _, repoChange := range change.Repos {
 		switch repoChange.Kind {
 		case manifestplan.RepoAdd:
-			logStep(opts.Step, fmt.Sprintf("worktree add %s", repoChange.Alias))
-			baseRef := desiredBaseRef(desired, change.WorkspaceID, repoChange.Alias)
-			_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoChange.ToRepo, repoChange.Alias, repoChange.ToBranch, baseRef, fetch)
-			if err != nil {
-				return err
-			}
-			if createdBranch {
-				baseBranchToRecord, baseBranchMixed = updateBaseBranchCandidate(baseBranchToRecord, baseBranchMixed, baseBranch)
-			}
 		case manifestplan.RepoUpdate:
 			if canRenameRepoBranchInPlace(repoChange) {
 				continue
 			}
-			logStep(opts.Step, fmt.Sprintf("worktree add %s", repoChange.Alias))
-			baseRef := desiredBaseRef(desired, change.WorkspaceID, repoChange.Alias)
-			_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoChange.ToRepo, repoChange.Alias, repoChange.ToBranch, baseRef, fetch)
-			if err != nil {
-				return err
-			}
-			if createdBranch {
-				baseBranchToRecord, baseBranchMixed = updateBaseBranchCandidate(baseBranchToRecord, baseBranchMixed, baseBranch)
+		default:
+			continue
+		}
+		repoChange := repoChange
+		jobs = append(jobs, worktreeJob{
+			workspaceID: change.WorkspaceID,
+			alias:       repoChange.Alias,
+			storeKey:    repoChange.ToRepo,
+			run: journaledWorktreeAdd(rootDir, opts.Journal, change.WorkspaceID, repoChange.Alias, repoChange.ToRepo, repoChange.ToBranch, func(ctx context.Context, step func(text string)) (worktreeJobResult, error) {
+				logStep(step, worktreeAddStep(change.WorkspaceID, repoChange.Alias, qualify))
+				baseRef := desiredBaseRef(desired, change.WorkspaceID, repoChange.Alias)
+				_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoChange.ToRepo, repoChange.Alias, repoChange.ToBranch, baseRef, fetch)
+				if err != nil {
+					return worktreeJobResult{}, err
+				}
+				return worktreeJobResult{createdBranch: createdBranch, baseBranch: baseBranch}, nil
+			}),
+		})
+	}
+	return jobs
+}
+
+// journaledWorktreeAdd wraps a worktree job so it is skipped when the journal already
+// holds it, and recorded (with the branch it created, if any) when it succeeds.
+func journaledWorktreeAdd(rootDir string, journal *Journal, workspaceID, alias, repoKey, branch string, run func(ctx context.Context, step func(text string)) (worktreeJobResult, error)) func(ctx context.Context, step func(text string)) (worktreeJobResult, error) {
+	return func(ctx context.Context, step func(text string)) (worktreeJobResult, error) {
+		if done, ok := journal.completed(JournalAddWorktree, workspaceID, alias); ok {
+			return worktreeJobResult{createdBranch: done.CreatedBranch, baseBranch: done.BaseBranch, resumed: true}, nil
+		}
+		entry := JournalStep{
+			Kind:        JournalAddWorktree,
+			WorkspaceID: workspaceID,
+			Alias:       alias,
+			RepoKey:     repoKey,
+			Branch:      strings.TrimSpace(branch),
+		}
+		if journal != nil && worktreeOnBranch(ctx, workspace.WorktreePath(rootDir, workspaceID, alias), entry.Branch) {
+			// An interrupted apply created the worktree but did not journal it. Whether it
+			// created the branch is unknown, so rollback keeps the branch.
+			return worktreeJobResult{resumed: true}, journal.record(entry)
+		}
+		result, err := run(ctx, step)
+		if err != nil {
+			return result, err
+		}
+		entry.CreatedBranch = result.createdBranch
+		entry.BaseBranch = result.baseBranch
+		if entry.CreatedBranch {
+			// Not bound to ctx: a canceled apply must still journal the worktree it created.
+			// Without Head, rollback keeps the branch.
+			if head, err := gitcmd.RevParse(context.WithoutCancel(ctx), workspace.WorktreePath(rootDir, workspaceID, alias), "HEAD"); err == nil {
+				entry.Head = head
 			}
 		}
+		return result, journal.record(entry)
 	}
-	if baseBranchMixed {
-		baseBranchToRecord = ""
+}
+
+// worktreeOnBranch reports whether worktreePath is an existing worktree with branch checked out.
+func worktreeOnBranch(ctx context.Context, worktreePath, branch string) bool {
+	if branch == "" {
+		return false
 	}
-	if err := recordBaseBranchIfMissing(rootDir, change.WorkspaceID, baseBranchToRecord); err != nil {
-		return err
+	if exists, err := paths.DirExists(worktreePath); err != nil || !exists {
+		return false
+	}
+	head, err := gitcmd.RevParse(ctx, worktreePath, "--abbrev-ref", "HEAD")
+	return err == nil && head == branch
+}
+
+// worktreeAddStep labels a worktree add. Concurrent output is not grouped under a
+// "create workspace" step, so the workspace ID is included.
+func worktreeAddStep(workspaceID, alias string, qualify bool) string {
+	if qualify {
+		return fmt.Sprintf("worktree add %s/%s", workspaceID, alias)
+	}
+	return fmt.Sprintf("worktree add %s", alias)
+}
+
+// recordBaseBranches records the base branch of workspaces whose worktrees were all created.
+func recordBaseBranches(rootDir string, jobs []worktreeJob, results []worktreeJobResult) error {
+	type candidate struct {
+		value    string
+		mixed    bool
+		complete bool
+	}
+	byWorkspace := map[string]*candidate{}
+	var order []string
+	for i, job := range jobs {
+		c, ok := byWorkspace[job.workspaceID]
+		if !ok {
+			c = &candidate{complete: true}
+			byWorkspace[job.workspaceID] = c
+			order = append(order, job.workspaceID)
+		}
+		if !results[i].done {
+			c.complete = false
+			continue
+		}
+		if results[i].createdBranch {
+			c.value, c.mixed = updateBaseBranchCandidate(c.value, c.mixed, results[i].baseBranch)
+		}
+	}
+	for _, workspaceID := range order {
+		c := byWorkspace[workspaceID]
+		if !c.complete || c.mixed {
+			// Workspace-level base_branch can't represent multiple different bases across repos.
+			// Keep it empty so `gion import` doesn't inject an incorrect base_ref into every repo.
+			continue
+		}
+		if err := recordBaseBranchIfMissing(rootDir, workspaceID, c.value); err != nil {
+			return err
+		}
 	}
 	return nil
 }
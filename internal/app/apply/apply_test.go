@@ -1,6 +1,13 @@
 package apply
 
-import "testing"
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
 
 func TestUpdateBaseBranchCandidate(t *testing.T) {
 	t.Parallel()
@@ -65,3 +72,45 @@ func TestUpdateBaseBranchCandidate(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyMetadataChanges(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	wsDir := workspace.WorkspaceDir(rootDir, "WS-1")
+	if err := workspace.SaveMetadata(wsDir, workspace.Metadata{Description: "old", Mode: workspace.MetadataModeRepo, BaseBranch: "origin/main"}); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+	plan := manifestplan.Result{Changes: []manifestplan.WorkspaceChange{{
+		Kind:        manifestplan.WorkspaceMetadata,
+		WorkspaceID: "WS-1",
+		Metadata: []manifestplan.MetadataChange{
+			{Field: manifestplan.MetadataDescription, From: "old", To: "new"},
+			{Field: manifestplan.MetadataBaseRef, From: "origin/main", To: ""},
+		},
+	}}}
+	journal := &Journal{path: JournalPath(rootDir), Steps: []JournalStep{}}
+
+	if err := applyMetadataChanges(rootDir, plan, Options{Journal: journal}); err != nil {
+		t.Fatalf("apply metadata: %v", err)
+	}
+	meta, err := workspace.LoadMetadata(wsDir)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	want := workspace.Metadata{Description: "new", Mode: workspace.MetadataModeRepo}
+	if !reflect.DeepEqual(meta, want) {
+		t.Fatalf("metadata: got %+v, want %+v", meta, want)
+	}
+
+	if _, err := Rollback(context.Background(), rootDir, journal, nil); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	meta, err = workspace.LoadMetadata(wsDir)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if meta.Description != "old" || meta.BaseBranch != "origin/main" {
+		t.Fatalf("rollback should restore previous metadata, got %+v", meta)
+	}
+}
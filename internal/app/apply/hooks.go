@@ -0,0 +1,118 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/hookcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// HookEvent names a lifecycle hook declared under hooks in gion.yaml.
+type HookEvent string
+
+const (
+	HookPostCreate HookEvent = "post_create"
+	HookPreRemove  HookEvent = "pre_remove"
+	HookPostApply  HookEvent = "post_apply"
+)
+
+// HookFailure is a hook command that exited non-zero or could not be started.
+type HookFailure struct {
+	Event       HookEvent
+	WorkspaceID string
+	Alias       string
+	Err         error
+}
+
+func (f HookFailure) String() string {
+	return fmt.Sprintf("%s %s/%s: %v", f.Event, f.WorkspaceID, f.Alias, f.Err)
+}
+
+// HookError reports failed hooks. Hook failures never stop an apply: Apply returns
+// HookError only after every change was applied, so the filesystem matches the plan.
+type HookError struct {
+	Failures []HookFailure
+}
+
+func (e *HookError) Error() string {
+	if len(e.Failures) == 1 {
+		return fmt.Sprintf("hook failed: %s", e.Failures[0])
+	}
+	return fmt.Sprintf("%d hooks failed", len(e.Failures))
+}
+
+// hookRunner runs the hooks of the workspaces in a plan and collects their failures.
+// It is used from the goroutine running Apply only.
+type hookRunner struct {
+	rootDir  string
+	plan     manifestplan.Result
+	step     func(text string)
+	failures []HookFailure
+}
+
+// hooks resolves the hooks of a workspace. Removed workspaces are no longer in the
+// desired manifest; their preset (recorded in the workspace metadata) still applies.
+func (r *hookRunner) hooks(workspaceID string) manifest.Hooks {
+	ws, ok := r.plan.Desired.Workspaces[workspaceID]
+	if !ok {
+		ws = r.plan.Actual.Workspaces[workspaceID]
+	}
+	return manifest.WorkspaceHooks(r.plan.Desired, ws)
+}
+
+// run runs the commands of one hook in the worktree of alias, stopping at the first
+// failing command. Worktrees that do not exist are skipped.
+func (r *hookRunner) run(ctx context.Context, event HookEvent, workspaceID, alias string) {
+	hooks := r.hooks(workspaceID)
+	var commands []string
+	switch event {
+	case HookPostCreate:
+		commands = hooks.PostCreate
+	case HookPreRemove:
+		commands = hooks.PreRemove
+	case HookPostApply:
+		commands = hooks.PostApply
+	}
+	if len(commands) == 0 || alias == "" {
+		return
+	}
+	dir := workspace.WorktreePath(r.rootDir, workspaceID, alias)
+	if exists, err := paths.DirExists(dir); err != nil || !exists {
+		return
+	}
+	logStep(r.step, fmt.Sprintf("hook %s %s/%s", event, workspaceID, alias))
+	env := []string{
+		"GION_WORKSPACE_ID=" + workspaceID,
+		"GION_REPO_ALIAS=" + alias,
+		"GION_ROOT=" + r.rootDir,
+	}
+	for _, command := range commands {
+		if _, err := hookcmd.Run(ctx, dir, command, env); err != nil {
+			r.failures = append(r.failures, HookFailure{Event: event, WorkspaceID: workspaceID, Alias: alias, Err: err})
+			return
+		}
+	}
+}
+
+// runPostApply runs post_apply in every repo of the workspaces the plan added or changed.
+func (r *hookRunner) runPostApply(ctx context.Context) {
+	for _, change := range r.plan.Changes {
+		if change.Kind == manifestplan.WorkspaceRemove {
+			continue
+		}
+		for _, repoEntry := range r.plan.Desired.Workspaces[change.WorkspaceID].Repos {
+			r.run(ctx, HookPostApply, change.WorkspaceID, repoEntry.Alias)
+		}
+	}
+}
+
+func (r *hookRunner) err() error {
+	if len(r.failures) == 0 {
+		return nil
+	}
+	return &HookError{Failures: r.failures}
+}
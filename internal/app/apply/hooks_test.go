@@ -0,0 +1,65 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestHookRunner(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	worktree := workspace.WorktreePath(rootDir, "WS-1", "app")
+	if err := os.MkdirAll(worktree, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	plan := manifestplan.Result{
+		Desired: manifest.File{
+			Presets: map[string]manifest.Preset{
+				"web": {Repos: []string{"example.com/org/app.git"}, Hooks: manifest.Hooks{
+					PostCreate: []string{`echo "preset $GION_WORKSPACE_ID $GION_REPO_ALIAS $GION_ROOT" >> hooks.log`},
+				}},
+			},
+			Workspaces: map[string]manifest.Workspace{
+				"WS-1": {PresetName: "web", Hooks: manifest.Hooks{
+					PostCreate: []string{"echo workspace >> hooks.log"},
+					PostApply:  []string{"exit 3", "echo skipped >> hooks.log"},
+				}, Repos: []manifest.Repo{{Alias: "app"}, {Alias: "missing"}}},
+			},
+		},
+		Changes: []manifestplan.WorkspaceChange{{Kind: manifestplan.WorkspaceAdd, WorkspaceID: "WS-1"}},
+	}
+	var steps []string
+	runner := &hookRunner{rootDir: rootDir, plan: plan, step: func(text string) { steps = append(steps, text) }}
+
+	runner.run(context.Background(), HookPostCreate, "WS-1", "app")
+	runner.runPostApply(context.Background())
+
+	data, err := os.ReadFile(filepath.Join(worktree, "hooks.log"))
+	if err != nil {
+		t.Fatalf("read hooks.log: %v", err)
+	}
+	want := "preset WS-1 app " + rootDir + "\nworkspace\n"
+	if string(data) != want {
+		t.Fatalf("hooks.log: got %q, want %q", data, want)
+	}
+	if strings.Join(steps, "|") != "hook post_create WS-1/app|hook post_apply WS-1/app" {
+		t.Fatalf("unexpected steps: %v", steps)
+	}
+
+	var hookErr *HookError
+	if err := runner.err(); !errors.As(err, &hookErr) {
+		t.Fatalf("expected HookError, got %v", err)
+	}
+	if len(hookErr.Failures) != 1 || hookErr.Failures[0].Event != HookPostApply || hookErr.Failures[0].Alias != "app" {
+		t.Fatalf("unexpected failures: %+v", hookErr.Failures)
+	}
+}
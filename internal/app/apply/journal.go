@@ -0,0 +1,256 @@
+package apply
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+const journalFileName = "apply-journal.json"
+
+const (
+	JournalStatusRunning = "running"
+	JournalStatusFailed  = "failed"
+)
+
+type JournalStepKind string
+
+const (
+	JournalRemoveWorkspace JournalStepKind = "remove_workspace"
+	JournalRemoveWorktree  JournalStepKind = "remove_worktree"
+	JournalRenameBranch    JournalStepKind = "rename_branch"
+	JournalCreateWorkspace JournalStepKind = "create_workspace"
+	JournalAddWorktree     JournalStepKind = "add_worktree"
+	JournalUpdateMetadata  JournalStepKind = "update_metadata"
+	JournalMoveWorkspace   JournalStepKind = "move_workspace"
+	JournalMoveWorktree    JournalStepKind = "move_worktree"
+)
+
+// JournalStep is one completed apply step.
+type JournalStep struct {
+	Kind        JournalStepKind `json:"kind"`
+	WorkspaceID string          `json:"workspace_id"`
+	Alias       string          `json:"alias,omitempty"`
+	RepoKey     string          `json:"repo_key,omitempty"`
+	Branch      string          `json:"branch,omitempty"`
+	FromBranch  string          `json:"from_branch,omitempty"`
+	// FromWorkspaceID is the previous ID of a move_workspace step.
+	FromWorkspaceID string `json:"from_workspace_id,omitempty"`
+	// FromAlias is the previous alias of a move_worktree step.
+	FromAlias string `json:"from_alias,omitempty"`
+	// CreatedBranch is set when the worktree add created a new branch from a base ref;
+	// Head is the commit it pointed at, so rollback only deletes it if it did not move.
+	CreatedBranch bool   `json:"created_branch,omitempty"`
+	BaseBranch    string `json:"base_branch,omitempty"`
+	Head          string `json:"head,omitempty"`
+	// Metadata is the workspace metadata before an update_metadata step.
+	Metadata *workspace.Metadata `json:"metadata,omitempty"`
+}
+
+// Reversible reports whether rollback can undo the step. Removals discard worktrees and
+// cannot be restored.
+func (s JournalStep) Reversible() bool {
+	switch s.Kind {
+	case JournalRemoveWorkspace, JournalRemoveWorktree:
+		return false
+	default:
+		return true
+	}
+}
+
+func (s JournalStep) String() string {
+	target := s.WorkspaceID
+	if s.Alias != "" {
+		target += "/" + s.Alias
+	}
+	switch s.Kind {
+	case JournalRemoveWorkspace:
+		return fmt.Sprintf("remove workspace %s", target)
+	case JournalRemoveWorktree:
+		return fmt.Sprintf("worktree remove %s", target)
+	case JournalRenameBranch:
+		return fmt.Sprintf("branch rename %s (%s -> %s)", target, s.FromBranch, s.Branch)
+	case JournalCreateWorkspace:
+		return fmt.Sprintf("create workspace %s", target)
+	case JournalAddWorktree:
+		return fmt.Sprintf("worktree add %s", target)
+	case JournalUpdateMetadata:
+		return fmt.Sprintf("update metadata %s", target)
+	case JournalMoveWorktree:
+		return fmt.Sprintf("worktree move %s/%s -> %s", s.WorkspaceID, s.FromAlias, s.Alias)
+	case JournalMoveWorkspace:
+		return fmt.Sprintf("move workspace %s -> %s", s.FromWorkspaceID, target)
+	default:
+		return fmt.Sprintf("%s %s", s.Kind, target)
+	}
+}
+
+func (s JournalStep) matches(kind JournalStepKind, workspaceID, alias string) bool {
+	return s.Kind == kind && s.WorkspaceID == workspaceID && s.Alias == alias
+}
+
+// Journal records the progress of an apply under GION_ROOT/.gion so an interrupted run
+// can be resumed (`gion apply --resume`) or undone (`gion apply --rollback`).
+type Journal struct {
+	mu   sync.Mutex
+	path string
+
+	StartedAt time.Time                      `json:"started_at"`
+	Status    string                         `json:"status"`
+	Error     string                         `json:"error,omitempty"`
+	Targets   []string                       `json:"targets,omitempty"`
+	Skipped   int                            `json:"skipped,omitempty"`
+	Desired   manifest.File                  `json:"desired"`
+	Actual    manifest.File                  `json:"actual"`
+	Changes   []manifestplan.WorkspaceChange `json:"changes"`
+	Steps     []JournalStep                  `json:"steps"`
+}
+
+func JournalPath(rootDir string) string {
+	return filepath.Join(paths.StateDir(rootDir), journalFileName)
+}
+
+// StartJournal writes a new journal for plan. It fails if a previous apply left one behind.
+func StartJournal(rootDir string, plan manifestplan.Result, targets []string, skipped int) (*Journal, error) {
+	path := JournalPath(rootDir)
+	if exists, err := paths.FileExists(path); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, fmt.Errorf("a previous apply did not finish (%s); run gion apply --resume or gion apply --rollback", path)
+	}
+	journal := &Journal{
+		path:      path,
+		StartedAt: time.Now().UTC(),
+		Status:    JournalStatusRunning,
+		Targets:   targets,
+		Skipped:   skipped,
+		Desired:   plan.Desired,
+		Actual:    plan.Actual,
+		Changes:   plan.Changes,
+		Steps:     []JournalStep{},
+	}
+	if err := journal.save(); err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// LoadJournal returns the journal left by an unfinished apply, if any.
+func LoadJournal(rootDir string) (*Journal, bool, error) {
+	path := JournalPath(rootDir)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("read apply journal: %w", err)
+	}
+	journal := &Journal{path: path}
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, false, fmt.Errorf("parse apply journal %s: %w", path, err)
+	}
+	return journal, true, nil
+}
+
+// Plan returns the plan the journaled apply was started with.
+func (j *Journal) Plan() manifestplan.Result {
+	return manifestplan.Result{
+		Desired: j.Desired,
+		Actual:  j.Actual,
+		Changes: j.Changes,
+	}
+}
+
+// Fail marks the journal as failed so the next run knows it must resume or roll back.
+func (j *Journal) Fail(cause error) error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Status = JournalStatusFailed
+	if cause != nil {
+		j.Error = cause.Error()
+	}
+	return j.saveLocked()
+}
+
+// Remove deletes the journal once the apply (or rollback) completed.
+func (j *Journal) Remove() error {
+	if j == nil {
+		return nil
+	}
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove apply journal: %w", err)
+	}
+	return nil
+}
+
+func (j *Journal) completed(kind JournalStepKind, workspaceID, alias string) (JournalStep, bool) {
+	if j == nil {
+		return JournalStep{}, false
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, step := range j.Steps {
+		if step.matches(kind, workspaceID, alias) {
+			return step, true
+		}
+	}
+	return JournalStep{}, false
+}
+
+func (j *Journal) record(step JournalStep) error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Steps = append(j.Steps, step)
+	return j.saveLocked()
+}
+
+func (j *Journal) forget(step JournalStep) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for i := range j.Steps {
+		if j.Steps[i].matches(step.Kind, step.WorkspaceID, step.Alias) {
+			j.Steps = append(j.Steps[:i], j.Steps[i+1:]...)
+			break
+		}
+	}
+	return j.saveLocked()
+}
+
+func (j *Journal) save() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.saveLocked()
+}
+
+func (j *Journal) saveLocked() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal apply journal: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o750); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write apply journal: %w", err)
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return fmt.Errorf("write apply journal: %w", err)
+	}
+	return nil
+}
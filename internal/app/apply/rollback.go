@@ -0,0 +1,130 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/app/remove_repo"
+	"github.com/tasuku43/gion/internal/app/rm"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+type RollbackResult struct {
+	Reverted []JournalStep
+	// Irreversible lists removals from the failed run; their worktrees are gone.
+	Irreversible []JournalStep
+}
+
+// Rollback undoes the reversible steps recorded in the journal, newest first:
+// created worktrees (refusing if they have local changes), branches created by those
+// worktrees (only when the branch did not move), new workspace directories (only when
+// empty), in-place branch renames, worktree and workspace moves, and metadata updates.
+// Undone steps are dropped from the journal as they succeed, so a failed rollback can be
+// retried.
+func Rollback(ctx context.Context, rootDir string, journal *Journal, step func(text string)) (RollbackResult, error) {
+	var result RollbackResult
+	var errs []error
+	steps := append([]JournalStep(nil), journal.Steps...)
+	for i := len(steps) - 1; i >= 0; i-- {
+		entry := steps[i]
+		if !entry.Reversible() {
+			result.Irreversible = append(result.Irreversible, entry)
+			continue
+		}
+		logStep(step, fmt.Sprintf("undo %s", entry))
+		if err := undoStep(ctx, rootDir, entry); err != nil {
+			errs = append(errs, fmt.Errorf("undo %s: %w", entry, err))
+			continue
+		}
+		if err := journal.forget(entry); err != nil {
+			return result, err
+		}
+		result.Reverted = append(result.Reverted, entry)
+	}
+	return result, errors.Join(errs...)
+}
+
+func undoStep(ctx context.Context, rootDir string, entry JournalStep) error {
+	switch entry.Kind {
+	case JournalAddWorktree:
+		return undoWorktreeAdd(ctx, rootDir, entry)
+	case JournalCreateWorkspace:
+		return undoWorkspaceCreate(ctx, rootDir, entry)
+	case JournalUpdateMetadata:
+		var previous workspace.Metadata
+		if entry.Metadata != nil {
+			previous = *entry.Metadata
+		}
+		return workspace.SaveMetadata(workspace.WorkspaceDir(rootDir, entry.WorkspaceID), previous)
+	case JournalRenameBranch:
+		worktreePath := workspace.WorktreePath(rootDir, entry.WorkspaceID, entry.Alias)
+		current, err := gitcmd.RevParse(ctx, worktreePath, "--abbrev-ref", "HEAD")
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(current) != entry.Branch {
+			return fmt.Errorf("repo %q is on %q, want %q", entry.Alias, current, entry.Branch)
+		}
+		return gitcmd.BranchMove(ctx, worktreePath, entry.Branch, entry.FromBranch)
+	case JournalMoveWorktree:
+		if err := workspace.MoveRepo(ctx, rootDir, entry.WorkspaceID, entry.Alias, entry.FromAlias); err != nil {
+			return err
+		}
+		return renamePullRequest(rootDir, entry.WorkspaceID, entry.Alias, entry.FromAlias)
+	case JournalMoveWorkspace:
+		return workspace.Move(ctx, rootDir, entry.WorkspaceID, entry.FromWorkspaceID)
+	default:
+		return fmt.Errorf("step cannot be undone")
+	}
+}
+
+func undoWorktreeAdd(ctx context.Context, rootDir string, entry JournalStep) error {
+	worktreePath := workspace.WorktreePath(rootDir, entry.WorkspaceID, entry.Alias)
+	exists, err := paths.DirExists(worktreePath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := remove_repo.RemoveRepo(ctx, rootDir, entry.WorkspaceID, entry.Alias, remove_repo.Options{}); err != nil {
+			return err
+		}
+	}
+	if !entry.CreatedBranch || strings.TrimSpace(entry.Branch) == "" {
+		return nil
+	}
+	store, err := repo.Open(ctx, rootDir, repo.SpecFromKey(entry.RepoKey), false)
+	if err != nil {
+		return err
+	}
+	head, ok, err := gitcmd.ShowRef(ctx, store.StorePath, "refs/heads/"+entry.Branch)
+	if err != nil || !ok {
+		return err
+	}
+	if entry.Head == "" || head != entry.Head {
+		// The branch gained commits (or was reset) after apply created it; keep it.
+		return nil
+	}
+	gitcmd.Logf(ctx, "git branch -D %s", entry.Branch)
+	return gitcmd.BranchDelete(ctx, store.StorePath, entry.Branch)
+}
+
+func undoWorkspaceCreate(ctx context.Context, rootDir string, entry JournalStep) error {
+	wsDir := workspace.WorkspaceDir(rootDir, entry.WorkspaceID)
+	exists, err := paths.DirExists(wsDir)
+	if err != nil || !exists {
+		return err
+	}
+	repos, _, err := workspace.ScanRepos(ctx, wsDir)
+	if err != nil {
+		return err
+	}
+	if len(repos) > 0 {
+		return fmt.Errorf("workspace still has repos")
+	}
+	return rm.Remove(ctx, rootDir, entry.WorkspaceID, false)
+}
@@ -0,0 +1,126 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/tasuku43/gion/internal/infra/output"
+)
+
+// DefaultConcurrency is the number of worktrees created in parallel when Options.Concurrency is unset.
+const DefaultConcurrency = 4
+
+// worktreeJob creates one worktree. Jobs that share a storeKey run one after another:
+// git takes locks in the bare store (worktree admin dir, refs) that do not tolerate
+// concurrent writers, while different stores are independent.
+type worktreeJob struct {
+	workspaceID string
+	alias       string
+	storeKey    string
+	run         func(ctx context.Context, step func(text string)) (worktreeJobResult, error)
+}
+
+type worktreeJobResult struct {
+	done          bool
+	createdBranch bool
+	baseBranch    string
+	// resumed is set when the worktree was created by an earlier, interrupted apply.
+	resumed bool
+}
+
+// runWorktreeJobs runs jobs with at most concurrency stores in flight and returns the
+// results in job order. After the first failure no new jobs are started; jobs already
+// running are allowed to finish so no half-created worktree is left behind.
+func runWorktreeJobs(ctx context.Context, jobs []worktreeJob, concurrency int, step func(text string)) ([]worktreeJobResult, error) {
+	results := make([]worktreeJobResult, len(jobs))
+	if concurrency <= 1 || len(jobs) <= 1 {
+		for i, job := range jobs {
+			result, err := job.run(ctx, step)
+			if err != nil {
+				return results, err
+			}
+			result.done = true
+			results[i] = result
+		}
+		return results, nil
+	}
+
+	groups := groupJobsByStore(jobs)
+	if concurrency > len(groups) {
+		concurrency = len(groups)
+	}
+	queue := make(chan []int)
+	errs := make([]error, len(jobs))
+	var failed sync.Once
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				for _, index := range group {
+					select {
+					case <-stop:
+						continue
+					default:
+					}
+					result, err := runBufferedJob(ctx, jobs[index], step)
+					if err != nil {
+						errs[index] = err
+						failed.Do(func() { close(stop) })
+						continue
+					}
+					result.done = true
+					results[index] = result
+				}
+			}
+		}()
+	}
+dispatch:
+	for _, group := range groups {
+		select {
+		case <-stop:
+			break dispatch
+		case queue <- group:
+		}
+	}
+	close(queue)
+	wg.Wait()
+	return results, errors.Join(errs...)
+}
+
+// runBufferedJob collects the job's step and git output and prints it as one block once
+// the job is done, so concurrent jobs never interleave their lines.
+func runBufferedJob(ctx context.Context, job worktreeJob, step func(text string)) (worktreeJobResult, error) {
+	buf := &output.Buffer{}
+	defer buf.Flush()
+	var jobStep func(text string)
+	if step != nil {
+		jobStep = buf.Step
+	}
+	return job.run(output.WithLogger(ctx, buf), jobStep)
+}
+
+// groupJobsByStore returns job indexes grouped by store, largest groups first so the
+// longest serial chains start early.
+func groupJobsByStore(jobs []worktreeJob) [][]int {
+	byStore := map[string][]int{}
+	var order []string
+	for i, job := range jobs {
+		if _, ok := byStore[job.storeKey]; !ok {
+			order = append(order, job.storeKey)
+		}
+		byStore[job.storeKey] = append(byStore[job.storeKey], i)
+	}
+	groups := make([][]int, 0, len(order))
+	for _, key := range order {
+		groups = append(groups, byStore[key])
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i]) > len(groups[j])
+	})
+	return groups
+}
@@ -0,0 +1,115 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunWorktreeJobs_SerializesPerStoreAndBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight int32
+	var mu sync.Mutex
+	busyStores := map[string]bool{}
+	var overlap atomic.Bool
+
+	newJob := func(store string, index int) worktreeJob {
+		return worktreeJob{
+			workspaceID: "WS",
+			alias:       store,
+			storeKey:    store,
+			run: func(ctx context.Context, step func(text string)) (worktreeJobResult, error) {
+				mu.Lock()
+				if busyStores[store] {
+					overlap.Store(true)
+				}
+				busyStores[store] = true
+				mu.Unlock()
+				current := atomic.AddInt32(&inFlight, 1)
+				for {
+					prev := atomic.LoadInt32(&maxInFlight)
+					if current <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				mu.Lock()
+				busyStores[store] = false
+				mu.Unlock()
+				return worktreeJobResult{baseBranch: store, createdBranch: index%2 == 0}, nil
+			},
+		}
+	}
+	var jobs []worktreeJob
+	for i, store := range []string{"a", "a", "b", "c", "c", "d", "e"} {
+		jobs = append(jobs, newJob(store, i))
+	}
+
+	results, err := runWorktreeJobs(context.Background(), jobs, 2, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if overlap.Load() {
+		t.Fatalf("jobs for the same store ran concurrently")
+	}
+	if got := atomic.LoadInt32(&maxInFlight); got > 2 {
+		t.Fatalf("max in flight: got %d, want <= 2", got)
+	}
+	for i, result := range results {
+		if !result.done || result.baseBranch != jobs[i].storeKey {
+			t.Fatalf("result %d out of order or not done: %+v", i, result)
+		}
+	}
+}
+
+func TestRunWorktreeJobs_StopsAfterFailure(t *testing.T) {
+	t.Parallel()
+
+	var ran atomic.Int32
+	failing := errors.New("boom")
+	var jobs []worktreeJob
+	for i := 0; i < 5; i++ {
+		i := i
+		jobs = append(jobs, worktreeJob{
+			storeKey: "same",
+			run: func(ctx context.Context, step func(text string)) (worktreeJobResult, error) {
+				ran.Add(1)
+				if i == 1 {
+					return worktreeJobResult{}, failing
+				}
+				return worktreeJobResult{}, nil
+			},
+		})
+	}
+	jobs = append(jobs, worktreeJob{storeKey: "other", run: func(ctx context.Context, step func(text string)) (worktreeJobResult, error) {
+		return worktreeJobResult{}, nil
+	}})
+
+	results, err := runWorktreeJobs(context.Background(), jobs, 2, nil)
+	if !errors.Is(err, failing) {
+		t.Fatalf("expected failing error, got %v", err)
+	}
+	if got := ran.Load(); got != 2 {
+		t.Fatalf("jobs after the failure in the same store should not run: ran %d", got)
+	}
+	if !results[0].done || results[1].done || results[2].done {
+		t.Fatalf("unexpected done flags: %+v", results)
+	}
+}
+
+func TestRecordBaseBranches_SkipsIncompleteWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	jobs := []worktreeJob{{workspaceID: "WS-1"}, {workspaceID: "WS-1"}}
+	results := []worktreeJobResult{{done: true, createdBranch: true, baseBranch: "origin/main"}, {}}
+	// WS-1 has no metadata; recording would fail if it were attempted.
+	if err := recordBaseBranches(rootDir, jobs, results); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+}
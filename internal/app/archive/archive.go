@@ -0,0 +1,309 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/gion/internal/app/rm"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+const (
+	recordFileName = "archive.json"
+	recordVersion  = 1
+	// Remote is the remote whose branches are treated as already saved: only commits
+	// missing from it go into the bundle.
+	Remote = "origin"
+)
+
+// Record describes an archived workspace (GION_ROOT/archive/<id>/archive.json).
+type Record struct {
+	Version     int                `json:"version"`
+	WorkspaceID string             `json:"workspace_id"`
+	ArchivedAt  time.Time          `json:"archived_at"`
+	Workspace   manifest.Workspace `json:"workspace"`
+	Metadata    workspace.Metadata `json:"metadata"`
+	Repos       []Repo             `json:"repos"`
+}
+
+// Repo is the archived state of one worktree.
+type Repo struct {
+	Alias    string `json:"alias"`
+	RepoKey  string `json:"repo_key"`
+	Branch   string `json:"branch"`
+	Upstream string `json:"upstream,omitempty"`
+	Head     string `json:"head"`
+	// Commits counts the commits of the branch that are on no origin branch; they are
+	// saved in Bundle.
+	Commits int    `json:"commits"`
+	Bundle  string `json:"bundle,omitempty"`
+	// ChangedFiles counts the uncommitted changes saved in Patch.
+	ChangedFiles int    `json:"changed_files"`
+	Patch        string `json:"patch,omitempty"`
+	// Stashes counts the branch's stash entries; they are not archived, but stay in the
+	// repo store (refs/stash) after the worktree is removed.
+	Stashes int `json:"stashes,omitempty"`
+	// IgnoredFiles counts the ignored files of the worktree (an ignored directory counts
+	// once); they are not archived and are deleted with the worktree.
+	IgnoredFiles int `json:"ignored_files,omitempty"`
+}
+
+// Dir returns the archive directory of a workspace.
+func Dir(rootDir, workspaceID string) string {
+	return filepath.Join(paths.ArchiveRoot(rootDir), workspaceID)
+}
+
+// Load reads the archive record of a workspace.
+func Load(rootDir, workspaceID string) (Record, error) {
+	data, err := os.ReadFile(filepath.Join(Dir(rootDir, workspaceID), recordFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Record{}, fmt.Errorf("archive not found: %s", workspaceID)
+		}
+		return Record{}, err
+	}
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return Record{}, fmt.Errorf("parse %s: %w", recordFileName, err)
+	}
+	if record.Version != recordVersion {
+		return Record{}, fmt.Errorf("unsupported archive version %d: %s", record.Version, workspaceID)
+	}
+	return record, nil
+}
+
+// Plan is what Archive will save for a workspace.
+type Plan struct {
+	WorkspaceID string
+	// InManifest is true when gion.yaml has an entry for the workspace; it is removed.
+	InManifest bool
+	Record     Record
+	Warnings   []error
+}
+
+// Prepare inspects the workspace and plans its archive. Detached worktrees cannot be
+// archived, since there is no branch to restore.
+func Prepare(ctx context.Context, rootDir, workspaceID string) (Plan, error) {
+	wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+	exists, err := paths.DirExists(wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	if !exists {
+		return Plan{}, fmt.Errorf("workspace not found: %s", workspaceID)
+	}
+	if _, err := os.Stat(Dir(rootDir, workspaceID)); err == nil {
+		return Plan{}, fmt.Errorf("archive already exists: %s", Dir(rootDir, workspaceID))
+	}
+
+	repos, warnings, err := workspace.ScanRepos(ctx, wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Alias < repos[j].Alias
+	})
+	meta, err := workspace.LoadMetadata(wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan := Plan{
+		WorkspaceID: workspaceID,
+		Warnings:    warnings,
+		Record: Record{
+			Version:     recordVersion,
+			WorkspaceID: workspaceID,
+			Metadata:    meta,
+		},
+	}
+
+	file, err := manifest.Load(rootDir)
+	switch {
+	case err == nil:
+		plan.Record.Workspace, plan.InManifest = file.Workspaces[workspaceID]
+	case !errors.Is(err, os.ErrNotExist):
+		return Plan{}, err
+	}
+
+	for _, repoEntry := range repos {
+		if strings.TrimSpace(repoEntry.StorePath) == "" || strings.TrimSpace(repoEntry.RepoKey) == "" {
+			return Plan{}, fmt.Errorf("cannot archive %s: not a gion worktree", repoEntry.Alias)
+		}
+		planned, err := planRepo(ctx, repoEntry)
+		if err != nil {
+			return Plan{}, fmt.Errorf("cannot archive %s: %w", repoEntry.Alias, err)
+		}
+		plan.Record.Repos = append(plan.Record.Repos, planned)
+	}
+	if !plan.InManifest {
+		plan.Record.Workspace = workspaceFromRecord(plan.Record)
+	}
+	return plan, nil
+}
+
+func planRepo(ctx context.Context, repoEntry workspace.Repo) (Repo, error) {
+	planned := Repo{Alias: repoEntry.Alias, RepoKey: repoEntry.RepoKey}
+	statusOut, err := gitcmd.StatusPorcelainV2Ignored(ctx, repoEntry.WorktreePath)
+	if err != nil {
+		return Repo{}, err
+	}
+	for _, line := range strings.Split(strings.TrimRight(statusOut, "\n"), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "#":
+			if len(fields) < 3 {
+				continue
+			}
+			switch fields[1] {
+			case "branch.head":
+				planned.Branch = fields[2]
+			case "branch.upstream":
+				planned.Upstream = fields[2]
+			}
+		case fields[0] == "!":
+			planned.IgnoredFiles++
+		default:
+			planned.ChangedFiles++
+		}
+	}
+	switch planned.Branch {
+	case "(detached)":
+		return Repo{}, fmt.Errorf("detached HEAD")
+	case "", "(unknown)":
+		return Repo{}, fmt.Errorf("no commits")
+	}
+	planned.Head, err = gitcmd.RevParse(ctx, repoEntry.WorktreePath, "HEAD")
+	if err != nil {
+		return Repo{}, err
+	}
+	planned.Commits, err = gitcmd.RevListCount(ctx, repoEntry.WorktreePath, "HEAD", "--not", "--remotes="+Remote)
+	if err != nil {
+		return Repo{}, err
+	}
+	subjects, err := gitcmd.StashSubjects(ctx, repoEntry.WorktreePath)
+	if err != nil {
+		return Repo{}, err
+	}
+	planned.Stashes = workspace.CountBranchStashes(subjects, planned.Branch)
+	return planned, nil
+}
+
+// workspaceFromRecord builds the gion.yaml entry of a workspace gion.yaml did not list.
+func workspaceFromRecord(record Record) manifest.Workspace {
+	ws := manifest.Workspace{
+		Description: record.Metadata.Description,
+		Mode:        record.Metadata.Mode,
+		PresetName:  record.Metadata.PresetName,
+		SourceURL:   record.Metadata.SourceURL,
+	}
+	for _, repoEntry := range record.Repos {
+		ws.Repos = append(ws.Repos, manifest.Repo{
+			Alias:   repoEntry.Alias,
+			RepoKey: repoEntry.RepoKey,
+			Branch:  repoEntry.Branch,
+			BaseRef: record.Metadata.BaseBranch,
+		})
+	}
+	return ws
+}
+
+type Options struct {
+	// Step is called before each step.
+	Step func(text string)
+}
+
+// Archive saves the workspace into its archive directory (a bundle of the commits origin
+// lacks and a patch of the uncommitted changes, per repo), then removes the workspace
+// and its gion.yaml entry. Nothing is removed unless the archive was written completely.
+func Archive(ctx context.Context, rootDir string, plan Plan, opts Options) (Record, error) {
+	dir := Dir(rootDir, plan.WorkspaceID)
+	if err := os.MkdirAll(paths.ArchiveRoot(rootDir), 0o750); err != nil {
+		return Record{}, fmt.Errorf("create archive dir: %w", err)
+	}
+	if err := os.Mkdir(dir, 0o750); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return Record{}, fmt.Errorf("archive already exists: %s", dir)
+		}
+		return Record{}, fmt.Errorf("create archive dir: %w", err)
+	}
+	record, err := writeArchive(ctx, rootDir, dir, plan, opts)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return Record{}, err
+	}
+
+	logStep(opts.Step, fmt.Sprintf("remove workspace %s", plan.WorkspaceID))
+	if err := rm.Remove(ctx, rootDir, plan.WorkspaceID, true); err != nil {
+		return record, err
+	}
+	if plan.InManifest {
+		logStep(opts.Step, fmt.Sprintf("remove %s from %s", plan.WorkspaceID, manifest.FileName))
+		file, err := manifest.Load(rootDir)
+		if err != nil {
+			return record, err
+		}
+		delete(file.Workspaces, plan.WorkspaceID)
+		if err := manifest.Save(rootDir, file); err != nil {
+			return record, err
+		}
+	}
+	return record, nil
+}
+
+func writeArchive(ctx context.Context, rootDir, dir string, plan Plan, opts Options) (Record, error) {
+	record := plan.Record
+	record.ArchivedAt = time.Now().UTC()
+	record.Repos = append([]Repo(nil), plan.Record.Repos...)
+	for i := range record.Repos {
+		repoEntry := &record.Repos[i]
+		worktreePath := workspace.WorktreePath(rootDir, plan.WorkspaceID, repoEntry.Alias)
+		logStep(opts.Step, fmt.Sprintf("archive %s", repoEntry.Alias))
+		if repoEntry.Commits > 0 {
+			repoEntry.Bundle = repoEntry.Alias + ".bundle"
+			gitcmd.Logf(ctx, "git bundle create %s refs/heads/%s --not --remotes=%s", repoEntry.Bundle, repoEntry.Branch, Remote)
+			if err := gitcmd.BundleCreate(ctx, worktreePath, filepath.Join(dir, repoEntry.Bundle), "refs/heads/"+repoEntry.Branch, "--not", "--remotes="+Remote); err != nil {
+				return Record{}, fmt.Errorf("archive %s: %w", repoEntry.Alias, err)
+			}
+		}
+		if repoEntry.ChangedFiles > 0 {
+			indexPath := filepath.Join(dir, repoEntry.Alias+".index")
+			gitcmd.Logf(ctx, "git diff --binary HEAD > %s", repoEntry.Alias+".patch")
+			patch, err := gitcmd.DiffUncommitted(ctx, worktreePath, indexPath)
+			_ = os.Remove(indexPath)
+			if err != nil {
+				return Record{}, fmt.Errorf("archive %s: %w", repoEntry.Alias, err)
+			}
+			if patch != "" {
+				repoEntry.Patch = repoEntry.Alias + ".patch"
+				if err := os.WriteFile(filepath.Join(dir, repoEntry.Patch), []byte(patch), 0o600); err != nil {
+					return Record{}, fmt.Errorf("archive %s: %w", repoEntry.Alias, err)
+				}
+			}
+		}
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return Record{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, recordFileName), append(data, '\n'), 0o600); err != nil {
+		return Record{}, err
+	}
+	return record, nil
+}
+
+func logStep(step func(text string), text string) {
+	if step != nil {
+		step(text)
+	}
+}
@@ -0,0 +1,240 @@
+package archive_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/archive"
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestArchiveAndRestore(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo, Description: "parked"}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	added, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true)
+	if err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	entry := manifest.Workspace{
+		Description: "parked",
+		Mode:        workspace.MetadataModeRepo,
+		Repos:       []manifest.Repo{{Alias: "repo", RepoKey: added.RepoKey, Branch: "WS-1"}},
+	}
+	if err := manifest.Save(rootDir, manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{"WS-1": entry}}); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	if err := os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("feature\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, worktreePath, "add", ".")
+	runGit(t, worktreePath, "commit", "-m", "feature")
+	head := runGit(t, worktreePath, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "notes.txt"), []byte("untracked\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	plan, err := archive.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if !plan.InManifest || len(plan.Record.Repos) != 1 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if planned := plan.Record.Repos[0]; planned.Branch != "WS-1" || planned.Head != head || planned.Commits != 1 || planned.ChangedFiles != 2 {
+		t.Fatalf("unexpected repo plan: %+v", planned)
+	}
+	if planned := plan.Record.Repos[0]; planned.Stashes != 0 || planned.IgnoredFiles != 0 {
+		t.Fatalf("expected no stashes or ignored files, got %+v", planned)
+	}
+	if _, err := archive.Archive(ctx, rootDir, plan, archive.Options{}); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if _, err := os.Stat(workspace.WorkspaceDir(rootDir, "WS-1")); !os.IsNotExist(err) {
+		t.Fatalf("workspace still exists: %v", err)
+	}
+	for _, name := range []string{"archive.json", "repo.bundle", "repo.patch"} {
+		if _, err := os.Stat(filepath.Join(archive.Dir(rootDir, "WS-1"), name)); err != nil {
+			t.Fatalf("archive file %s missing: %v", name, err)
+		}
+	}
+	file, err := manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+	if _, ok := file.Workspaces["WS-1"]; ok {
+		t.Fatalf("expected WS-1 to be removed from %s", manifest.FileName)
+	}
+
+	if _, err := archive.Restore(ctx, rootDir, "WS-1", archive.Options{}); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != head {
+		t.Fatalf("expected HEAD %s after restore, got %s", head, got)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "--abbrev-ref", "HEAD"); got != "WS-1" {
+		t.Fatalf("expected branch WS-1, got %s", got)
+	}
+	for name, want := range map[string]string{"README.md": "changed\n", "notes.txt": "untracked\n", "feature.txt": "feature\n"} {
+		data, err := os.ReadFile(filepath.Join(worktreePath, name))
+		if err != nil || string(data) != want {
+			t.Fatalf("expected %s to be %q, got %q (%v)", name, want, data, err)
+		}
+	}
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-1"))
+	if err != nil || meta.Description != "parked" {
+		t.Fatalf("expected metadata to be restored, got %+v (%v)", meta, err)
+	}
+	file, err = manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+	if got := file.Workspaces["WS-1"]; len(got.Repos) != 1 || got.Description != "parked" {
+		t.Fatalf("expected WS-1 back in %s, got %+v", manifest.FileName, got)
+	}
+	if _, err := os.Stat(archive.Dir(rootDir, "WS-1")); !os.IsNotExist(err) {
+		t.Fatalf("expected archive to be deleted: %v", err)
+	}
+}
+
+func TestArchiveReportsStashesAndIgnoredFiles(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	if err := os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("stashed\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, worktreePath, "stash")
+	if err := os.WriteFile(filepath.Join(worktreePath, ".gitignore"), []byte("build/\n*.log\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(worktreePath, "build"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, name := range []string{"build/out.bin", "debug.log"} {
+		if err := os.WriteFile(filepath.Join(worktreePath, name), []byte("ignored\n"), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+
+	plan, err := archive.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if len(plan.Record.Repos) != 1 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if got := plan.Record.Repos[0]; got.Stashes != 1 || got.IgnoredFiles != 2 || got.ChangedFiles != 1 {
+		t.Fatalf("unexpected repo plan: %+v", got)
+	}
+
+	// Archiving goes ahead; the stash survives in the store.
+	if _, err := archive.Archive(ctx, rootDir, plan, archive.Options{}); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	store, err := repo.Open(ctx, rootDir, repoSpec, false)
+	if err != nil {
+		t.Fatalf("repo open: %v", err)
+	}
+	if got := runGit(t, store.StorePath, "log", "-g", "--format=%gs", "refs/stash"); !strings.HasPrefix(got, "WIP on WS-1: ") {
+		t.Fatalf("expected the stash to stay in the store, got %q", got)
+	}
+}
+
+func setupLocalRemoteRepo(t *testing.T, tmp string) string {
+	t.Helper()
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "example.com", "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/example.com/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	return "https://example.com/org/repo.git"
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s failed: %v\nstderr:\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
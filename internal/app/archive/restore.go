@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/app/rm"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// restoreRefPrefix holds bundle heads while a restore runs. It is outside refs/heads so
+// store normalization (which prunes local branches without a worktree) leaves it alone.
+const restoreRefPrefix = "refs/gion/restore/"
+
+// Restore recreates an archived workspace: each branch is recreated at its archived head
+// (from the bundle when origin lacks commits), the uncommitted changes are re-applied,
+// the workspace entry is written back to gion.yaml, and the archive is deleted.
+func Restore(ctx context.Context, rootDir, workspaceID string, opts Options) (Record, error) {
+	record, err := Load(rootDir, workspaceID)
+	if err != nil {
+		return Record{}, err
+	}
+	exists, err := paths.DirExists(workspace.WorkspaceDir(rootDir, workspaceID))
+	if err != nil {
+		return Record{}, err
+	}
+	if exists {
+		return Record{}, fmt.Errorf("workspace already exists: %s", workspaceID)
+	}
+	file, err := manifest.Load(rootDir)
+	if err != nil {
+		return Record{}, err
+	}
+	if _, ok := file.Workspaces[workspaceID]; ok {
+		return Record{}, fmt.Errorf("workspace already exists in %s: %s", manifest.FileName, workspaceID)
+	}
+
+	dir := Dir(rootDir, workspaceID)
+	logStep(opts.Step, fmt.Sprintf("create workspace %s", workspaceID))
+	if _, err := create.CreateWorkspace(ctx, rootDir, workspaceID, record.Metadata); err != nil {
+		return Record{}, err
+	}
+	for _, repoEntry := range record.Repos {
+		logStep(opts.Step, fmt.Sprintf("restore %s", repoEntry.Alias))
+		if err := restoreRepo(ctx, rootDir, dir, workspaceID, repoEntry); err != nil {
+			// Everything is still in the archive; drop the partial workspace so the
+			// restore can be retried.
+			if rmErr := rm.Remove(ctx, rootDir, workspaceID, true); rmErr != nil {
+				err = errors.Join(err, fmt.Errorf("remove partial workspace: %w", rmErr))
+			}
+			return Record{}, fmt.Errorf("restore %s: %w", repoEntry.Alias, err)
+		}
+	}
+
+	logStep(opts.Step, fmt.Sprintf("add %s to %s", workspaceID, manifest.FileName))
+	file.Workspaces[workspaceID] = record.Workspace
+	if err := manifest.Save(rootDir, file); err != nil {
+		return record, err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return record, fmt.Errorf("remove archive: %w", err)
+	}
+	return record, nil
+}
+
+func restoreRepo(ctx context.Context, rootDir, dir, workspaceID string, repoEntry Repo) error {
+	repoSpec := repo.SpecFromKey(repoEntry.RepoKey)
+	store, err := repo.Get(ctx, rootDir, repoSpec)
+	if err != nil {
+		return err
+	}
+	startPoint := repoEntry.Head
+	if repoEntry.Bundle != "" {
+		// The bundle's prerequisites are origin commits; fetch so the store has them.
+		gitcmd.Logf(ctx, "git fetch --prune")
+		if err := repo.Prefetch(ctx, rootDir, repoSpec); err != nil {
+			return err
+		}
+		startPoint = restoreRefPrefix + workspaceID + "/" + repoEntry.Alias
+		refspec := fmt.Sprintf("+refs/heads/%s:%s", repoEntry.Branch, startPoint)
+		bundlePath := filepath.Join(dir, repoEntry.Bundle)
+		gitcmd.Logf(ctx, "git fetch %s %s", bundlePath, refspec)
+		if _, err := gitcmd.Run(ctx, []string{"fetch", bundlePath, refspec}, gitcmd.Options{Dir: store.StorePath}); err != nil {
+			return err
+		}
+		defer func() {
+			_, _ = gitcmd.Run(ctx, []string{"update-ref", "-d", startPoint}, gitcmd.Options{Dir: store.StorePath})
+		}()
+	} else if _, err := gitcmd.RevParse(ctx, store.StorePath, "--verify", "--quiet", repoEntry.Head+"^{commit}"); err != nil {
+		gitcmd.Logf(ctx, "git fetch --prune")
+		if err := repo.Prefetch(ctx, rootDir, repoSpec); err != nil {
+			return err
+		}
+		if _, err := gitcmd.RevParse(ctx, store.StorePath, "--verify", "--quiet", repoEntry.Head+"^{commit}"); err != nil {
+			return fmt.Errorf("commit %s is no longer on %s", repoEntry.Head, Remote)
+		}
+	}
+
+	added, err := workspace.AddWithStartPoint(ctx, rootDir, workspaceID, repoSpec, repoEntry.Alias, repoEntry.Branch, startPoint)
+	if err != nil {
+		return err
+	}
+	if repoEntry.Upstream != "" {
+		if _, ok, err := gitcmd.ShowRef(ctx, store.StorePath, "refs/remotes/"+repoEntry.Upstream); err == nil && ok {
+			if err := gitcmd.BranchSetUpstream(ctx, added.WorktreePath, repoEntry.Branch, repoEntry.Upstream); err != nil {
+				return err
+			}
+		}
+	}
+	if repoEntry.Patch != "" {
+		gitcmd.Logf(ctx, "git apply --binary %s", repoEntry.Patch)
+		if err := gitcmd.Apply(ctx, added.WorktreePath, filepath.Join(dir, repoEntry.Patch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package execcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// Target is one repo worktree a command runs in.
+type Target struct {
+	WorkspaceID string
+	Alias       string
+	Dir         string
+}
+
+func (t Target) String() string {
+	return t.WorkspaceID + "/" + t.Alias
+}
+
+type Selection struct {
+	// WorkspaceIDs are the workspaces to run in; empty selects every workspace.
+	WorkspaceIDs []string
+	// Aliases limits the repos to these aliases; empty selects every repo.
+	Aliases []string
+}
+
+// Targets resolves the worktrees selected by sel, ordered by workspace ID and alias.
+// Scan warnings (e.g. directories that are not worktrees) are returned separately.
+func Targets(ctx context.Context, rootDir string, sel Selection) ([]Target, []error, error) {
+	workspaceIDs := sel.WorkspaceIDs
+	if len(workspaceIDs) == 0 {
+		entries, _, err := workspace.List(rootDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, entry := range entries {
+			workspaceIDs = append(workspaceIDs, entry.WorkspaceID)
+		}
+	}
+	wanted := map[string]bool{}
+	for _, alias := range sel.Aliases {
+		wanted[strings.TrimSpace(alias)] = false
+	}
+
+	var targets []Target
+	var warnings []error
+	for _, workspaceID := range workspaceIDs {
+		wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+		exists, err := paths.DirExists(wsDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			return nil, nil, fmt.Errorf("workspace not found: %s", workspaceID)
+		}
+		repos, repoWarnings, err := workspace.ScanRepos(ctx, wsDir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("workspace %s: %w", workspaceID, err)
+		}
+		for _, warn := range repoWarnings {
+			warnings = append(warnings, fmt.Errorf("workspace %s: %w", workspaceID, warn))
+		}
+		sort.Slice(repos, func(i, j int) bool {
+			return repos[i].Alias < repos[j].Alias
+		})
+		for _, repoEntry := range repos {
+			if len(wanted) > 0 {
+				if _, ok := wanted[repoEntry.Alias]; !ok {
+					continue
+				}
+				wanted[repoEntry.Alias] = true
+			}
+			targets = append(targets, Target{WorkspaceID: workspaceID, Alias: repoEntry.Alias, Dir: repoEntry.WorktreePath})
+		}
+	}
+	var missing []string
+	for alias, found := range wanted {
+		if !found {
+			missing = append(missing, alias)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, nil, fmt.Errorf("repo not found: %s", strings.Join(missing, ", "))
+	}
+	return targets, warnings, nil
+}
+
+type Options struct {
+	// Jobs is how many targets run at once; zero or one runs them one after another.
+	Jobs int
+	// RootDir is exported to the command as GION_ROOT.
+	RootDir string
+	// Output receives every line the command writes to stdout or stderr. Calls are
+	// serialized, so lines of concurrent targets never interleave.
+	Output func(target Target, line string)
+}
+
+type Result struct {
+	Target   Target
+	ExitCode int
+	Duration time.Duration
+	// Err is set when the command failed; ExitCode is -1 when it could not be started.
+	Err error
+}
+
+// Run runs argv in every target and returns the results in target order.
+func Run(ctx context.Context, targets []Target, argv []string, opts Options) []Result {
+	results := make([]Result, len(targets))
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	var outputMu sync.Mutex
+	emit := func(target Target, line string) {
+		if opts.Output == nil {
+			return
+		}
+		outputMu.Lock()
+		defer outputMu.Unlock()
+		opts.Output(target, line)
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs && i < len(targets); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				results[index] = runOne(ctx, targets[index], argv, opts.RootDir, emit)
+			}
+		}()
+	}
+	for i := range targets {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+func runOne(ctx context.Context, target Target, argv []string, rootDir string, emit func(Target, string)) Result {
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = target.Dir
+	cmd.Env = append(os.Environ(),
+		"GION_WORKSPACE_ID="+target.WorkspaceID,
+		"GION_REPO_ALIAS="+target.Alias,
+		"GION_ROOT="+rootDir,
+	)
+	stdout := &lineWriter{emit: func(line string) { emit(target, line) }}
+	stderr := &lineWriter{emit: func(line string) { emit(target, line) }}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	stdout.flush()
+	stderr.flush()
+
+	result := Result{Target: target, Duration: time.Since(start), Err: err}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.ExitCode = -1
+	}
+	return result
+}
+
+// lineWriter forwards complete lines. Stdout and stderr get one each, so a partial line
+// on one stream is never glued to a line of the other.
+type lineWriter struct {
+	emit func(line string)
+	buf  []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(strings.TrimRight(string(w.buf[:i]), "\r"))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *lineWriter) flush() {
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = nil
+	}
+}
@@ -0,0 +1,49 @@
+package execcmd
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	targets := []Target{
+		{WorkspaceID: "WS-1", Alias: "api", Dir: t.TempDir()},
+		{WorkspaceID: "WS-1", Alias: "web", Dir: t.TempDir()},
+	}
+	var lines []string
+	results := Run(context.Background(), targets, []string{"sh", "-c", `printf 'one\ntwo' ; echo "$GION_REPO_ALIAS" >&2; test "$GION_REPO_ALIAS" = api`}, Options{
+		Jobs:    2,
+		RootDir: "/root",
+		Output: func(target Target, line string) {
+			lines = append(lines, target.Alias+": "+line)
+		},
+	})
+
+	if len(results) != 2 || results[0].Target.Alias != "api" || results[1].Target.Alias != "web" {
+		t.Fatalf("results should follow target order, got %+v", results)
+	}
+	if results[0].ExitCode != 0 || results[0].Err != nil {
+		t.Fatalf("api: expected success, got %+v", results[0])
+	}
+	if results[1].ExitCode != 1 || results[1].Err == nil {
+		t.Fatalf("web: expected exit 1, got %+v", results[1])
+	}
+	sort.Strings(lines)
+	want := "api: api|api: one|api: two|web: one|web: two|web: web"
+	if got := strings.Join(lines, "|"); got != want {
+		t.Fatalf("output: got %q, want %q", got, want)
+	}
+}
+
+func TestRunCommandNotFound(t *testing.T) {
+	t.Parallel()
+
+	results := Run(context.Background(), []Target{{WorkspaceID: "WS-1", Alias: "api", Dir: t.TempDir()}}, []string{"gion-no-such-command"}, Options{})
+	if results[0].ExitCode != -1 || results[0].Err == nil {
+		t.Fatalf("expected start failure, got %+v", results[0])
+	}
+}
@@ -47,7 +47,10 @@ func Build(ctx context.Context, rootDir string) (manifest.File, []error, error)
 		Version:    1,
 		Workspaces: map[string]manifest.Workspace{},
 	}
-	if existing, err := manifest.Load(rootDir); err == nil {
+	// Presets and hooks are not reflected on the filesystem; keep them from gion.yaml.
+	var existing manifest.File
+	if loaded, err := manifest.Load(rootDir); err == nil {
+		existing = loaded
 		file.Presets = existing.Presets
 	}
 	var warnings []error
@@ -79,13 +82,21 @@ func Build(ctx context.Context, rootDir string) (manifest.File, []error, error)
 			}
 		}
 
+		// Metadata holds one base_branch; per-repo base refs that gion.yaml sets apart are
+		// kept rather than overwritten with it.
+		mixedBaseRefs := mixedRepoBaseRefs(existing.Workspaces[wsID])
 		repoEntries := make([]manifest.Repo, 0, len(repos))
 		for _, repoEntry := range repos {
+			alias := strings.TrimSpace(repoEntry.Alias)
+			baseRef, ok := mixedBaseRefs[alias]
+			if !ok {
+				baseRef = strings.TrimSpace(meta.BaseBranch)
+			}
 			repoEntries = append(repoEntries, manifest.Repo{
-				Alias:   strings.TrimSpace(repoEntry.Alias),
+				Alias:   alias,
 				RepoKey: strings.TrimSpace(repoEntry.RepoKey),
 				Branch:  strings.TrimSpace(repoEntry.Branch),
-				BaseRef: strings.TrimSpace(meta.BaseBranch),
+				BaseRef: baseRef,
 			})
 		}
 		sort.Slice(repoEntries, func(i, j int) bool {
@@ -97,6 +108,7 @@ func Build(ctx context.Context, rootDir string) (manifest.File, []error, error)
 			Mode:        strings.TrimSpace(meta.Mode),
 			PresetName:  strings.TrimSpace(meta.PresetName),
 			SourceURL:   strings.TrimSpace(meta.SourceURL),
+			Hooks:       existing.Workspaces[wsID].Hooks,
 			Repos:       repoEntries,
 		}
 		file.Workspaces[wsID] = wsEntry
@@ -119,3 +131,21 @@ func Write(rootDir string, file manifest.File, warnings []error) (Result, error)
 func Path(rootDir string) string {
 	return manifest.Path(rootDir)
 }
+
+// mixedRepoBaseRefs returns the base_ref of each repo of ws by alias when the repos do not
+// all share one, and nil when they do.
+func mixedRepoBaseRefs(ws manifest.Workspace) map[string]string {
+	baseRefs := make(map[string]string, len(ws.Repos))
+	mixed := false
+	for i, repoEntry := range ws.Repos {
+		value := strings.TrimSpace(repoEntry.BaseRef)
+		if i > 0 && value != strings.TrimSpace(ws.Repos[0].BaseRef) {
+			mixed = true
+		}
+		baseRefs[strings.TrimSpace(repoEntry.Alias)] = value
+	}
+	if !mixed {
+		return nil
+	}
+	return baseRefs
+}
@@ -24,6 +24,7 @@ type Entry struct {
 	Drift        DriftStatus
 	Risk         workspace.WorkspaceStateKind
 	Description  string
+	SourceURL    string
 	HasWorkspace bool
 }
 
@@ -64,7 +65,7 @@ func List(ctx context.Context, rootDir string) (Result, error) {
 		switch change.Kind {
 		case manifestplan.WorkspaceAdd:
 			statusByWorkspaceID[change.WorkspaceID] = DriftMissing
-		case manifestplan.WorkspaceUpdate:
+		case manifestplan.WorkspaceUpdate, manifestplan.WorkspaceMetadata, manifestplan.WorkspaceRename:
 			statusByWorkspaceID[change.WorkspaceID] = DriftDrift
 		default:
 			// WorkspaceRemove is handled via filesystem scan (extra entries).
@@ -102,6 +103,7 @@ func List(ctx context.Context, rootDir string) (Result, error) {
 			Drift:        drift,
 			Risk:         risk,
 			Description:  strings.TrimSpace(ws.Description),
+			SourceURL:    strings.TrimSpace(ws.SourceURL),
 			HasWorkspace: hasWorkspace,
 		})
 
@@ -158,33 +160,49 @@ func bestEffortWorkspaceRisk(ctx context.Context, rootDir, workspaceID string) (
 // aggregateRiskKind picks a single workspace risk label from repo risks.
 //
 // We keep "unknown" as a special-case top priority (can't confidently assert safety).
-// When unknown is not present, we use a stable order: dirty > diverged > unpushed.
+// When unknown is not present, we use a stable order:
+// in-progress > dirty > diverged > unpublished > unpushed > stashed.
 func aggregateRiskKind(repos []workspace.RepoState) workspace.WorkspaceStateKind {
 	hasDirty := false
 	hasUnknown := false
+	hasInProgress := false
 	hasDiverged := false
+	hasUnpublished := false
 	hasUnpushed := false
+	hasStashed := false
 	for _, repo := range repos {
 		switch repo.Kind {
 		case workspace.RepoStateUnknown:
 			hasUnknown = true
+		case workspace.RepoStateInProgress:
+			hasInProgress = true
 		case workspace.RepoStateDirty:
 			hasDirty = true
 		case workspace.RepoStateDiverged:
 			hasDiverged = true
+		case workspace.RepoStateUnpublished:
+			hasUnpublished = true
 		case workspace.RepoStateUnpushed:
 			hasUnpushed = true
+		case workspace.RepoStateStashed:
+			hasStashed = true
 		}
 	}
 	switch {
 	case hasUnknown:
 		return workspace.WorkspaceStateUnknown
+	case hasInProgress:
+		return workspace.WorkspaceStateInProgress
 	case hasDirty:
 		return workspace.WorkspaceStateDirty
 	case hasDiverged:
 		return workspace.WorkspaceStateDiverged
+	case hasUnpublished:
+		return workspace.WorkspaceStateUnpublished
 	case hasUnpushed:
 		return workspace.WorkspaceStateUnpushed
+	case hasStashed:
+		return workspace.WorkspaceStateStashed
 	default:
 		return workspace.WorkspaceStateClean
 	}
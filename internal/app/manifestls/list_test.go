@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
 )
 
 func TestList_ClassifiesAppliedMissingDriftExtra(t *testing.T) {
@@ -16,6 +17,9 @@ func TestList_ClassifiesAppliedMissingDriftExtra(t *testing.T) {
 	if err := os.MkdirAll(filepath.Join(rootDir, "workspaces", "WS_APPLIED"), 0o755); err != nil {
 		t.Fatalf("mkdir applied: %v", err)
 	}
+	if err := workspace.SaveMetadata(filepath.Join(rootDir, "workspaces", "WS_APPLIED"), workspace.Metadata{Description: "applied"}); err != nil {
+		t.Fatalf("save applied metadata: %v", err)
+	}
 	if err := os.MkdirAll(filepath.Join(rootDir, "workspaces", "WS_DRIFT"), 0o755); err != nil {
 		t.Fatalf("mkdir drift: %v", err)
 	}
@@ -0,0 +1,252 @@
+package manifestplan
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+// DocumentSchemaVersion is bumped whenever a field is removed or its meaning changes.
+// Adding new optional fields does not require a bump.
+const DocumentSchemaVersion = 1
+
+// Document is the machine-readable form of a plan Result (used by `gion plan --format json|yaml`).
+type Document struct {
+	SchemaVersion int                 `json:"schema_version" yaml:"schema_version"`
+	HasChanges    bool                `json:"has_changes" yaml:"has_changes"`
+	Destructive   bool                `json:"destructive" yaml:"destructive"`
+	Summary       DocumentSummary     `json:"summary" yaml:"summary"`
+	Changes       []DocumentWorkspace `json:"changes" yaml:"changes"`
+	Warnings      []string            `json:"warnings" yaml:"warnings"`
+}
+
+type DocumentSummary struct {
+	Add    int `json:"add" yaml:"add"`
+	Update int `json:"update" yaml:"update"`
+	Remove int `json:"remove" yaml:"remove"`
+}
+
+type DocumentWorkspace struct {
+	Kind        string `json:"kind" yaml:"kind"`
+	WorkspaceID string `json:"workspace_id" yaml:"workspace_id"`
+	// FromWorkspaceID is set on renames (kind "rename").
+	FromWorkspaceID string                   `json:"from_workspace_id,omitempty" yaml:"from_workspace_id,omitempty"`
+	Description     string                   `json:"description,omitempty" yaml:"description,omitempty"`
+	Destructive     bool                     `json:"destructive" yaml:"destructive"`
+	Risk            *DocumentRisk            `json:"risk,omitempty" yaml:"risk,omitempty"`
+	Repos           []DocumentRepo           `json:"repos" yaml:"repos"`
+	Metadata        []DocumentMetadataChange `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+type DocumentMetadataChange struct {
+	Field string `json:"field" yaml:"field"`
+	From  string `json:"from" yaml:"from"`
+	To    string `json:"to" yaml:"to"`
+}
+
+type DocumentRepo struct {
+	Kind        string `json:"kind" yaml:"kind"`
+	Alias       string `json:"alias" yaml:"alias"`
+	FromAlias   string `json:"from_alias,omitempty" yaml:"from_alias,omitempty"`
+	FromRepo    string `json:"from_repo,omitempty" yaml:"from_repo,omitempty"`
+	ToRepo      string `json:"to_repo,omitempty" yaml:"to_repo,omitempty"`
+	FromBranch  string `json:"from_branch,omitempty" yaml:"from_branch,omitempty"`
+	ToBranch    string `json:"to_branch,omitempty" yaml:"to_branch,omitempty"`
+	Destructive bool   `json:"destructive" yaml:"destructive"`
+}
+
+// DocumentRisk mirrors workspace.WorkspaceState for workspaces that already exist on the filesystem.
+type DocumentRisk struct {
+	Kind     string             `json:"kind" yaml:"kind"`
+	Repos    []DocumentRepoRisk `json:"repos" yaml:"repos"`
+	Warnings []string           `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+type DocumentRepoRisk struct {
+	Alias          string `json:"alias" yaml:"alias"`
+	Kind           string `json:"kind" yaml:"kind"`
+	Upstream       string `json:"upstream,omitempty" yaml:"upstream,omitempty"`
+	Ahead          int    `json:"ahead" yaml:"ahead"`
+	Behind         int    `json:"behind" yaml:"behind"`
+	StagedCount    int    `json:"staged" yaml:"staged"`
+	UnstagedCount  int    `json:"unstaged" yaml:"unstaged"`
+	UntrackedCount int    `json:"untracked" yaml:"untracked"`
+	UnmergedCount  int    `json:"unmerged" yaml:"unmerged"`
+	StashCount     int    `json:"stash" yaml:"stash"`
+	Operation      string `json:"operation,omitempty" yaml:"operation,omitempty"`
+	// LocalCommits counts, for branches without upstream, the commits not on BaseRef
+	// ("origin" for all origin branches).
+	LocalCommits int    `json:"local_commits" yaml:"local_commits"`
+	BaseRef      string `json:"base_ref,omitempty" yaml:"base_ref,omitempty"`
+	Error        string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Export builds a Document for the plan, classifying the risk of every workspace that
+// is removed or updated (workspaces being added have no state yet).
+func Export(ctx context.Context, rootDir string, result Result) Document {
+	return buildDocument(result, func(workspaceID string) (workspace.WorkspaceState, error) {
+		return workspace.State(ctx, rootDir, workspaceID)
+	})
+}
+
+func buildDocument(result Result, stateFor func(workspaceID string) (workspace.WorkspaceState, error)) Document {
+	doc := Document{
+		SchemaVersion: DocumentSchemaVersion,
+		HasChanges:    len(result.Changes) > 0,
+		Changes:       []DocumentWorkspace{},
+		Warnings:      []string{},
+	}
+	for _, warn := range result.Warnings {
+		doc.Warnings = append(doc.Warnings, warn.Error())
+	}
+	for _, change := range result.Changes {
+		entry := DocumentWorkspace{
+			Kind:            change.Kind.String(),
+			WorkspaceID:     change.WorkspaceID,
+			FromWorkspaceID: change.FromWorkspaceID,
+			Description:     workspaceDescription(result, change.WorkspaceID),
+			Destructive:     IsDestructiveWorkspaceChange(change),
+			Repos:           []DocumentRepo{},
+		}
+		for _, repoChange := range change.Repos {
+			entry.Repos = append(entry.Repos, DocumentRepo{
+				Kind:        string(repoChange.Kind),
+				Alias:       repoChange.Alias,
+				FromAlias:   repoChange.FromAlias,
+				FromRepo:    repoChange.FromRepo,
+				ToRepo:      repoChange.ToRepo,
+				FromBranch:  repoChange.FromBranch,
+				ToBranch:    repoChange.ToBranch,
+				Destructive: IsDestructiveRepoChange(repoChange),
+			})
+		}
+		for _, metadataChange := range change.Metadata {
+			entry.Metadata = append(entry.Metadata, DocumentMetadataChange{
+				Field: string(metadataChange.Field),
+				From:  metadataChange.From,
+				To:    metadataChange.To,
+			})
+		}
+		switch change.Kind {
+		case WorkspaceAdd:
+			doc.Summary.Add++
+		case WorkspaceUpdate, WorkspaceMetadata, WorkspaceRename:
+			doc.Summary.Update++
+		case WorkspaceRemove:
+			doc.Summary.Remove++
+		}
+		if change.Kind != WorkspaceAdd && stateFor != nil {
+			entry.Risk = documentRisk(stateFor(existingWorkspaceID(change)))
+		}
+		if entry.Destructive {
+			doc.Destructive = true
+		}
+		doc.Changes = append(doc.Changes, entry)
+	}
+	return doc
+}
+
+func documentRisk(state workspace.WorkspaceState, err error) *DocumentRisk {
+	if err != nil {
+		return &DocumentRisk{
+			Kind:     string(workspace.WorkspaceStateUnknown),
+			Repos:    []DocumentRepoRisk{},
+			Warnings: []string{err.Error()},
+		}
+	}
+	risk := &DocumentRisk{
+		Kind:  string(state.Kind),
+		Repos: []DocumentRepoRisk{},
+	}
+	for _, repo := range state.Repos {
+		entry := DocumentRepoRisk{
+			Alias:          repo.Alias,
+			Kind:           string(repo.Kind),
+			Upstream:       repo.Upstream,
+			Ahead:          repo.AheadCount,
+			Behind:         repo.BehindCount,
+			StagedCount:    repo.StagedCount,
+			UnstagedCount:  repo.UnstagedCount,
+			UntrackedCount: repo.UntrackedCount,
+			UnmergedCount:  repo.UnmergedCount,
+			StashCount:     repo.StashCount,
+			Operation:      repo.Operation,
+			LocalCommits:   repo.LocalCommitCount,
+			BaseRef:        repo.BaseRef,
+		}
+		if repo.Error != nil {
+			entry.Error = repo.Error.Error()
+		}
+		risk.Repos = append(risk.Repos, entry)
+	}
+	for _, warn := range state.Warnings {
+		risk.Warnings = append(risk.Warnings, warn.Error())
+	}
+	return risk
+}
+
+// existingWorkspaceID returns the ID the changed workspace has on the filesystem now.
+func existingWorkspaceID(change WorkspaceChange) string {
+	if change.Kind == WorkspaceRename {
+		return change.FromWorkspaceID
+	}
+	return change.WorkspaceID
+}
+
+func workspaceDescription(result Result, workspaceID string) string {
+	if ws, ok := result.Desired.Workspaces[workspaceID]; ok {
+		return strings.TrimSpace(ws.Description)
+	}
+	if ws, ok := result.Actual.Workspaces[workspaceID]; ok {
+		return strings.TrimSpace(ws.Description)
+	}
+	return ""
+}
+
+// IsDestructiveWorkspaceChange reports whether applying the change may discard local work.
+// Renames move worktrees and are never destructive.
+func IsDestructiveWorkspaceChange(change WorkspaceChange) bool {
+	switch change.Kind {
+	case WorkspaceRemove:
+		return true
+	case WorkspaceUpdate:
+		for _, repoChange := range change.Repos {
+			if IsDestructiveRepoChange(repoChange) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsDestructiveRepoChange reports whether the repo change removes a worktree.
+// In-place branch renames and alias moves keep the worktree and are not destructive.
+func IsDestructiveRepoChange(change RepoChange) bool {
+	switch change.Kind {
+	case RepoRemove:
+		return true
+	case RepoUpdate:
+		return !IsInPlaceBranchRename(change)
+	default:
+		return false
+	}
+}
+
+// IsInPlaceBranchRename reports whether an update only renames the branch of the same repo.
+func IsInPlaceBranchRename(change RepoChange) bool {
+	if change.Kind != RepoUpdate {
+		return false
+	}
+	fromRepo := strings.TrimSpace(change.FromRepo)
+	toRepo := strings.TrimSpace(change.ToRepo)
+	fromBranch := strings.TrimSpace(change.FromBranch)
+	toBranch := strings.TrimSpace(change.ToBranch)
+	if fromRepo == "" || toRepo == "" || fromBranch == "" || toBranch == "" {
+		return false
+	}
+	if fromRepo != toRepo {
+		return false
+	}
+	return fromBranch != toBranch
+}
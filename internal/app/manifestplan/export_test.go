@@ -0,0 +1,88 @@
+package manifestplan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestBuildDocument(t *testing.T) {
+	t.Parallel()
+
+	result := Result{
+		Desired: manifest.File{Workspaces: map[string]manifest.Workspace{
+			"WS-ADD":    {Description: "new work"},
+			"WS-RENAME": {},
+		}},
+		Actual: manifest.File{Workspaces: map[string]manifest.Workspace{
+			"WS-RENAME": {},
+			"WS-RM":     {Description: "old work"},
+		}},
+		Changes: []WorkspaceChange{
+			{Kind: WorkspaceAdd, WorkspaceID: "WS-ADD", Repos: []RepoChange{
+				{Kind: RepoAdd, Alias: "app", ToRepo: "example.com/org/app", ToBranch: "WS-ADD"},
+			}},
+			{Kind: WorkspaceUpdate, WorkspaceID: "WS-RENAME", Repos: []RepoChange{
+				{Kind: RepoUpdate, Alias: "app", FromRepo: "example.com/org/app", ToRepo: "example.com/org/app", FromBranch: "a", ToBranch: "b"},
+			}},
+			{Kind: WorkspaceRemove, WorkspaceID: "WS-RM"},
+		},
+		Warnings: []error{errors.New("scan warning")},
+	}
+	states := map[string]workspace.WorkspaceState{
+		"WS-RENAME": {Kind: workspace.WorkspaceStateClean, Repos: []workspace.RepoState{{Alias: "app", Kind: workspace.RepoStateClean, Upstream: "origin/a"}}},
+		"WS-RM":     {Kind: workspace.WorkspaceStateUnpushed, Repos: []workspace.RepoState{{Alias: "app", Kind: workspace.RepoStateUnpushed, AheadCount: 2}}},
+	}
+	var asked []string
+	doc := buildDocument(result, func(id string) (workspace.WorkspaceState, error) {
+		asked = append(asked, id)
+		return states[id], nil
+	})
+
+	if doc.SchemaVersion != DocumentSchemaVersion {
+		t.Fatalf("schema version: got %d", doc.SchemaVersion)
+	}
+	if !doc.HasChanges || !doc.Destructive {
+		t.Fatalf("expected has_changes and destructive, got %+v", doc)
+	}
+	if doc.Summary != (DocumentSummary{Add: 1, Update: 1, Remove: 1}) {
+		t.Fatalf("summary: got %+v", doc.Summary)
+	}
+	if len(doc.Warnings) != 1 || doc.Warnings[0] != "scan warning" {
+		t.Fatalf("warnings: got %v", doc.Warnings)
+	}
+	if len(asked) != 2 {
+		t.Fatalf("expected state lookups only for existing workspaces, got %v", asked)
+	}
+
+	add := doc.Changes[0]
+	if add.Risk != nil || add.Destructive || add.Description != "new work" {
+		t.Fatalf("unexpected add entry: %+v", add)
+	}
+	rename := doc.Changes[1]
+	if rename.Destructive || rename.Repos[0].Destructive {
+		t.Fatalf("branch rename should not be destructive: %+v", rename)
+	}
+	rm := doc.Changes[2]
+	if !rm.Destructive || rm.Risk == nil || rm.Risk.Kind != "unpushed" || rm.Risk.Repos[0].Ahead != 2 {
+		t.Fatalf("unexpected remove entry: %+v", rm)
+	}
+	if rm.Description != "old work" {
+		t.Fatalf("remove description: got %q", rm.Description)
+	}
+}
+
+func TestBuildDocument_StateErrorIsUnknown(t *testing.T) {
+	t.Parallel()
+
+	result := Result{Changes: []WorkspaceChange{{Kind: WorkspaceRemove, WorkspaceID: "WS-1"}}}
+	doc := buildDocument(result, func(string) (workspace.WorkspaceState, error) {
+		return workspace.WorkspaceState{}, errors.New("boom")
+	})
+	risk := doc.Changes[0].Risk
+	if risk == nil || risk.Kind != string(workspace.WorkspaceStateUnknown) || len(risk.Warnings) != 1 {
+		t.Fatalf("expected unknown risk with warning, got %+v", risk)
+	}
+}
@@ -16,11 +16,16 @@ const (
 	RepoAdd    RepoChangeKind = "add"
 	RepoRemove RepoChangeKind = "remove"
 	RepoUpdate RepoChangeKind = "update"
+	// RepoMove renames the alias of a repo whose repo key and branch stay the same; the
+	// worktree is moved in place instead of being removed and re-added.
+	RepoMove RepoChangeKind = "move"
 )
 
 type RepoChange struct {
-	Kind       RepoChangeKind
-	Alias      string
+	Kind  RepoChangeKind
+	Alias string
+	// FromAlias is the previous alias of a RepoMove.
+	FromAlias  string
 	FromRepo   string
 	ToRepo     string
 	FromBranch string
@@ -33,12 +38,42 @@ const (
 	WorkspaceAdd    WorkspaceChangeKind = "add"
 	WorkspaceRemove WorkspaceChangeKind = "remove"
 	WorkspaceUpdate WorkspaceChangeKind = "update"
+	// WorkspaceMetadata is an in-place update of workspace metadata only
+	// (.gion/metadata.json); worktrees are left untouched.
+	WorkspaceMetadata WorkspaceChangeKind = "metadata"
+	// WorkspaceRename moves an existing workspace to a new ID. It replaces a remove+add
+	// pair whose repos (aliases, repo keys, branches) are identical, so worktrees are
+	// moved instead of recreated.
+	WorkspaceRename WorkspaceChangeKind = "rename"
 )
 
+type MetadataField string
+
+const (
+	MetadataDescription MetadataField = "description"
+	MetadataMode        MetadataField = "mode"
+	MetadataPresetName  MetadataField = "preset_name"
+	MetadataSourceURL   MetadataField = "source_url"
+	// MetadataBaseRef is the repos' base_ref, stored once per workspace as base_branch.
+	MetadataBaseRef MetadataField = "base_ref"
+)
+
+type MetadataChange struct {
+	Field MetadataField
+	From  string
+	To    string
+}
+
 type WorkspaceChange struct {
 	Kind        WorkspaceChangeKind
 	WorkspaceID string
-	Repos       []RepoChange
+	// FromWorkspaceID is the current ID of a workspace being renamed to WorkspaceID.
+	FromWorkspaceID string
+	Repos           []RepoChange
+	// Metadata lists metadata differences of an existing workspace. It is set on
+	// WorkspaceMetadata changes, on WorkspaceUpdate changes that also change repos, and
+	// on WorkspaceRename changes.
+	Metadata []MetadataChange
 }
 
 type Result struct {
@@ -87,11 +122,23 @@ func Plan(ctx context.Context, rootDir string) (Result, error) {
 			continue
 		}
 		repoChanges := diffRepos(actualWS.Repos, desiredWS.Repos)
-		if len(repoChanges) > 0 {
+		metadataChanges := diffMetadata(actualWS, desiredWS)
+		if _, ok := WorkspaceBaseRef(desiredWS); !ok {
+			warnings = append(warnings, fmt.Errorf("workspace %s: repos have different base_ref values; base_branch in metadata is left unchanged", id))
+		}
+		switch {
+		case len(repoChanges) > 0:
 			changes = append(changes, WorkspaceChange{
 				Kind:        WorkspaceUpdate,
 				WorkspaceID: id,
 				Repos:       repoChanges,
+				Metadata:    metadataChanges,
+			})
+		case len(metadataChanges) > 0:
+			changes = append(changes, WorkspaceChange{
+				Kind:        WorkspaceMetadata,
+				WorkspaceID: id,
+				Metadata:    metadataChanges,
 			})
 		}
 	}
@@ -109,6 +156,7 @@ func Plan(ctx context.Context, rootDir string) (Result, error) {
 			WorkspaceID: id,
 		})
 	}
+	changes = detectRenames(changes, desired, actual)
 
 	sort.Slice(changes, func(i, j int) bool {
 		if changes[i].WorkspaceID == changes[j].WorkspaceID {
@@ -125,6 +173,74 @@ func Plan(ctx context.Context, rootDir string) (Result, error) {
 	}, nil
 }
 
+// detectRenames turns a workspace add and a workspace remove into a rename when the two
+// workspaces have the same repos and no other add or remove matches either of them.
+func detectRenames(changes []WorkspaceChange, desired, actual manifest.File) []WorkspaceChange {
+	addsBySignature := map[string][]int{}
+	removesBySignature := map[string][]int{}
+	for i, change := range changes {
+		switch change.Kind {
+		case WorkspaceAdd:
+			ws := desired.Workspaces[change.WorkspaceID]
+			if len(ws.Repos) == 0 {
+				continue
+			}
+			signature := repoSignature(ws.Repos)
+			addsBySignature[signature] = append(addsBySignature[signature], i)
+		case WorkspaceRemove:
+			ws := actual.Workspaces[change.WorkspaceID]
+			if len(ws.Repos) == 0 {
+				continue
+			}
+			signature := repoSignature(ws.Repos)
+			removesBySignature[signature] = append(removesBySignature[signature], i)
+		}
+	}
+
+	replaced := map[int]bool{}
+	var renames []WorkspaceChange
+	for signature, adds := range addsBySignature {
+		removes := removesBySignature[signature]
+		if len(adds) != 1 || len(removes) != 1 {
+			continue
+		}
+		toID := changes[adds[0]].WorkspaceID
+		fromID := changes[removes[0]].WorkspaceID
+		replaced[adds[0]] = true
+		replaced[removes[0]] = true
+		renames = append(renames, WorkspaceChange{
+			Kind:            WorkspaceRename,
+			WorkspaceID:     toID,
+			FromWorkspaceID: fromID,
+			Metadata:        diffMetadata(actual.Workspaces[fromID], desired.Workspaces[toID]),
+		})
+	}
+	if len(renames) == 0 {
+		return changes
+	}
+	kept := make([]WorkspaceChange, 0, len(changes)-len(renames))
+	for i, change := range changes {
+		if !replaced[i] {
+			kept = append(kept, change)
+		}
+	}
+	return append(kept, renames...)
+}
+
+// repoSignature identifies the repos of a workspace regardless of order.
+func repoSignature(repos []manifest.Repo) string {
+	entries := make([]string, 0, len(repos))
+	for _, repoEntry := range repos {
+		entries = append(entries, strings.Join([]string{
+			strings.TrimSpace(repoEntry.Alias),
+			strings.TrimSpace(repoEntry.RepoKey),
+			strings.TrimSpace(repoEntry.Branch),
+		}, "\x00"))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
+
 func diffRepos(actualRepos, desiredRepos []manifest.Repo) []RepoChange {
 	actualByAlias := map[string]manifest.Repo{}
 	for _, repo := range actualRepos {
@@ -171,12 +287,108 @@ func diffRepos(actualRepos, desiredRepos []manifest.Repo) []RepoChange {
 		})
 	}
 
+	changes = detectRepoMoves(changes)
 	sort.Slice(changes, func(i, j int) bool {
 		return changes[i].Alias < changes[j].Alias
 	})
 	return changes
 }
 
+// detectRepoMoves turns a repo add and a repo remove into a move when both have the same
+// repo key and branch and no other add or remove matches either of them.
+func detectRepoMoves(changes []RepoChange) []RepoChange {
+	addsByKey := map[string][]int{}
+	removesByKey := map[string][]int{}
+	for i, change := range changes {
+		switch change.Kind {
+		case RepoAdd:
+			key := repoMoveKey(change.ToRepo, change.ToBranch)
+			addsByKey[key] = append(addsByKey[key], i)
+		case RepoRemove:
+			key := repoMoveKey(change.FromRepo, change.FromBranch)
+			removesByKey[key] = append(removesByKey[key], i)
+		}
+	}
+
+	replaced := map[int]bool{}
+	var moves []RepoChange
+	for key, adds := range addsByKey {
+		removes := removesByKey[key]
+		if len(adds) != 1 || len(removes) != 1 {
+			continue
+		}
+		added := changes[adds[0]]
+		removed := changes[removes[0]]
+		if strings.TrimSpace(added.ToRepo) == "" || strings.TrimSpace(added.ToBranch) == "" {
+			continue
+		}
+		replaced[adds[0]] = true
+		replaced[removes[0]] = true
+		moves = append(moves, RepoChange{
+			Kind:       RepoMove,
+			Alias:      added.Alias,
+			FromAlias:  removed.Alias,
+			FromRepo:   removed.FromRepo,
+			ToRepo:     added.ToRepo,
+			FromBranch: removed.FromBranch,
+			ToBranch:   added.ToBranch,
+		})
+	}
+	if len(moves) == 0 {
+		return changes
+	}
+	kept := make([]RepoChange, 0, len(changes)-len(moves))
+	for i, change := range changes {
+		if !replaced[i] {
+			kept = append(kept, change)
+		}
+	}
+	return append(kept, moves...)
+}
+
+func repoMoveKey(repoKey, branch string) string {
+	return strings.TrimSpace(repoKey) + "\x00" + strings.TrimSpace(branch)
+}
+
+// diffMetadata compares the workspace fields stored in .gion/metadata.json.
+func diffMetadata(actual, desired manifest.Workspace) []MetadataChange {
+	var changes []MetadataChange
+	compare := func(field MetadataField, from, to string) {
+		from = strings.TrimSpace(from)
+		to = strings.TrimSpace(to)
+		if from != to {
+			changes = append(changes, MetadataChange{Field: field, From: from, To: to})
+		}
+	}
+	compare(MetadataDescription, actual.Description, desired.Description)
+	compare(MetadataMode, actual.Mode, desired.Mode)
+	compare(MetadataPresetName, actual.PresetName, desired.PresetName)
+	compare(MetadataSourceURL, actual.SourceURL, desired.SourceURL)
+	// base_ref can only be compared when both sides have repos that share one: metadata
+	// holds a single base_branch, and mixed per-repo values must not clear it.
+	if len(actual.Repos) > 0 && len(desired.Repos) > 0 {
+		actualBaseRef, actualShared := WorkspaceBaseRef(actual)
+		desiredBaseRef, desiredShared := WorkspaceBaseRef(desired)
+		if actualShared && desiredShared {
+			compare(MetadataBaseRef, actualBaseRef, desiredBaseRef)
+		}
+	}
+	return changes
+}
+
+// WorkspaceBaseRef returns the base_ref shared by all repos of ws. Metadata holds a single
+// base branch per workspace; ok is false when the repos have different base refs.
+func WorkspaceBaseRef(ws manifest.Workspace) (baseRef string, ok bool) {
+	for i, repoEntry := range ws.Repos {
+		value := strings.TrimSpace(repoEntry.BaseRef)
+		if i > 0 && value != baseRef {
+			return "", false
+		}
+		baseRef = value
+	}
+	return baseRef, true
+}
+
 func plannedRepoAdds(ws manifest.Workspace) []RepoChange {
 	var changes []RepoChange
 	for _, repo := range ws.Repos {
@@ -210,6 +422,10 @@ func (k WorkspaceChangeKind) String() string {
 		return "remove"
 	case WorkspaceUpdate:
 		return "update"
+	case WorkspaceMetadata:
+		return "metadata"
+	case WorkspaceRename:
+		return "rename"
 	default:
 		return fmt.Sprintf("unknown(%s)", string(k))
 	}
@@ -0,0 +1,139 @@
+package manifestplan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func TestDiffMetadata(t *testing.T) {
+	t.Parallel()
+
+	actual := manifest.Workspace{
+		Description: "old",
+		Mode:        "repo",
+		Repos:       []manifest.Repo{{Alias: "app", BaseRef: "origin/main"}},
+	}
+	desired := manifest.Workspace{
+		Description: " new ",
+		Mode:        "repo",
+		SourceURL:   "https://example.com/issues/1",
+		Repos:       []manifest.Repo{{Alias: "app", BaseRef: "origin/develop"}},
+	}
+	got := diffMetadata(actual, desired)
+	want := []MetadataChange{
+		{Field: MetadataDescription, From: "old", To: "new"},
+		{Field: MetadataSourceURL, From: "", To: "https://example.com/issues/1"},
+		{Field: MetadataBaseRef, From: "origin/main", To: "origin/develop"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("metadata changes: got %+v, want %+v", got, want)
+	}
+
+	if changes := diffMetadata(actual, actual); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", changes)
+	}
+
+	// Mixed per-repo base refs cannot be stored as one base_branch: leave it alone.
+	mixed := manifest.Workspace{
+		Description: "old",
+		Mode:        "repo",
+		Repos:       []manifest.Repo{{Alias: "api", BaseRef: "origin/develop"}, {Alias: "app", BaseRef: "origin/main"}},
+	}
+	if changes := diffMetadata(actual, mixed); len(changes) != 0 {
+		t.Fatalf("expected no base_ref change for mixed base refs, got %+v", changes)
+	}
+	if changes := diffMetadata(mixed, actual); len(changes) != 0 {
+		t.Fatalf("expected no base_ref change against mixed actual base refs, got %+v", changes)
+	}
+}
+
+func TestWorkspaceBaseRef(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		repos  []manifest.Repo
+		want   string
+		wantOK bool
+	}{
+		{name: "none", repos: nil, want: "", wantOK: true},
+		{name: "shared", repos: []manifest.Repo{{BaseRef: "origin/main"}, {BaseRef: "origin/main"}}, want: "origin/main", wantOK: true},
+		{name: "mixed", repos: []manifest.Repo{{BaseRef: "origin/main"}, {BaseRef: "origin/develop"}}, want: "", wantOK: false},
+		{name: "partly unset", repos: []manifest.Repo{{BaseRef: "origin/main"}, {}}, want: "", wantOK: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := WorkspaceBaseRef(manifest.Workspace{Repos: tc.repos})
+			if got != tc.want || ok != tc.wantOK {
+				t.Fatalf("expected (%q, %v), got (%q, %v)", tc.want, tc.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestDetectRenames(t *testing.T) {
+	t.Parallel()
+
+	repos := []manifest.Repo{{Alias: "app", RepoKey: "example.com/org/app.git", Branch: "feature"}}
+	actual := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"OLD": {Description: "old", Repos: repos},
+	}}
+	desired := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"NEW": {Description: "new", Repos: repos},
+	}}
+	changes := []WorkspaceChange{
+		{Kind: WorkspaceAdd, WorkspaceID: "NEW"},
+		{Kind: WorkspaceRemove, WorkspaceID: "OLD"},
+	}
+	got := detectRenames(changes, desired, actual)
+	want := []WorkspaceChange{{
+		Kind:            WorkspaceRename,
+		WorkspaceID:     "NEW",
+		FromWorkspaceID: "OLD",
+		Metadata:        []MetadataChange{{Field: MetadataDescription, From: "old", To: "new"}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("renames: got %+v, want %+v", got, want)
+	}
+
+	// Two removed workspaces with the same repos leave the match ambiguous.
+	actual.Workspaces["OTHER"] = manifest.Workspace{Repos: repos}
+	changes = append(changes, WorkspaceChange{Kind: WorkspaceRemove, WorkspaceID: "OTHER"})
+	if got := detectRenames(changes, desired, actual); !reflect.DeepEqual(got, changes) {
+		t.Fatalf("expected ambiguous changes to stay as is, got %+v", got)
+	}
+
+	// A different branch is a different workspace.
+	desired.Workspaces["NEW"] = manifest.Workspace{Repos: []manifest.Repo{{Alias: "app", RepoKey: "example.com/org/app.git", Branch: "other"}}}
+	changes = changes[:2]
+	if got := detectRenames(changes, desired, actual); !reflect.DeepEqual(got, changes) {
+		t.Fatalf("expected changes with different branches to stay as is, got %+v", got)
+	}
+}
+
+func TestDiffReposDetectsAliasMoves(t *testing.T) {
+	t.Parallel()
+
+	actual := []manifest.Repo{
+		{Alias: "app", RepoKey: "example.com/org/app.git", Branch: "feature"},
+		{Alias: "lib", RepoKey: "example.com/org/lib.git", Branch: "feature"},
+	}
+	desired := []manifest.Repo{
+		{Alias: "backend", RepoKey: "example.com/org/app.git", Branch: "feature"},
+		{Alias: "shared", RepoKey: "example.com/org/lib.git", Branch: "other"},
+	}
+	got := diffRepos(actual, desired)
+	want := []RepoChange{
+		{Kind: RepoMove, Alias: "backend", FromAlias: "app", FromRepo: "example.com/org/app.git", ToRepo: "example.com/org/app.git", FromBranch: "feature", ToBranch: "feature"},
+		{Kind: RepoRemove, Alias: "lib", FromRepo: "example.com/org/lib.git", FromBranch: "feature"},
+		{Kind: RepoAdd, Alias: "shared", ToRepo: "example.com/org/lib.git", ToBranch: "other"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("repo changes: got %+v, want %+v", got, want)
+	}
+	if IsDestructiveRepoChange(got[0]) {
+		t.Fatalf("expected alias move to be non-destructive")
+	}
+}
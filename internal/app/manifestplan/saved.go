@@ -0,0 +1,195 @@
+package manifestplan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+// SavedPlanFormatVersion identifies the on-disk layout written by SavePlanFile.
+const SavedPlanFormatVersion = 1
+
+// Fingerprint captures the inputs a plan was computed from, so a saved plan can be
+// rejected when gion.yaml or the worktrees changed before it is applied.
+type Fingerprint struct {
+	ManifestSHA256 string            `json:"manifest_sha256"`
+	Heads          map[string]string `json:"heads"`
+}
+
+// SavedPlan is the serialized form of a plan Result (`gion plan --out <file>`).
+type SavedPlan struct {
+	FormatVersion int               `json:"format_version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	RootDir       string            `json:"root_dir"`
+	Fingerprint   Fingerprint       `json:"fingerprint"`
+	Desired       manifest.File     `json:"desired"`
+	Actual        manifest.File     `json:"actual"`
+	Changes       []WorkspaceChange `json:"changes"`
+	Warnings      []string          `json:"warnings"`
+}
+
+// Result restores the plan Result captured in the saved plan.
+func (s SavedPlan) Result() Result {
+	var warnings []error
+	for _, warn := range s.Warnings {
+		warnings = append(warnings, errors.New(warn))
+	}
+	return Result{
+		Desired:  s.Desired,
+		Actual:   s.Actual,
+		Changes:  s.Changes,
+		Warnings: warnings,
+	}
+}
+
+// ComputeFingerprint hashes gion.yaml and records the HEAD commit of every worktree.
+func ComputeFingerprint(ctx context.Context, rootDir string) (Fingerprint, error) {
+	data, err := os.ReadFile(manifest.Path(rootDir))
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("read %s: %w", manifest.FileName, err)
+	}
+	sum := sha256.Sum256(data)
+	fp := Fingerprint{
+		ManifestSHA256: hex.EncodeToString(sum[:]),
+		Heads:          map[string]string{},
+	}
+
+	entries, _, err := workspace.List(rootDir)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	for _, entry := range entries {
+		repos, _, err := workspace.ScanRepos(ctx, entry.WorkspacePath)
+		if err != nil {
+			return Fingerprint{}, fmt.Errorf("workspace %s repos: %w", entry.WorkspaceID, err)
+		}
+		for _, repoEntry := range repos {
+			head, err := gitcmd.RevParse(ctx, repoEntry.WorktreePath, "HEAD")
+			if err != nil {
+				head = ""
+			}
+			fp.Heads[headKey(entry.WorkspaceID, repoEntry.Alias)] = head
+		}
+	}
+	return fp, nil
+}
+
+// SavePlanFile writes the plan and its fingerprint to path.
+func SavePlanFile(path, rootDir string, result Result, fp Fingerprint) error {
+	saved := SavedPlan{
+		FormatVersion: SavedPlanFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		RootDir:       rootDir,
+		Fingerprint:   fp,
+		Desired:       result.Desired,
+		Actual:        result.Actual,
+		Changes:       result.Changes,
+		Warnings:      []string{},
+	}
+	for _, warn := range result.Warnings {
+		saved.Warnings = append(saved.Warnings, warn.Error())
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal plan file: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("create plan file dir: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write plan file: %w", err)
+	}
+	return nil
+}
+
+// LoadPlanFile reads a plan written by SavePlanFile.
+func LoadPlanFile(path string) (SavedPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SavedPlan{}, fmt.Errorf("read plan file: %w", err)
+	}
+	var saved SavedPlan
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return SavedPlan{}, fmt.Errorf("parse plan file: %w", err)
+	}
+	if saved.FormatVersion != SavedPlanFormatVersion {
+		return SavedPlan{}, fmt.Errorf("unsupported plan file version: %d", saved.FormatVersion)
+	}
+	return saved, nil
+}
+
+// Verify returns an error describing every difference between the saved fingerprint
+// and the current state of rootDir.
+func (s SavedPlan) Verify(ctx context.Context, rootDir string) error {
+	if filepath.Clean(s.RootDir) != filepath.Clean(rootDir) {
+		return fmt.Errorf("plan file was created for root %s (current root: %s)", s.RootDir, rootDir)
+	}
+	current, err := ComputeFingerprint(ctx, rootDir)
+	if err != nil {
+		return err
+	}
+	if problems := diffFingerprints(s.Fingerprint, current); len(problems) > 0 {
+		return fmt.Errorf("plan file is stale (re-run gion plan): %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func diffFingerprints(saved, current Fingerprint) []string {
+	var problems []string
+	if saved.ManifestSHA256 != current.ManifestSHA256 {
+		problems = append(problems, fmt.Sprintf("%s changed", manifest.FileName))
+	}
+	keys := map[string]struct{}{}
+	for key := range saved.Heads {
+		keys[key] = struct{}{}
+	}
+	for key := range current.Heads {
+		keys[key] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		before, hadBefore := saved.Heads[key]
+		after, hasAfter := current.Heads[key]
+		switch {
+		case !hadBefore:
+			problems = append(problems, fmt.Sprintf("%s appeared", key))
+		case !hasAfter:
+			problems = append(problems, fmt.Sprintf("%s disappeared", key))
+		case before != after:
+			problems = append(problems, fmt.Sprintf("%s HEAD moved (%s -> %s)", key, shortOID(before), shortOID(after)))
+		}
+	}
+	return problems
+}
+
+func headKey(workspaceID, alias string) string {
+	return workspaceID + "/" + alias
+}
+
+func shortOID(oid string) string {
+	if oid == "" {
+		return "unknown"
+	}
+	if len(oid) <= 7 {
+		return oid
+	}
+	return oid[:7]
+}
@@ -0,0 +1,83 @@
+package manifestplan
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func TestSavedPlan_RoundTripAndVerify(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	if err := manifest.Save(rootDir, manifest.File{Version: 1}); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+
+	fp, err := ComputeFingerprint(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("fingerprint: %v", err)
+	}
+	result := Result{
+		Changes: []WorkspaceChange{{Kind: WorkspaceRemove, WorkspaceID: "WS-1"}},
+		Warnings: []error{
+			errors.New("warn"),
+		},
+	}
+	planPath := filepath.Join(rootDir, "out", "plan.bin")
+	if err := SavePlanFile(planPath, rootDir, result, fp); err != nil {
+		t.Fatalf("save plan: %v", err)
+	}
+
+	saved, err := LoadPlanFile(planPath)
+	if err != nil {
+		t.Fatalf("load plan: %v", err)
+	}
+	restored := saved.Result()
+	if len(restored.Changes) != 1 || restored.Changes[0].WorkspaceID != "WS-1" || restored.Changes[0].Kind != WorkspaceRemove {
+		t.Fatalf("changes not restored: %+v", restored.Changes)
+	}
+	if len(restored.Warnings) != 1 || restored.Warnings[0].Error() != "warn" {
+		t.Fatalf("warnings not restored: %v", restored.Warnings)
+	}
+	if err := saved.Verify(ctx, rootDir); err != nil {
+		t.Fatalf("verify unchanged root: %v", err)
+	}
+	if err := saved.Verify(ctx, t.TempDir()); err == nil {
+		t.Fatalf("expected error for a different root")
+	}
+
+	if err := os.WriteFile(manifest.Path(rootDir), []byte("version: 1\n# edited\n"), 0o600); err != nil {
+		t.Fatalf("edit manifest: %v", err)
+	}
+	err = saved.Verify(ctx, rootDir)
+	if err == nil || !strings.Contains(err.Error(), manifest.FileName+" changed") {
+		t.Fatalf("expected manifest change error, got %v", err)
+	}
+}
+
+func TestDiffFingerprints(t *testing.T) {
+	t.Parallel()
+
+	saved := Fingerprint{ManifestSHA256: "a", Heads: map[string]string{
+		"WS-1/app": "1111111111",
+		"WS-1/api": "2222222222",
+	}}
+	current := Fingerprint{ManifestSHA256: "a", Heads: map[string]string{
+		"WS-1/app": "3333333333",
+		"WS-2/web": "4444444444",
+	}}
+	got := diffFingerprints(saved, current)
+	want := []string{
+		"WS-1/api disappeared",
+		"WS-1/app HEAD moved (1111111 -> 3333333)",
+		"WS-2/web appeared",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("diff mismatch:\ngot:  %v\nwant: %v", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+package manifestplan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+// Target selects a workspace, or a single repo alias inside a workspace, for a targeted apply.
+type Target struct {
+	WorkspaceID string
+	Alias       string
+}
+
+func (t Target) String() string {
+	if t.Alias == "" {
+		return t.WorkspaceID
+	}
+	return t.WorkspaceID + "/" + t.Alias
+}
+
+// ParseTargets parses `<workspace-id>[/<alias>]` selectors.
+func ParseTargets(values []string) ([]Target, error) {
+	var targets []Target
+	seen := map[Target]struct{}{}
+	for _, value := range values {
+		raw := strings.TrimSpace(value)
+		workspaceID, alias, hasAlias := strings.Cut(raw, "/")
+		workspaceID = strings.TrimSpace(workspaceID)
+		alias = strings.TrimSpace(alias)
+		if workspaceID == "" || (hasAlias && (alias == "" || strings.Contains(alias, "/"))) {
+			return nil, fmt.Errorf("invalid --target: %q (want <workspace-id>[/<alias>])", value)
+		}
+		target := Target{WorkspaceID: workspaceID, Alias: alias}
+		if _, ok := seen[target]; ok {
+			continue
+		}
+		seen[target] = struct{}{}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// FilterTargets narrows the plan to the changes selected by targets and returns the
+// number of changes that were left out (one per workspace change, or per repo change
+// when only some aliases of an updated workspace are targeted). Metadata changes are
+// only applied when the whole workspace is targeted. A rename is selected by either of
+// its workspace IDs, and a repo move by either of its aliases.
+func FilterTargets(result Result, targets []Target) (Result, int, error) {
+	if len(targets) == 0 {
+		return result, 0, nil
+	}
+	wholeWorkspace := map[string]bool{}
+	aliases := map[string]map[string]bool{}
+	for _, target := range targets {
+		if err := validateTarget(result, target); err != nil {
+			return Result{}, 0, err
+		}
+		if target.Alias == "" {
+			wholeWorkspace[target.WorkspaceID] = true
+			continue
+		}
+		if aliases[target.WorkspaceID] == nil {
+			aliases[target.WorkspaceID] = map[string]bool{}
+		}
+		aliases[target.WorkspaceID][target.Alias] = true
+	}
+
+	filtered := result
+	filtered.Changes = nil
+	skipped := 0
+	for _, change := range result.Changes {
+		if wholeWorkspace[change.WorkspaceID] {
+			filtered.Changes = append(filtered.Changes, change)
+			continue
+		}
+		if change.Kind == WorkspaceRename {
+			// A rename moves the whole workspace; targeting either ID selects it.
+			if wholeWorkspace[change.FromWorkspaceID] {
+				filtered.Changes = append(filtered.Changes, change)
+				continue
+			}
+			if aliases[change.FromWorkspaceID] != nil {
+				return Result{}, 0, fmt.Errorf("cannot target individual repos of workspace %s: the whole workspace is planned for %s (use --target %s)", change.FromWorkspaceID, change.Kind, change.WorkspaceID)
+			}
+		}
+		selected := aliases[change.WorkspaceID]
+		if selected == nil {
+			skipped++
+			continue
+		}
+		if change.Kind == WorkspaceMetadata {
+			// Metadata belongs to the workspace, not to a repo; it needs a workspace target.
+			skipped++
+			continue
+		}
+		if change.Kind != WorkspaceUpdate {
+			return Result{}, 0, fmt.Errorf("cannot target individual repos of workspace %s: the whole workspace is planned for %s (use --target %s)", change.WorkspaceID, change.Kind, change.WorkspaceID)
+		}
+		narrowed := change
+		narrowed.Repos = nil
+		if len(narrowed.Metadata) > 0 {
+			narrowed.Metadata = nil
+			skipped++
+		}
+		for _, repoChange := range change.Repos {
+			if selected[repoChange.Alias] || (repoChange.Kind == RepoMove && selected[repoChange.FromAlias]) {
+				narrowed.Repos = append(narrowed.Repos, repoChange)
+				continue
+			}
+			skipped++
+		}
+		if len(narrowed.Repos) > 0 {
+			filtered.Changes = append(filtered.Changes, narrowed)
+		}
+	}
+	return filtered, skipped, nil
+}
+
+func validateTarget(result Result, target Target) error {
+	desiredWS, inDesired := result.Desired.Workspaces[target.WorkspaceID]
+	actualWS, inActual := result.Actual.Workspaces[target.WorkspaceID]
+	if !inDesired && !inActual {
+		return fmt.Errorf("--target %s: workspace not found in %s or on filesystem", target, manifest.FileName)
+	}
+	if target.Alias == "" {
+		return nil
+	}
+	if _, ok := findRepoAlias(desiredWS.Repos, target.Alias); ok {
+		return nil
+	}
+	if _, ok := findRepoAlias(actualWS.Repos, target.Alias); ok {
+		return nil
+	}
+	return fmt.Errorf("--target %s: repo alias not found in workspace %s", target, target.WorkspaceID)
+}
+
+// MergeTargeted builds the manifest to write after a targeted apply: targeted workspaces
+// and aliases take their state from the filesystem (imported), while everything else
+// keeps the desired entries so untouched drift stays visible to the next plan.
+func MergeTargeted(desired, imported manifest.File, targets []Target) manifest.File {
+	merged := manifest.File{
+		Version:    desired.Version,
+		Workspaces: map[string]manifest.Workspace{},
+		Presets:    desired.Presets,
+	}
+	if merged.Version == 0 {
+		merged.Version = imported.Version
+	}
+	for id, ws := range desired.Workspaces {
+		merged.Workspaces[id] = ws
+	}
+	for _, target := range targets {
+		if target.Alias != "" {
+			continue
+		}
+		if ws, ok := imported.Workspaces[target.WorkspaceID]; ok {
+			merged.Workspaces[target.WorkspaceID] = ws
+		} else {
+			delete(merged.Workspaces, target.WorkspaceID)
+		}
+	}
+	for _, target := range targets {
+		if target.Alias == "" {
+			continue
+		}
+		ws, ok := merged.Workspaces[target.WorkspaceID]
+		if !ok {
+			continue
+		}
+		repos := make([]manifest.Repo, 0, len(ws.Repos))
+		replaced := false
+		importedRepo, inImported := findRepoAlias(imported.Workspaces[target.WorkspaceID].Repos, target.Alias)
+		for _, repoEntry := range ws.Repos {
+			if repoEntry.Alias != target.Alias {
+				repos = append(repos, repoEntry)
+				continue
+			}
+			if inImported {
+				repos = append(repos, importedRepo)
+				replaced = true
+			}
+		}
+		if inImported && !replaced {
+			repos = append(repos, importedRepo)
+		}
+		ws.Repos = repos
+		merged.Workspaces[target.WorkspaceID] = ws
+	}
+	return merged
+}
+
+func findRepoAlias(repos []manifest.Repo, alias string) (manifest.Repo, bool) {
+	for _, repoEntry := range repos {
+		if repoEntry.Alias == alias {
+			return repoEntry, true
+		}
+	}
+	return manifest.Repo{}, false
+}
@@ -0,0 +1,135 @@
+package manifestplan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func TestParseTargets(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseTargets([]string{"WS-1", " WS-2/app ", "WS-1"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []Target{{WorkspaceID: "WS-1"}, {WorkspaceID: "WS-2", Alias: "app"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("targets: got %+v, want %+v", got, want)
+	}
+
+	for _, value := range []string{"", "/app", "WS-1/", "WS-1/a/b"} {
+		if _, err := ParseTargets([]string{value}); err == nil {
+			t.Fatalf("expected error for %q", value)
+		}
+	}
+}
+
+func targetTestResult() Result {
+	return Result{
+		Desired: manifest.File{Workspaces: map[string]manifest.Workspace{
+			"WS-ADD":  {Repos: []manifest.Repo{{Alias: "app"}}},
+			"WS-META": {Description: "new"},
+			"WS-UPD":  {Repos: []manifest.Repo{{Alias: "app", Branch: "new"}, {Alias: "api", Branch: "new"}}},
+		}},
+		Actual: manifest.File{Workspaces: map[string]manifest.Workspace{
+			"WS-UPD":  {Repos: []manifest.Repo{{Alias: "app", Branch: "old"}, {Alias: "api", Branch: "old"}}},
+			"WS-RM":   {},
+			"WS-META": {Description: "old"},
+		}},
+		Changes: []WorkspaceChange{
+			{Kind: WorkspaceAdd, WorkspaceID: "WS-ADD", Repos: []RepoChange{{Kind: RepoAdd, Alias: "app"}}},
+			{Kind: WorkspaceMetadata, WorkspaceID: "WS-META", Metadata: []MetadataChange{{Field: MetadataDescription, From: "old", To: "new"}}},
+			{Kind: WorkspaceRemove, WorkspaceID: "WS-RM"},
+			{Kind: WorkspaceUpdate, WorkspaceID: "WS-UPD", Repos: []RepoChange{
+				{Kind: RepoUpdate, Alias: "api", FromBranch: "old", ToBranch: "new"},
+				{Kind: RepoUpdate, Alias: "app", FromBranch: "old", ToBranch: "new"},
+			}},
+		},
+	}
+}
+
+func TestFilterTargets(t *testing.T) {
+	t.Parallel()
+
+	result := targetTestResult()
+
+	filtered, skipped, err := FilterTargets(result, []Target{{WorkspaceID: "WS-ADD"}})
+	if err != nil {
+		t.Fatalf("filter workspace: %v", err)
+	}
+	if len(filtered.Changes) != 1 || filtered.Changes[0].WorkspaceID != "WS-ADD" || skipped != 3 {
+		t.Fatalf("workspace target: got %+v skipped=%d", filtered.Changes, skipped)
+	}
+
+	filtered, skipped, err = FilterTargets(result, []Target{{WorkspaceID: "WS-UPD", Alias: "app"}})
+	if err != nil {
+		t.Fatalf("filter alias: %v", err)
+	}
+	if len(filtered.Changes) != 1 || len(filtered.Changes[0].Repos) != 1 || filtered.Changes[0].Repos[0].Alias != "app" {
+		t.Fatalf("alias target: got %+v", filtered.Changes)
+	}
+	if skipped != 4 {
+		t.Fatalf("alias target skipped: got %d, want 4", skipped)
+	}
+	if len(result.Changes[3].Repos) != 2 {
+		t.Fatalf("input plan was modified: %+v", result.Changes[3])
+	}
+
+	filtered, _, err = FilterTargets(result, []Target{{WorkspaceID: "WS-META"}})
+	if err != nil || len(filtered.Changes) != 1 || filtered.Changes[0].Kind != WorkspaceMetadata {
+		t.Fatalf("metadata target: got %+v err=%v", filtered.Changes, err)
+	}
+
+	if _, _, err := FilterTargets(result, []Target{{WorkspaceID: "WS-ADD", Alias: "app"}}); err == nil {
+		t.Fatalf("expected error for alias target on workspace add")
+	}
+	if _, _, err := FilterTargets(result, []Target{{WorkspaceID: "WS-NOPE"}}); err == nil {
+		t.Fatalf("expected error for unknown workspace")
+	}
+	if _, _, err := FilterTargets(result, []Target{{WorkspaceID: "WS-UPD", Alias: "nope"}}); err == nil {
+		t.Fatalf("expected error for unknown alias")
+	}
+}
+
+func TestMergeTargeted(t *testing.T) {
+	t.Parallel()
+
+	desired := manifest.File{
+		Version: 1,
+		Workspaces: map[string]manifest.Workspace{
+			"WS-ADD": {Repos: []manifest.Repo{{Alias: "app", Branch: "WS-ADD"}}},
+			"WS-UPD": {Repos: []manifest.Repo{{Alias: "api", Branch: "new"}, {Alias: "app", Branch: "new"}}},
+		},
+		Presets: map[string]manifest.Preset{"p": {Repos: []string{"r"}}},
+	}
+	imported := manifest.File{
+		Version: 1,
+		Workspaces: map[string]manifest.Workspace{
+			"WS-ADD": {Description: "created", Repos: []manifest.Repo{{Alias: "app", Branch: "WS-ADD"}}},
+			"WS-UPD": {Repos: []manifest.Repo{{Alias: "api", Branch: "old"}, {Alias: "app", Branch: "new"}}},
+			"WS-RM":  {},
+		},
+	}
+
+	merged := MergeTargeted(desired, imported, []Target{{WorkspaceID: "WS-ADD"}, {WorkspaceID: "WS-UPD", Alias: "app"}})
+	if merged.Workspaces["WS-ADD"].Description != "created" {
+		t.Fatalf("targeted workspace should come from import: %+v", merged.Workspaces["WS-ADD"])
+	}
+	if _, ok := merged.Workspaces["WS-RM"]; ok {
+		t.Fatalf("untargeted workspace on filesystem must not be adopted")
+	}
+	upd := merged.Workspaces["WS-UPD"].Repos
+	if len(upd) != 2 || upd[0].Branch != "new" || upd[1].Branch != "new" {
+		t.Fatalf("untargeted alias should keep desired entry: %+v", upd)
+	}
+	if len(merged.Presets) != 1 {
+		t.Fatalf("presets should be preserved: %+v", merged.Presets)
+	}
+
+	merged = MergeTargeted(desired, manifest.File{Version: 1}, []Target{{WorkspaceID: "WS-UPD", Alias: "app"}})
+	if repos := merged.Workspaces["WS-UPD"].Repos; len(repos) != 1 || repos[0].Alias != "api" {
+		t.Fatalf("targeted alias missing on filesystem should be dropped: %+v", repos)
+	}
+}
@@ -0,0 +1,254 @@
+package prcreate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/app/push"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repospec"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// Repo is the planned PR of one worktree branch.
+type Repo struct {
+	Alias string
+	Dir   string
+	// Host, Owner and Name locate the repository on its provider.
+	Host  string
+	Owner string
+	Name  string
+	// Branch is the PR head; Base is the branch on origin the PR targets.
+	Branch string
+	Base   string
+	// Ahead counts the commits of Branch that are not on origin/Base.
+	Ahead int
+	// Push is the push that publishes Branch first; Push.Skip is set when none is needed.
+	Push push.Repo
+	// ExistingURL is the PR already recorded for the repo in workspace metadata.
+	ExistingURL string
+	// Skip explains why no PR is opened; empty when one is.
+	Skip string
+}
+
+type Plan struct {
+	WorkspaceID string
+	Title       string
+	Body        string
+	Metadata    workspace.Metadata
+	Repos       []Repo
+	Warnings    []error
+}
+
+// Creates returns the repos the plan opens a PR for.
+func (p Plan) Creates() []Repo {
+	var repos []Repo
+	for _, repoEntry := range p.Repos {
+		if repoEntry.Skip == "" {
+			repos = append(repos, repoEntry)
+		}
+	}
+	return repos
+}
+
+// Pushes returns the pushes that must succeed before the PRs are opened.
+func (p Plan) Pushes() push.Plan {
+	plan := push.Plan{WorkspaceID: p.WorkspaceID}
+	for _, repoEntry := range p.Creates() {
+		if repoEntry.Push.Skip == "" {
+			plan.Repos = append(plan.Repos, repoEntry.Push)
+		}
+	}
+	return plan
+}
+
+// Link is a PR opened (or recorded) for a repo of the workspace.
+type Link struct {
+	Alias string
+	URL   string
+}
+
+// Prepare plans one PR per worktree branch that has commits ahead of its base. The base is
+// the repo's base_ref in gion.yaml, else the workspace base_branch, else origin's default
+// branch. The title is the first line of the workspace description (the workspace id when
+// there is none) and the body is the rest of it.
+func Prepare(ctx context.Context, rootDir, workspaceID string) (Plan, error) {
+	wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+	exists, err := paths.DirExists(wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	if !exists {
+		return Plan{}, fmt.Errorf("workspace not found: %s", workspaceID)
+	}
+	meta, err := workspace.LoadMetadata(wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	pushPlan, err := push.Prepare(ctx, rootDir, workspaceID)
+	if err != nil {
+		return Plan{}, err
+	}
+	repos, _, err := workspace.ScanRepos(ctx, wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	repoSpecs := map[string]string{}
+	for _, repoEntry := range repos {
+		repoSpecs[repoEntry.Alias] = repoEntry.RepoSpec
+	}
+
+	plan := Plan{WorkspaceID: workspaceID, Metadata: meta, Warnings: pushPlan.Warnings}
+	baseRefs := map[string]string{}
+	description := meta.Description
+	if file, err := manifest.Load(rootDir); err == nil {
+		if ws, ok := file.Workspaces[workspaceID]; ok {
+			for _, repoEntry := range ws.Repos {
+				baseRefs[repoEntry.Alias] = strings.TrimSpace(repoEntry.BaseRef)
+			}
+			if strings.TrimSpace(description) == "" {
+				description = ws.Description
+			}
+		}
+	} else {
+		plan.Warnings = append(plan.Warnings, err)
+	}
+	plan.Title, plan.Body = splitDescription(description)
+	if plan.Title == "" {
+		plan.Title = workspaceID
+	}
+
+	for _, pushRepo := range pushPlan.Repos {
+		baseRef := baseRefs[pushRepo.Alias]
+		if baseRef == "" {
+			baseRef = meta.BaseBranch
+		}
+		plan.Repos = append(plan.Repos, planRepo(ctx, pushRepo, repoSpecs[pushRepo.Alias], baseRef, meta.PullRequestURL(pushRepo.Alias)))
+	}
+	return plan, nil
+}
+
+func planRepo(ctx context.Context, pushRepo push.Repo, repoSpec, baseRef, existingURL string) Repo {
+	planned := Repo{Alias: pushRepo.Alias, Dir: pushRepo.Dir, Branch: pushRepo.Branch, Push: pushRepo, ExistingURL: existingURL}
+	if existingURL != "" {
+		planned.Skip = "PR exists: " + existingURL
+		return planned
+	}
+	if pushRepo.Skip != "" && pushRepo.Skip != push.SkipNothingToPush {
+		planned.Skip = pushRepo.Skip
+		return planned
+	}
+	spec, err := repospec.Normalize(repoSpec)
+	if err != nil {
+		planned.Skip = err.Error()
+		return planned
+	}
+	planned.Host, planned.Owner, planned.Name = spec.Host, spec.Owner, spec.Repo
+
+	if baseRef == "" {
+		baseRef, err = defaultBaseRef(ctx, planned.Dir)
+		if err != nil {
+			planned.Skip = err.Error()
+			return planned
+		}
+	}
+	planned.Base = strings.TrimPrefix(baseRef, push.Remote+"/")
+	if planned.Base == planned.Branch {
+		planned.Skip = "branch is the base branch"
+		return planned
+	}
+	planned.Ahead, err = gitcmd.RevListCount(ctx, planned.Dir, baseRef+"..HEAD")
+	if err != nil {
+		planned.Skip = err.Error()
+		return planned
+	}
+	if planned.Ahead == 0 {
+		planned.Skip = fmt.Sprintf("no commits ahead of %s", baseRef)
+		return planned
+	}
+
+	if planned.Push.Skip == push.SkipNothingToPush {
+		// The commits are on origin, but maybe not on origin/<branch> (the PR head).
+		_, published, err := gitcmd.ShowRef(ctx, planned.Dir, "refs/remotes/"+push.Remote+"/"+planned.Branch)
+		if err != nil {
+			planned.Skip = err.Error()
+			return planned
+		}
+		if !published {
+			planned.Push.Skip = ""
+			planned.Push.SetUpstream = true
+		}
+	}
+	return planned
+}
+
+// defaultBaseRef returns origin's default branch (origin/HEAD) as origin/<branch>.
+func defaultBaseRef(ctx context.Context, dir string) (string, error) {
+	ref, ok, err := gitcmd.SymbolicRef(ctx, dir, "refs/remotes/"+push.Remote+"/HEAD")
+	if err != nil {
+		return "", err
+	}
+	if !ok || !strings.HasPrefix(ref, "refs/remotes/"+push.Remote+"/") {
+		return "", fmt.Errorf("base branch unknown (set base_ref in gion.yaml)")
+	}
+	return strings.TrimPrefix(ref, "refs/remotes/"), nil
+}
+
+func splitDescription(description string) (string, string) {
+	description = strings.TrimSpace(description)
+	title, body, _ := strings.Cut(description, "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(body)
+}
+
+// relatedPRsHeading starts the section of sibling PR links in a PR body.
+const relatedPRsHeading = "Related PRs:"
+
+// BodyWithSiblings returns body with a "Related PRs:" section linking the other PRs of
+// the workspace. An existing section (the heading and the "- " lines below it) is replaced
+// in place, so the rest of a body edited on the provider is kept; otherwise the section is
+// appended.
+func BodyWithSiblings(body, alias string, links []Link) string {
+	var lines []string
+	for _, link := range links {
+		if link.Alias == alias {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", link.Alias, link.URL))
+	}
+	section := ""
+	if len(lines) > 0 {
+		section = relatedPRsHeading + "\n" + strings.Join(lines, "\n")
+	}
+
+	bodyLines := strings.Split(body, "\n")
+	for i, line := range bodyLines {
+		if strings.TrimSpace(line) != relatedPRsHeading {
+			continue
+		}
+		end := i + 1
+		for end < len(bodyLines) && strings.HasPrefix(strings.TrimSpace(bodyLines[end]), "- ") {
+			end++
+		}
+		before := strings.TrimRight(strings.Join(bodyLines[:i], "\n"), "\r\n")
+		after := strings.TrimLeft(strings.Join(bodyLines[end:], "\n"), "\r\n")
+		return joinParagraphs(before, section, after)
+	}
+	if section == "" {
+		return body
+	}
+	return joinParagraphs(strings.TrimRight(body, "\n"), section)
+}
+
+// joinParagraphs joins the non-blank parts with a blank line between them.
+func joinParagraphs(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if strings.TrimSpace(part) != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "\n\n")
+}
@@ -0,0 +1,177 @@
+package prcreate_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/app/prcreate"
+	"github.com/tasuku43/gion/internal/app/push"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestPreparePlansPRsAheadOfBase(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	meta := workspace.Metadata{Mode: workspace.MetadataModeRepo, Description: "Add login flow\n\nDetails here."}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", meta); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+
+	plan, err := prcreate.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if plan.Title != "Add login flow" || plan.Body != "Details here." {
+		t.Fatalf("unexpected title/body: %q %q", plan.Title, plan.Body)
+	}
+	if len(plan.Repos) != 1 || plan.Repos[0].Skip != "no commits ahead of origin/main" {
+		t.Fatalf("expected a fresh branch to be skipped, got %+v", plan.Repos)
+	}
+
+	if err := os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("feature\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, worktreePath, "add", ".")
+	runGit(t, worktreePath, "commit", "-m", "feature")
+
+	plan, err = prcreate.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	planned := plan.Repos[0]
+	if planned.Skip != "" || planned.Branch != "WS-1" || planned.Base != "main" || planned.Ahead != 1 {
+		t.Fatalf("unexpected plan: %+v", planned)
+	}
+	if planned.Host != "example.com" || planned.Owner != "org" || planned.Name != "repo" {
+		t.Fatalf("unexpected repo location: %+v", planned)
+	}
+	if planned.Push.Skip != "" || len(plan.Pushes().Repos) != 1 {
+		t.Fatalf("expected the branch to be pushed first, got %+v", planned.Push)
+	}
+
+	if results := push.Run(ctx, plan.Pushes(), push.Options{}); len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("unexpected push results: %+v", results)
+	}
+	plan, err = prcreate.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if len(plan.Creates()) != 1 || len(plan.Pushes().Repos) != 0 {
+		t.Fatalf("expected a PR without push after push, got %+v", plan.Repos)
+	}
+
+	wsDir := workspace.WorkspaceDir(rootDir, "WS-1")
+	meta.SetPullRequest("repo", "https://example.com/org/repo/pull/1")
+	if err := workspace.SaveMetadata(wsDir, meta); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+	plan, err = prcreate.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if len(plan.Creates()) != 0 || plan.Repos[0].ExistingURL != "https://example.com/org/repo/pull/1" {
+		t.Fatalf("expected the recorded PR to be skipped, got %+v", plan.Repos)
+	}
+}
+
+func TestBodyWithSiblings(t *testing.T) {
+	links := []prcreate.Link{
+		{Alias: "api", URL: "https://example.com/org/api/pull/1"},
+		{Alias: "web", URL: "https://example.com/org/web/pull/2"},
+	}
+	got := prcreate.BodyWithSiblings("Details.", "api", links)
+	want := "Details.\n\nRelated PRs:\n- web: https://example.com/org/web/pull/2"
+	if got != want {
+		t.Fatalf("unexpected body:\n%s\nwant:\n%s", got, want)
+	}
+	if got := prcreate.BodyWithSiblings("", "web", links); got != "Related PRs:\n- api: https://example.com/org/api/pull/1" {
+		t.Fatalf("unexpected body without description: %q", got)
+	}
+	if got := prcreate.BodyWithSiblings("Details.", "api", links[:1]); got != "Details." {
+		t.Fatalf("expected no section without siblings, got %q", got)
+	}
+
+	// A body edited on the provider keeps everything but the old section.
+	edited := "Edited on the web.\r\n\r\nRelated PRs:\r\n- web: https://example.com/old\r\n\r\nReviewer notes."
+	got = prcreate.BodyWithSiblings(edited, "api", links)
+	want = "Edited on the web.\n\nRelated PRs:\n- web: https://example.com/org/web/pull/2\n\nReviewer notes."
+	if got != want {
+		t.Fatalf("unexpected replaced body:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func setupLocalRemoteRepo(t *testing.T, tmp string) string {
+	t.Helper()
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "example.com", "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/example.com/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	return "https://example.com/org/repo.git"
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s failed: %v\nstderr:\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
@@ -0,0 +1,186 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// Remote is the remote every workspace branch is pushed to.
+const Remote = "origin"
+
+// SkipNothingToPush is the Skip reason of a branch whose commits are all on origin.
+const SkipNothingToPush = "nothing to push"
+
+// Repo is the planned push of one worktree branch.
+type Repo struct {
+	Alias  string
+	Dir    string
+	Branch string
+	// Upstream is the branch's current upstream (empty when none is set).
+	Upstream string
+	// Commits counts the commits of the branch that are on no origin branch yet.
+	Commits int
+	// SetUpstream is true when the upstream is not origin/<branch> yet.
+	SetUpstream bool
+	// UpstreamOnly is true when the branch has nothing to push but origin/<branch> exists
+	// and is not its upstream: only the upstream is set.
+	UpstreamOnly bool
+	// Skip explains why the repo is not pushed; empty when it is.
+	Skip string
+}
+
+type Plan struct {
+	WorkspaceID string
+	Repos       []Repo
+	Warnings    []error
+}
+
+// Pushes returns the repos the plan will push.
+func (p Plan) Pushes() []Repo {
+	var repos []Repo
+	for _, repoEntry := range p.Repos {
+		if repoEntry.Skip == "" {
+			repos = append(repos, repoEntry)
+		}
+	}
+	return repos
+}
+
+// Prepare fetches the repo stores of the workspace and plans the push of every worktree
+// branch. Branches without commits that origin lacks are skipped (only their upstream is
+// set when origin/<branch> exists), as are detached worktrees. Local changes that are not
+// committed are not pushed and do not block the push.
+func Prepare(ctx context.Context, rootDir, workspaceID string) (Plan, error) {
+	wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+	exists, err := paths.DirExists(wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	if !exists {
+		return Plan{}, fmt.Errorf("workspace not found: %s", workspaceID)
+	}
+	repos, warnings, err := workspace.ScanRepos(ctx, wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Alias < repos[j].Alias
+	})
+	plan := Plan{WorkspaceID: workspaceID, Warnings: warnings}
+	fetched := map[string]error{}
+	for _, repoEntry := range repos {
+		planned := Repo{Alias: repoEntry.Alias, Dir: repoEntry.WorktreePath, Branch: repoEntry.Branch}
+		if strings.TrimSpace(repoEntry.RepoSpec) != "" {
+			fetchErr, ok := fetched[repoEntry.RepoSpec]
+			if !ok {
+				fetchErr = repo.Prefetch(ctx, rootDir, repoEntry.RepoSpec)
+				fetched[repoEntry.RepoSpec] = fetchErr
+			}
+			if fetchErr != nil {
+				planned.Skip = fmt.Sprintf("fetch failed: %v", fetchErr)
+				plan.Repos = append(plan.Repos, planned)
+				continue
+			}
+		}
+		plan.Repos = append(plan.Repos, planRepo(ctx, planned))
+	}
+	return plan, nil
+}
+
+func planRepo(ctx context.Context, planned Repo) Repo {
+	statusOut, err := gitcmd.StatusPorcelainV2(ctx, planned.Dir)
+	if err != nil {
+		planned.Skip = err.Error()
+		return planned
+	}
+	for _, line := range strings.Split(statusOut, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "#" {
+			continue
+		}
+		switch fields[1] {
+		case "branch.head":
+			planned.Branch = fields[2]
+		case "branch.upstream":
+			planned.Upstream = fields[2]
+		}
+	}
+	switch planned.Branch {
+	case "(detached)":
+		planned.Skip = "detached HEAD"
+		return planned
+	case "", "(unknown)":
+		planned.Skip = "no commits"
+		return planned
+	}
+
+	planned.Commits, err = gitcmd.RevListCount(ctx, planned.Dir, "HEAD", "--not", "--remotes="+Remote)
+	if err != nil {
+		planned.Skip = err.Error()
+		return planned
+	}
+	planned.SetUpstream = planned.Upstream != Remote+"/"+planned.Branch
+	if planned.Commits == 0 {
+		if planned.SetUpstream {
+			_, published, err := gitcmd.ShowRef(ctx, planned.Dir, "refs/remotes/"+Remote+"/"+planned.Branch)
+			if err != nil {
+				planned.Skip = err.Error()
+				return planned
+			}
+			if published {
+				planned.UpstreamOnly = true
+				return planned
+			}
+		}
+		planned.SetUpstream = false
+		planned.Skip = SkipNothingToPush
+		return planned
+	}
+	return planned
+}
+
+type Result struct {
+	Repo Repo
+	Err  error
+}
+
+type Options struct {
+	// Step is called before each repo is pushed.
+	Step func(text string)
+}
+
+// Run pushes the planned repos one after another (or only sets their upstream). A failed
+// push does not stop the others; the results cover every planned push in order.
+func Run(ctx context.Context, plan Plan, opts Options) []Result {
+	var results []Result
+	for _, repoEntry := range plan.Pushes() {
+		if repoEntry.UpstreamOnly {
+			upstream := Remote + "/" + repoEntry.Branch
+			if opts.Step != nil {
+				opts.Step(fmt.Sprintf("set upstream of %s %s to %s", repoEntry.Alias, repoEntry.Branch, upstream))
+			}
+			gitcmd.Logf(ctx, "git branch --set-upstream-to=%s %s", upstream, repoEntry.Branch)
+			err := gitcmd.BranchSetUpstream(ctx, repoEntry.Dir, repoEntry.Branch, upstream)
+			results = append(results, Result{Repo: repoEntry, Err: err})
+			continue
+		}
+		if opts.Step != nil {
+			opts.Step(fmt.Sprintf("push %s %s to %s", repoEntry.Alias, repoEntry.Branch, Remote))
+		}
+		if repoEntry.SetUpstream {
+			gitcmd.Logf(ctx, "git push --set-upstream %s %s", Remote, repoEntry.Branch)
+		} else {
+			gitcmd.Logf(ctx, "git push %s %s", Remote, repoEntry.Branch)
+		}
+		err := gitcmd.Push(ctx, repoEntry.Dir, Remote, repoEntry.Branch, repoEntry.SetUpstream)
+		results = append(results, Result{Repo: repoEntry, Err: err})
+	}
+	return results
+}
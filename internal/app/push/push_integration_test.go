@@ -0,0 +1,187 @@
+package push_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/app/push"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestPushSetsUpstream(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, remotePath := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+
+	plan, err := push.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if len(plan.Repos) != 1 || plan.Repos[0].Skip != "nothing to push" {
+		t.Fatalf("expected a fresh branch to be skipped, got %+v", plan.Repos)
+	}
+
+	if err := os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("feature\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, worktreePath, "add", ".")
+	runGit(t, worktreePath, "commit", "-m", "feature")
+
+	plan, err = push.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	planned := plan.Repos[0]
+	if planned.Skip != "" || planned.Branch != "WS-1" || planned.Commits != 1 || !planned.SetUpstream {
+		t.Fatalf("unexpected plan: %+v", planned)
+	}
+
+	results := push.Run(ctx, plan, push.Options{})
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if got, want := runGit(t, "", "--git-dir", remotePath, "rev-parse", "refs/heads/WS-1"), runGit(t, worktreePath, "rev-parse", "HEAD"); got != want {
+		t.Fatalf("expected remote WS-1 at %s, got %s", want, got)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); got != "origin/WS-1" {
+		t.Fatalf("expected upstream origin/WS-1, got %s", got)
+	}
+
+	plan, err = push.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if len(plan.Pushes()) != 0 {
+		t.Fatalf("expected nothing to push after push, got %+v", plan.Repos)
+	}
+}
+
+func TestPushSetsUpstreamOfPublishedBranch(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+	t.Setenv("GION_FETCH_GRACE_SECONDS", "0")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, remotePath := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+
+	// The branch reaches origin without the store knowing (pushed from another clone);
+	// Prepare fetches first, so it only sets the upstream.
+	runGit(t, "", "--git-dir", remotePath, "update-ref", "refs/heads/WS-1", runGit(t, worktreePath, "rev-parse", "HEAD"))
+	plan, err := push.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	planned := plan.Repos[0]
+	if planned.Skip != "" || planned.Commits != 0 || !planned.UpstreamOnly || !planned.SetUpstream {
+		t.Fatalf("expected an upstream-only plan, got %+v", planned)
+	}
+
+	results := push.Run(ctx, plan, push.Options{})
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); got != "origin/WS-1" {
+		t.Fatalf("expected upstream origin/WS-1, got %s", got)
+	}
+
+	plan, err = push.Prepare(ctx, rootDir, "WS-1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if plan.Repos[0].Skip != push.SkipNothingToPush {
+		t.Fatalf("expected nothing to do once the upstream is set, got %+v", plan.Repos[0])
+	}
+}
+
+func setupLocalRemoteRepo(t *testing.T, tmp string) (string, string) {
+	t.Helper()
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "example.com", "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/example.com/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	return "https://example.com/org/repo.git", remotePath
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s failed: %v\nstderr:\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
@@ -0,0 +1,96 @@
+package status
+
+import "github.com/tasuku43/gion/internal/domain/workspace"
+
+// DocumentSchemaVersion is bumped whenever a field is removed or its meaning changes.
+// Adding new optional fields does not require a bump.
+const DocumentSchemaVersion = 1
+
+// Document is the machine-readable form of Collect results (used by `gion status --json`).
+type Document struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Workspaces    []DocumentWorkspace `json:"workspaces"`
+}
+
+type DocumentWorkspace struct {
+	WorkspaceID string         `json:"workspace_id"`
+	Description string         `json:"description,omitempty"`
+	State       string         `json:"state"`
+	Repos       []DocumentRepo `json:"repos"`
+	Warnings    []string       `json:"warnings,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+type DocumentRepo struct {
+	Alias     string `json:"alias"`
+	State     string `json:"state"`
+	Branch    string `json:"branch,omitempty"`
+	Upstream  string `json:"upstream,omitempty"`
+	Head      string `json:"head,omitempty"`
+	Detached  bool   `json:"detached"`
+	Ahead     int    `json:"ahead"`
+	Behind    int    `json:"behind"`
+	Staged    int    `json:"staged"`
+	Unstaged  int    `json:"unstaged"`
+	Untracked int    `json:"untracked"`
+	Unmerged  int    `json:"unmerged"`
+	Stash     int    `json:"stash"`
+	// Operation is the rebase, merge, cherry-pick, or revert left in progress.
+	Operation string `json:"operation,omitempty"`
+	// LocalCommits counts, for branches without upstream, the commits not on BaseRef
+	// ("origin" for all origin branches).
+	LocalCommits int    `json:"local_commits"`
+	BaseRef      string `json:"base_ref,omitempty"`
+	// ChangedFiles lists `git status --short` style entries (e.g. " M main.go").
+	ChangedFiles []string `json:"changed_files"`
+	Error        string   `json:"error,omitempty"`
+}
+
+func Export(results []Workspace) Document {
+	doc := Document{SchemaVersion: DocumentSchemaVersion, Workspaces: []DocumentWorkspace{}}
+	for _, result := range results {
+		entry := DocumentWorkspace{
+			WorkspaceID: result.WorkspaceID,
+			Description: result.Description,
+			State:       string(result.State.Kind),
+			Repos:       []DocumentRepo{},
+		}
+		if result.Err != nil {
+			entry.Error = result.Err.Error()
+		}
+		for _, warn := range result.Status.Warnings {
+			entry.Warnings = append(entry.Warnings, warn.Error())
+		}
+		for i, repo := range result.Status.Repos {
+			entry.Repos = append(entry.Repos, documentRepo(repo, result.State.Repos[i].Kind))
+		}
+		doc.Workspaces = append(doc.Workspaces, entry)
+	}
+	return doc
+}
+
+func documentRepo(repo workspace.RepoStatus, kind workspace.RepoStateKind) DocumentRepo {
+	entry := DocumentRepo{
+		Alias:        repo.Alias,
+		State:        string(kind),
+		Branch:       repo.Branch,
+		Upstream:     repo.Upstream,
+		Head:         repo.Head,
+		Detached:     repo.Detached,
+		Ahead:        repo.AheadCount,
+		Behind:       repo.BehindCount,
+		Staged:       repo.StagedCount,
+		Unstaged:     repo.UnstagedCount,
+		Untracked:    repo.UntrackedCount,
+		Unmerged:     repo.UnmergedCount,
+		Stash:        repo.StashCount,
+		Operation:    repo.Operation,
+		LocalCommits: repo.LocalCommitCount,
+		BaseRef:      repo.BaseRef,
+		ChangedFiles: append([]string{}, repo.ChangedFiles...),
+	}
+	if repo.Error != nil {
+		entry.Error = repo.Error.Error()
+	}
+	return entry
+}
@@ -0,0 +1,91 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// DefaultConcurrency is the number of workspaces whose status is computed at once.
+const DefaultConcurrency = 4
+
+// Workspace is the status of one workspace. Err is set when the workspace could not be
+// scanned at all; per-repo git errors are reported in Status.Repos[i].Error.
+type Workspace struct {
+	WorkspaceID string
+	Description string
+	Status      workspace.StatusResult
+	State       workspace.WorkspaceState
+	Err         error
+}
+
+// Collect computes the status of the given workspaces (every workspace under
+// GION_ROOT/workspaces when workspaceIDs is empty), up to concurrency at a time.
+// Results follow the order of workspaceIDs (workspace ID order when listing all).
+func Collect(ctx context.Context, rootDir string, workspaceIDs []string, concurrency int) ([]Workspace, error) {
+	var descriptions map[string]string
+	if len(workspaceIDs) == 0 {
+		entries, _, err := workspace.List(rootDir)
+		if err != nil {
+			return nil, err
+		}
+		descriptions = map[string]string{}
+		for _, entry := range entries {
+			workspaceIDs = append(workspaceIDs, entry.WorkspaceID)
+			descriptions[entry.WorkspaceID] = entry.Description
+		}
+	} else {
+		for _, workspaceID := range workspaceIDs {
+			exists, err := paths.DirExists(workspace.WorkspaceDir(rootDir, workspaceID))
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				return nil, fmt.Errorf("workspace not found: %s", workspaceID)
+			}
+		}
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	results := make([]Workspace, len(workspaceIDs))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(workspaceIDs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				results[index] = collectOne(ctx, rootDir, workspaceIDs[index], descriptions)
+			}
+		}()
+	}
+	for i := range workspaceIDs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results, nil
+}
+
+func collectOne(ctx context.Context, rootDir, workspaceID string, descriptions map[string]string) Workspace {
+	result := Workspace{WorkspaceID: workspaceID}
+	if description, ok := descriptions[workspaceID]; ok {
+		result.Description = description
+	} else {
+		result.Description, _ = workspace.ReadDescription(workspace.WorkspaceDir(rootDir, workspaceID))
+	}
+	status, err := workspace.Status(ctx, rootDir, workspaceID)
+	if err != nil {
+		result.Err = err
+		result.State = workspace.WorkspaceState{WorkspaceID: workspaceID, Kind: workspace.WorkspaceStateUnknown}
+		return result
+	}
+	result.Status = status
+	result.State = workspace.StateFromStatus(status)
+	return result
+}
@@ -0,0 +1,64 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestCollectUnknownWorkspace(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	if err := os.MkdirAll(workspace.WorkspaceDir(rootDir, "WS-1"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if _, err := Collect(context.Background(), rootDir, []string{"WS-1", "WS-2"}, 2); err == nil || !strings.Contains(err.Error(), "WS-2") {
+		t.Fatalf("expected workspace not found error, got %v", err)
+	}
+
+	results, err := Collect(context.Background(), rootDir, nil, 2)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if len(results) != 1 || results[0].WorkspaceID != "WS-1" || results[0].Err != nil || len(results[0].Status.Repos) != 0 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	status := workspace.StatusResult{
+		WorkspaceID: "WS-1",
+		Repos: []workspace.RepoStatus{
+			{Alias: "api", Branch: "PROJ-1", Upstream: "origin/PROJ-1", Dirty: true, UnstagedCount: 1, ChangedFiles: []string{" M main.go"}},
+			{Alias: "web", Branch: "PROJ-1", Error: errors.New("boom")},
+		},
+	}
+	doc := Export([]Workspace{
+		{WorkspaceID: "WS-1", Description: "fix", Status: status, State: workspace.StateFromStatus(status)},
+		{WorkspaceID: "WS-2", Err: errors.New("scan failed"), State: workspace.WorkspaceState{Kind: workspace.WorkspaceStateUnknown}},
+	})
+
+	if doc.SchemaVersion != DocumentSchemaVersion || len(doc.Workspaces) != 2 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+	ws := doc.Workspaces[0]
+	if ws.State != "dirty" || ws.Description != "fix" || len(ws.Repos) != 2 {
+		t.Fatalf("unexpected workspace: %+v", ws)
+	}
+	if api := ws.Repos[0]; api.State != "dirty" || api.Unstaged != 1 || len(api.ChangedFiles) != 1 {
+		t.Fatalf("unexpected api repo: %+v", api)
+	}
+	if web := ws.Repos[1]; web.State != "unknown" || web.Error != "boom" || web.ChangedFiles == nil {
+		t.Fatalf("unexpected web repo: %+v", web)
+	}
+	if failed := doc.Workspaces[1]; failed.Error != "scan failed" || failed.State != "unknown" || failed.Repos == nil {
+		t.Fatalf("unexpected failed workspace: %+v", failed)
+	}
+}
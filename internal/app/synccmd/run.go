@@ -0,0 +1,120 @@
+package synccmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+type Outcome string
+
+const (
+	OutcomeUpdated  Outcome = "updated"
+	OutcomeConflict Outcome = "conflict"
+	OutcomeFailed   Outcome = "failed"
+	// OutcomeNotRun marks the repos left alone after an earlier repo stopped the sync.
+	OutcomeNotRun Outcome = "not run"
+)
+
+type Result struct {
+	Repo    Repo
+	Outcome Outcome
+	// ConflictFiles lists the paths that conflicted. The rebase or merge has been
+	// aborted, so the worktree is back where it was before the sync.
+	ConflictFiles []string
+	Err           error
+}
+
+type Options struct {
+	// Step is called before each repo is updated.
+	Step func(text string)
+}
+
+// ErrStopped is returned by Run when a repo could not be updated; the repos after it
+// are not touched.
+var ErrStopped = errors.New("sync stopped")
+
+// Run updates the repos the plan marks for update, one after another, and stops at
+// the first conflict or error. The results cover every planned update in order.
+func Run(ctx context.Context, plan Plan, opts Options) ([]Result, error) {
+	updates := plan.Updates()
+	results := make([]Result, 0, len(updates))
+	var stopErr error
+	for _, repoEntry := range updates {
+		if stopErr != nil {
+			results = append(results, Result{Repo: repoEntry, Outcome: OutcomeNotRun})
+			continue
+		}
+		if opts.Step != nil {
+			opts.Step(fmt.Sprintf("%s %s onto %s", plan.Strategy, repoEntry.Alias, repoEntry.BaseRef))
+		}
+		result := runOne(ctx, plan.Strategy, repoEntry)
+		if result.Outcome != OutcomeUpdated {
+			stopErr = fmt.Errorf("%w at %s/%s: %s", ErrStopped, plan.WorkspaceID, repoEntry.Alias, result.Outcome)
+		}
+		results = append(results, result)
+	}
+	return results, stopErr
+}
+
+func runOne(ctx context.Context, strategy Strategy, repoEntry Repo) Result {
+	var err error
+	switch strategy {
+	case StrategyRebase:
+		gitcmd.Logf(ctx, "git rebase --quiet %s", repoEntry.BaseRef)
+		err = gitcmd.Rebase(ctx, repoEntry.Dir, repoEntry.BaseRef)
+	case StrategyMerge:
+		gitcmd.Logf(ctx, "git merge --no-edit %s", repoEntry.BaseRef)
+		err = gitcmd.Merge(ctx, repoEntry.Dir, repoEntry.BaseRef, false)
+	case StrategyFFOnly:
+		gitcmd.Logf(ctx, "git merge --no-edit --ff-only %s", repoEntry.BaseRef)
+		err = gitcmd.Merge(ctx, repoEntry.Dir, repoEntry.BaseRef, true)
+	default:
+		err = fmt.Errorf("unsupported strategy: %s", strategy)
+	}
+	if err == nil {
+		return Result{Repo: repoEntry, Outcome: OutcomeUpdated}
+	}
+
+	result := Result{Repo: repoEntry, Outcome: OutcomeFailed, Err: err}
+	if files := conflictFiles(ctx, repoEntry.Dir); len(files) > 0 {
+		result.Outcome = OutcomeConflict
+		result.ConflictFiles = files
+	}
+	// Leave nothing half-done behind: an aborted rebase or merge restores the branch.
+	var abortErr error
+	switch strategy {
+	case StrategyRebase:
+		abortErr = gitcmd.RebaseAbort(ctx, repoEntry.Dir)
+	case StrategyMerge:
+		if result.Outcome == OutcomeConflict {
+			abortErr = gitcmd.MergeAbort(ctx, repoEntry.Dir)
+		}
+	}
+	if abortErr != nil && result.Outcome == OutcomeConflict {
+		result.Err = errors.Join(result.Err, fmt.Errorf("abort failed, resolve by hand: %w", abortErr))
+	}
+	return result
+}
+
+func conflictFiles(ctx context.Context, dir string) []string {
+	statusOut, err := gitcmd.StatusPorcelainV2(ctx, dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(statusOut, "\n") {
+		if !strings.HasPrefix(line, "u ") {
+			continue
+		}
+		// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+		fields := strings.SplitN(line, " ", 11)
+		if len(fields) == 11 {
+			files = append(files, fields[10])
+		}
+	}
+	return files
+}
@@ -0,0 +1,230 @@
+package synccmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+type Strategy string
+
+const (
+	StrategyRebase Strategy = "rebase"
+	StrategyMerge  Strategy = "merge"
+	StrategyFFOnly Strategy = "ff-only"
+)
+
+func ParseStrategy(value string) (Strategy, error) {
+	switch Strategy(strings.TrimSpace(value)) {
+	case StrategyRebase:
+		return StrategyRebase, nil
+	case StrategyMerge:
+		return StrategyMerge, nil
+	case StrategyFFOnly:
+		return StrategyFFOnly, nil
+	default:
+		return "", fmt.Errorf("unsupported strategy: %s (use rebase, merge, or ff-only)", value)
+	}
+}
+
+type Action string
+
+const (
+	ActionUpdate   Action = "update"
+	ActionUpToDate Action = "up-to-date"
+	ActionSkip     Action = "skip"
+)
+
+// Repo is the planned sync of one worktree. Ahead and Behind count the commits of the
+// branch and of BaseRef that the other does not have.
+type Repo struct {
+	Alias   string
+	Dir     string
+	Branch  string
+	BaseRef string
+	Ahead   int
+	Behind  int
+	Action  Action
+	// Reason explains why the repo is skipped.
+	Reason string
+	// Published counts the commits a rebase would rewrite that are already on the
+	// branch's upstream; such repos are skipped, since the rewritten branch could not be
+	// pushed without force.
+	Published int
+}
+
+type Plan struct {
+	WorkspaceID string
+	Strategy    Strategy
+	Repos       []Repo
+	Warnings    []error
+}
+
+// Updates returns the repos the plan will update.
+func (p Plan) Updates() []Repo {
+	var repos []Repo
+	for _, repoEntry := range p.Repos {
+		if repoEntry.Action == ActionUpdate {
+			repos = append(repos, repoEntry)
+		}
+	}
+	return repos
+}
+
+// Prepare fetches the repo stores of the workspace and plans how each worktree branch
+// gets onto its base ref. The base ref is the repo's base_ref in gion.yaml, else the
+// workspace base_branch in metadata, else the store's default branch.
+// Dirty and detached worktrees are skipped, as are branches ff-only cannot move and
+// branches whose pushed commits a rebase would rewrite.
+func Prepare(ctx context.Context, rootDir, workspaceID string, strategy Strategy) (Plan, error) {
+	wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+	exists, err := paths.DirExists(wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	if !exists {
+		return Plan{}, fmt.Errorf("workspace not found: %s", workspaceID)
+	}
+	plan := Plan{WorkspaceID: workspaceID, Strategy: strategy}
+
+	repos, warnings, err := workspace.ScanRepos(ctx, wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan.Warnings = append(plan.Warnings, warnings...)
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Alias < repos[j].Alias
+	})
+
+	manifestBaseRefs := map[string]string{}
+	if file, err := manifest.Load(rootDir); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			plan.Warnings = append(plan.Warnings, err)
+		}
+	} else if ws, ok := file.Workspaces[workspaceID]; ok {
+		for _, repoEntry := range ws.Repos {
+			manifestBaseRefs[repoEntry.Alias] = strings.TrimSpace(repoEntry.BaseRef)
+		}
+	}
+	meta, err := workspace.LoadMetadata(wsDir)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	fetched := map[string]error{}
+	for _, repoEntry := range repos {
+		planned := Repo{Alias: repoEntry.Alias, Dir: repoEntry.WorktreePath, Branch: repoEntry.Branch}
+		if strings.TrimSpace(repoEntry.RepoSpec) == "" || strings.TrimSpace(repoEntry.StorePath) == "" {
+			plan.Repos = append(plan.Repos, skip(planned, "not a gion worktree"))
+			continue
+		}
+		fetchErr, ok := fetched[repoEntry.RepoSpec]
+		if !ok {
+			fetchErr = repo.Prefetch(ctx, rootDir, repoEntry.RepoSpec)
+			fetched[repoEntry.RepoSpec] = fetchErr
+		}
+		if fetchErr != nil {
+			plan.Repos = append(plan.Repos, skip(planned, fmt.Sprintf("fetch failed: %v", fetchErr)))
+			continue
+		}
+		plan.Repos = append(plan.Repos, planRepo(ctx, planned, repoEntry.StorePath, firstNonEmpty(manifestBaseRefs[repoEntry.Alias], meta.BaseBranch), strategy))
+	}
+	return plan, nil
+}
+
+func planRepo(ctx context.Context, planned Repo, storePath, baseRef string, strategy Strategy) Repo {
+	statusOut, err := gitcmd.StatusPorcelainV2(ctx, planned.Dir)
+	if err != nil {
+		return skip(planned, err.Error())
+	}
+	changed := 0
+	upstream := ""
+	for _, line := range strings.Split(strings.TrimRight(statusOut, "\n"), "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "# branch.head "):
+			if strings.TrimSpace(strings.TrimPrefix(line, "# branch.head ")) == "(detached)" {
+				return skip(planned, "detached HEAD")
+			}
+		case strings.HasPrefix(line, "# branch.upstream "):
+			upstream = strings.TrimSpace(strings.TrimPrefix(line, "# branch.upstream "))
+		case strings.HasPrefix(line, "# "):
+		default:
+			changed++
+		}
+	}
+	if changed > 0 {
+		return skip(planned, fmt.Sprintf("dirty (%d changed file(s))", changed))
+	}
+
+	if baseRef == "" {
+		baseRef, err = workspace.ResolveBaseRef(ctx, storePath)
+		if err != nil {
+			return skip(planned, fmt.Sprintf("cannot detect base ref: %v", err))
+		}
+		baseRef = strings.TrimPrefix(baseRef, "refs/heads/")
+	}
+	planned.BaseRef = baseRef
+	if _, err := gitcmd.RevParse(ctx, planned.Dir, "--verify", "--quiet", baseRef+"^{commit}"); err != nil {
+		return skip(planned, fmt.Sprintf("base ref not found: %s", baseRef))
+	}
+	planned.Ahead, planned.Behind, err = gitcmd.RevListLeftRightCount(ctx, planned.Dir, "HEAD", baseRef)
+	if err != nil {
+		return skip(planned, err.Error())
+	}
+	switch {
+	case planned.Behind == 0:
+		planned.Action = ActionUpToDate
+	case strategy == StrategyFFOnly && planned.Ahead > 0:
+		return skip(planned, fmt.Sprintf("diverged from %s (ff-only)", baseRef))
+	default:
+		planned.Action = ActionUpdate
+	}
+	if strategy == StrategyRebase && planned.Ahead > 0 && upstream != "" {
+		planned.Published, err = publishedCommits(ctx, planned.Dir, upstream, baseRef)
+		if err != nil {
+			return skip(planned, err.Error())
+		}
+		if planned.Published > 0 {
+			return skip(planned, fmt.Sprintf("rebase would rewrite %d commit(s) already pushed to %s; use --strategy merge", planned.Published, upstream))
+		}
+	}
+	return planned
+}
+
+// publishedCommits counts the commits of HEAD that a rebase onto baseRef would rewrite and
+// that upstream already has. An upstream that no longer exists has none.
+func publishedCommits(ctx context.Context, dir, upstream, baseRef string) (int, error) {
+	if _, err := gitcmd.RevParse(ctx, dir, "--verify", "--quiet", upstream+"^{commit}"); err != nil {
+		return 0, nil
+	}
+	mergeBase, err := gitcmd.MergeBase(ctx, dir, "HEAD", upstream)
+	if err != nil {
+		return 0, err
+	}
+	return gitcmd.RevListCount(ctx, dir, mergeBase, "--not", baseRef)
+}
+
+func skip(planned Repo, reason string) Repo {
+	planned.Action = ActionSkip
+	planned.Reason = reason
+	return planned
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
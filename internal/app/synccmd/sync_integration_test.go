@@ -0,0 +1,239 @@
+package synccmd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/app/synccmd"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestSyncRebase(t *testing.T) {
+	ctx := context.Background()
+	rootDir, seedDir := setupSyncWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+
+	commitFile(t, worktreePath, "feature.txt", "feature\n")
+	commitFile(t, seedDir, "upstream.txt", "upstream\n")
+	runGit(t, seedDir, "push", "origin", "main")
+
+	plan, err := synccmd.Prepare(ctx, rootDir, "WS-1", synccmd.StrategyRebase)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if len(plan.Repos) != 1 {
+		t.Fatalf("expected 1 repo, got %+v", plan.Repos)
+	}
+	planned := plan.Repos[0]
+	if planned.Action != synccmd.ActionUpdate || planned.BaseRef != "origin/main" || planned.Ahead != 1 || planned.Behind != 1 {
+		t.Fatalf("unexpected plan: %+v", planned)
+	}
+
+	results, err := synccmd.Run(ctx, plan, synccmd.Options{})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(results) != 1 || results[0].Outcome != synccmd.OutcomeUpdated {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "upstream.txt")); err != nil {
+		t.Fatalf("upstream commit missing after rebase: %v", err)
+	}
+	if got := runGit(t, worktreePath, "rev-list", "--count", "origin/main..HEAD"); got != "1" {
+		t.Fatalf("expected the feature commit on top of origin/main, got %s commit(s)", got)
+	}
+
+	plan, err = synccmd.Prepare(ctx, rootDir, "WS-1", synccmd.StrategyRebase)
+	if err != nil {
+		t.Fatalf("prepare again: %v", err)
+	}
+	if plan.Repos[0].Action != synccmd.ActionUpToDate {
+		t.Fatalf("expected up-to-date, got %+v", plan.Repos[0])
+	}
+}
+
+func TestSyncConflictAborts(t *testing.T) {
+	ctx := context.Background()
+	rootDir, seedDir := setupSyncWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+
+	commitFile(t, worktreePath, "README.md", "ours\n")
+	commitFile(t, seedDir, "README.md", "theirs\n")
+	runGit(t, seedDir, "push", "origin", "main")
+	head := runGit(t, worktreePath, "rev-parse", "HEAD")
+
+	for _, strategy := range []synccmd.Strategy{synccmd.StrategyRebase, synccmd.StrategyMerge} {
+		plan, err := synccmd.Prepare(ctx, rootDir, "WS-1", strategy)
+		if err != nil {
+			t.Fatalf("%s: prepare: %v", strategy, err)
+		}
+		results, err := synccmd.Run(ctx, plan, synccmd.Options{})
+		if !errors.Is(err, synccmd.ErrStopped) {
+			t.Fatalf("%s: expected ErrStopped, got %v", strategy, err)
+		}
+		if len(results) != 1 || results[0].Outcome != synccmd.OutcomeConflict {
+			t.Fatalf("%s: unexpected results: %+v", strategy, results)
+		}
+		if got := strings.Join(results[0].ConflictFiles, ","); got != "README.md" {
+			t.Fatalf("%s: expected README.md to conflict, got %q", strategy, got)
+		}
+		if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != head {
+			t.Fatalf("%s: expected HEAD to stay at %s, got %s", strategy, head, got)
+		}
+		if got := runGit(t, worktreePath, "status", "--porcelain"); got != "" {
+			t.Fatalf("%s: expected a clean worktree after abort, got:\n%s", strategy, got)
+		}
+	}
+}
+
+func TestPrepareSkips(t *testing.T) {
+	ctx := context.Background()
+	rootDir, seedDir := setupSyncWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+
+	commitFile(t, worktreePath, "feature.txt", "feature\n")
+	commitFile(t, seedDir, "upstream.txt", "upstream\n")
+	runGit(t, seedDir, "push", "origin", "main")
+
+	plan, err := synccmd.Prepare(ctx, rootDir, "WS-1", synccmd.StrategyFFOnly)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if got := plan.Repos[0]; got.Action != synccmd.ActionSkip || !strings.Contains(got.Reason, "ff-only") {
+		t.Fatalf("expected diverged skip, got %+v", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(worktreePath, "DIRTY.txt"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write dirty file: %v", err)
+	}
+	plan, err = synccmd.Prepare(ctx, rootDir, "WS-1", synccmd.StrategyRebase)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if got := plan.Repos[0]; got.Action != synccmd.ActionSkip || !strings.Contains(got.Reason, "dirty") {
+		t.Fatalf("expected dirty skip, got %+v", got)
+	}
+	if len(plan.Updates()) != 0 {
+		t.Fatalf("expected no updates, got %+v", plan.Updates())
+	}
+}
+
+func TestPrepareSkipsRebaseOfPushedCommits(t *testing.T) {
+	ctx := context.Background()
+	rootDir, seedDir := setupSyncWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+
+	commitFile(t, worktreePath, "feature.txt", "feature\n")
+	runGit(t, worktreePath, "push", "-u", "origin", "HEAD")
+	commitFile(t, worktreePath, "local.txt", "local\n")
+	commitFile(t, seedDir, "upstream.txt", "upstream\n")
+	runGit(t, seedDir, "push", "origin", "main")
+
+	plan, err := synccmd.Prepare(ctx, rootDir, "WS-1", synccmd.StrategyRebase)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if got := plan.Repos[0]; got.Action != synccmd.ActionSkip || got.Published != 1 || !strings.Contains(got.Reason, "--strategy merge") {
+		t.Fatalf("expected a skip for the pushed commit, got %+v", got)
+	}
+
+	plan, err = synccmd.Prepare(ctx, rootDir, "WS-1", synccmd.StrategyMerge)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if got := plan.Repos[0]; got.Action != synccmd.ActionUpdate || got.Ahead != 2 {
+		t.Fatalf("expected merge to update the pushed branch, got %+v", got)
+	}
+}
+
+func setupSyncWorkspace(t *testing.T) (string, string) {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+	t.Setenv("GION_FETCH_GRACE_SECONDS", "0")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, seedDir := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	return rootDir, seedDir
+}
+
+func setupLocalRemoteRepo(t *testing.T, tmp string) (string, string) {
+	t.Helper()
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "example.com", "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	commitFile(t, seedDir, "README.md", "hello\n")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/example.com/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	return "https://example.com/org/repo.git", seedDir
+}
+
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", "update "+name)
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s failed: %v\nstderr:\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
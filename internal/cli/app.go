@@ -6,7 +6,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/tasuku43/gion/internal/domain/manifest"
 	"github.com/tasuku43/gion/internal/infra/debuglog"
 	"github.com/tasuku43/gion/internal/infra/paths"
 )
@@ -19,9 +21,11 @@ func Run() error {
 	var debugFlag bool
 	var helpFlag bool
 	var versionFlag bool
+	var lockTimeout time.Duration
 	fs.StringVar(&rootFlag, "root", "", "override root")
 	fs.BoolVar(&noPrompt, "no-prompt", false, "disable interactive prompt")
 	fs.BoolVar(&debugFlag, "debug", false, "write debug logs to file")
+	fs.DurationVar(&lockTimeout, "lock-timeout", defaultLockTimeout, "how long to wait for another gion process to release the root lock")
 	fs.BoolVar(&helpFlag, "help", false, "show help")
 	fs.BoolVar(&helpFlag, "h", false, "show help")
 	fs.BoolVar(&versionFlag, "version", false, "print version")
@@ -75,6 +79,16 @@ func Run() error {
 	}
 
 	ctx := context.Background()
+	manifest.SetHistoryCommand(commandLine(args))
+	if commandWritesRoot(args) {
+		lock, err := acquireRootLock(ctx, rootDir, lockTimeout, args)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = lock.Release()
+		}()
+	}
 	switch args[0] {
 	case "init":
 		return runInit(rootDir, args[1:])
@@ -90,6 +104,16 @@ func Run() error {
 		return runImport(ctx, rootDir, args[1:], noPrompt)
 	case "apply":
 		return runApply(ctx, rootDir, args[1:], noPrompt)
+	case "exec":
+		return runExec(ctx, rootDir, args[1:])
+	case "status", "st":
+		return runStatus(ctx, rootDir, args[1:])
+	case "sync":
+		return runSync(ctx, rootDir, args[1:], noPrompt)
+	case "push":
+		return runPush(ctx, rootDir, args[1:], noPrompt)
+	case "pr":
+		return runPR(ctx, rootDir, args[1:], noPrompt)
 	default:
 		return fmt.Errorf("unknown command: %s", args[0])
 	}
@@ -1,6 +1,9 @@
 package cli
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestFormatReviewWorkspaceID(t *testing.T) {
 	cases := []struct {
@@ -67,3 +70,45 @@ func TestFormatIssueWorkspaceID(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandWritesRoot(t *testing.T) {
+	cases := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"apply"}, want: true},
+		{args: []string{"apply", "--help"}, want: false},
+		{args: []string{"import"}, want: true},
+		{args: []string{"init"}, want: true},
+		{args: []string{"plan"}, want: false},
+		{args: []string{"sync", "WS-1"}, want: true},
+		{args: []string{"push", "WS-1"}, want: true},
+		{args: []string{"pr", "create", "WS-1"}, want: true},
+		{args: []string{"pr", "create", "--help"}, want: false},
+		{args: []string{"status"}, want: false},
+		{args: []string{"doctor"}, want: false},
+		{args: []string{"doctor", "--fix"}, want: true},
+		{args: []string{"repo", "get", "git@github.com:o/r.git"}, want: true},
+		{args: []string{"repo", "ls"}, want: false},
+		{args: []string{"manifest", "add", "WS-1"}, want: true},
+		{args: []string{"m", "ls"}, want: false},
+		{args: []string{"man", "validate"}, want: false},
+		{args: []string{"manifest", "gc"}, want: true},
+		{args: []string{"m", "mv", "WS-1", "WS-2"}, want: true},
+		{args: []string{"manifest", "archive", "WS-1"}, want: true},
+		{args: []string{"manifest", "restore", "WS-1"}, want: true},
+		{args: []string{"manifest", "history"}, want: false},
+		{args: []string{"m", "undo", "2"}, want: true},
+		{args: []string{"manifest", "preset", "add", "p"}, want: true},
+		{args: []string{"m", "p", "ls"}, want: false},
+		{args: []string{"version"}, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			if got := commandWritesRoot(tc.args); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
@@ -18,13 +19,58 @@ import (
 )
 
 func runApply(ctx context.Context, rootDir string, args []string, noPrompt bool) error {
-	if len(args) == 1 && isHelpArg(args[0]) {
+	applyFlags := flag.NewFlagSet("apply", flag.ContinueOnError)
+	var targetFlags stringSliceFlag
+	var concurrency int
+	var resume bool
+	var rollback bool
+	var helpFlag bool
+	applyFlags.Var(&targetFlags, "target", "limit apply to <workspace-id>[/<alias>] (repeatable)")
+	applyFlags.IntVar(&concurrency, "concurrency", apply.DefaultConcurrency, "max repo stores to create worktrees for in parallel")
+	applyFlags.BoolVar(&resume, "resume", false, "continue an interrupted apply")
+	applyFlags.BoolVar(&rollback, "rollback", false, "undo the non-destructive steps of an interrupted apply")
+	applyFlags.BoolVar(&helpFlag, "help", false, "show help")
+	applyFlags.BoolVar(&helpFlag, "h", false, "show help")
+	applyFlags.SetOutput(os.Stdout)
+	applyFlags.Usage = func() {
+		printApplyHelp(os.Stdout)
+	}
+	if err := applyFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
 		printApplyHelp(os.Stdout)
 		return nil
 	}
-	if len(args) != 0 {
-		return fmt.Errorf("usage: gion apply")
+	if applyFlags.NArg() > 1 {
+		return fmt.Errorf("usage: gion apply [--target <workspace-id>[/<alias>]]... [--concurrency <n>] [<planfile>]")
+	}
+	if concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if resume || rollback {
+		if resume && rollback {
+			return fmt.Errorf("--resume and --rollback cannot be used together")
+		}
+		if applyFlags.NArg() != 0 || len(targetFlags) > 0 {
+			return fmt.Errorf("--resume/--rollback cannot be combined with --target or a plan file")
+		}
+		if rollback {
+			return runApplyRollback(ctx, rootDir, noPrompt)
+		}
+		return runApplyResume(ctx, rootDir, noPrompt, concurrency)
+	}
+	targets, err := manifestplan.ParseTargets(targetFlags)
+	if err != nil {
+		return err
+	}
+	if applyFlags.NArg() == 1 {
+		return runApplySavedPlan(ctx, rootDir, applyFlags.Arg(0), noPrompt, targets, concurrency)
 	}
+
 	plan, err := manifestplan.Plan(ctx, rootDir)
 	if err != nil {
 		var vErr *manifest.ValidationError
@@ -37,16 +83,66 @@ func runApply(ctx context.Context, rootDir string, args []string, noPrompt bool)
 		}
 		return err
 	}
-	_, err = runApplyInternalWithPlan(ctx, rootDir, nil, noPrompt, plan)
+	req, err := newApplyRequest(plan, targets, concurrency)
+	if err != nil {
+		return err
+	}
+	_, err = runApplyInternalRequest(ctx, rootDir, nil, noPrompt, req)
 	return err
 }
 
+// runApplySavedPlan applies a plan written by `gion plan --out` exactly as reviewed.
+// It refuses to run when gion.yaml or any worktree HEAD changed since the plan was made.
+func runApplySavedPlan(ctx context.Context, rootDir, planPath string, noPrompt bool, targets []manifestplan.Target, concurrency int) error {
+	saved, err := manifestplan.LoadPlanFile(planPath)
+	if err != nil {
+		return err
+	}
+	if err := saved.Verify(ctx, rootDir); err != nil {
+		return err
+	}
+	req, err := newApplyRequest(saved.Result(), targets, concurrency)
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	renderer.Section("Info")
+	renderer.Bullet(fmt.Sprintf("plan file: %s (created %s)", planPath, saved.CreatedAt.Local().Format("2006-01-02 15:04:05")))
+	renderer.Blank()
+
+	_, err = runApplyInternalRequest(ctx, rootDir, renderer, noPrompt, req)
+	return err
+}
+
+// applyRequest is the plan to apply plus the `gion apply` options that shape it.
+// When Targets is set, Plan is already narrowed and Skipped counts the changes that
+// were left out and stay pending for the next apply.
+// Journal is set when resuming an interrupted apply.
+type applyRequest struct {
+	Plan        manifestplan.Result
+	Targets     []manifestplan.Target
+	Skipped     int
+	Concurrency int
+	Journal     *apply.Journal
+}
+
+func newApplyRequest(plan manifestplan.Result, targets []manifestplan.Target, concurrency int) (applyRequest, error) {
+	filtered, skipped, err := manifestplan.FilterTargets(plan, targets)
+	if err != nil {
+		return applyRequest{}, err
+	}
+	return applyRequest{Plan: filtered, Targets: targets, Skipped: skipped, Concurrency: concurrency}, nil
+}
+
 func countWorkspaceChangeKinds(plan manifestplan.Result) (adds, updates, removes int) {
 	for _, change := range plan.Changes {
 		switch change.Kind {
 		case manifestplan.WorkspaceAdd:
 			adds++
-		case manifestplan.WorkspaceUpdate:
+		case manifestplan.WorkspaceUpdate, manifestplan.WorkspaceMetadata, manifestplan.WorkspaceRename:
 			updates++
 		case manifestplan.WorkspaceRemove:
 			removes++
@@ -57,49 +153,13 @@ func countWorkspaceChangeKinds(plan manifestplan.Result) (adds, updates, removes
 
 func planHasDestructiveChanges(plan manifestplan.Result) bool {
 	for _, change := range plan.Changes {
-		switch change.Kind {
-		case manifestplan.WorkspaceRemove:
+		if manifestplan.IsDestructiveWorkspaceChange(change) {
 			return true
-		case manifestplan.WorkspaceUpdate:
-			if hasDestructiveRepoChange(change.Repos) {
-				return true
-			}
 		}
 	}
 	return false
 }
 
-func hasDestructiveRepoChange(changes []manifestplan.RepoChange) bool {
-	for _, change := range changes {
-		switch change.Kind {
-		case manifestplan.RepoRemove:
-			return true
-		case manifestplan.RepoUpdate:
-			if isInPlaceBranchRename(change) {
-				continue
-			}
-			return true
-		}
-	}
-	return false
-}
-
-func isInPlaceBranchRename(change manifestplan.RepoChange) bool {
-	if change.Kind != manifestplan.RepoUpdate {
-		return false
-	}
-	if strings.TrimSpace(change.FromRepo) == "" || strings.TrimSpace(change.ToRepo) == "" {
-		return false
-	}
-	if strings.TrimSpace(change.FromBranch) == "" || strings.TrimSpace(change.ToBranch) == "" {
-		return false
-	}
-	if strings.TrimSpace(change.FromRepo) != strings.TrimSpace(change.ToRepo) {
-		return false
-	}
-	return strings.TrimSpace(change.FromBranch) != strings.TrimSpace(change.ToBranch)
-}
-
 type applyInternalResult struct {
 	HadChanges bool
 	Confirmed  bool
@@ -108,6 +168,11 @@ type applyInternalResult struct {
 }
 
 func runApplyInternalWithPlan(ctx context.Context, rootDir string, renderer *ui.Renderer, noPrompt bool, plan manifestplan.Result) (applyInternalResult, error) {
+	return runApplyInternalRequest(ctx, rootDir, renderer, noPrompt, applyRequest{Plan: plan})
+}
+
+func runApplyInternalRequest(ctx context.Context, rootDir string, renderer *ui.Renderer, noPrompt bool, req applyRequest) (applyInternalResult, error) {
+	plan := req.Plan
 
 	theme := ui.DefaultTheme()
 	useColor := isatty.IsTerminal(os.Stdout.Fd())
@@ -117,6 +182,14 @@ func runApplyInternalWithPlan(ctx context.Context, rootDir string, renderer *ui.
 	output.SetStepLogger(renderer)
 	defer output.SetStepLogger(nil)
 
+	if req.Journal == nil {
+		if _, pending, err := apply.LoadJournal(rootDir); err != nil {
+			return applyInternalResult{}, err
+		} else if pending {
+			return applyInternalResult{}, fmt.Errorf("a previous apply did not finish (%s); run gion apply --resume or gion apply --rollback", apply.JournalPath(rootDir))
+		}
+	}
+
 	var warningLines []string
 	for _, warn := range plan.Warnings {
 		warningLines = append(warningLines, warn.Error())
@@ -127,6 +200,12 @@ func runApplyInternalWithPlan(ctx context.Context, rootDir string, renderer *ui.
 	}
 
 	renderer.Section("Plan")
+	if len(req.Targets) > 0 {
+		renderer.Bullet(fmt.Sprintf("targeted: %s", formatApplyTargets(req.Targets)))
+		if req.Skipped > 0 {
+			renderer.BulletWarn(fmt.Sprintf("%d other change(s) outside --target left untouched (run gion plan to review)", req.Skipped))
+		}
+	}
 	if len(plan.Changes) == 0 {
 		renderer.Bullet("no changes")
 		return applyInternalResult{HadChanges: false, Confirmed: false, Applied: false}, nil
@@ -175,27 +254,82 @@ func runApplyInternalWithPlan(ctx context.Context, rootDir string, renderer *ui.
 		renderer.BulletWarn(fmt.Sprintf("prefetch failed (continuing): %v", err))
 		prefetchOK = false
 	}
+	journal := req.Journal
+	if journal == nil {
+		var err error
+		journal, err = apply.StartJournal(rootDir, plan, formatApplyTargetList(req.Targets), req.Skipped)
+		if err != nil {
+			return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: false}, err
+		}
+	}
+	// Failed hooks do not stop the apply; they are reported once the result is recorded.
+	var hookErr *apply.HookError
 	if err := apply.Apply(ctx, rootDir, plan, apply.Options{
 		AllowDirty:       destructive,
 		AllowStatusError: destructive,
 		PrefetchTimeout:  defaultPrefetchTimeout,
 		PrefetchOK:       prefetchOK,
+		Concurrency:      req.Concurrency,
+		Journal:          journal,
 		Step:             output.Step,
-	}); err != nil {
-		return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: false}, err
+	}); err != nil && !errors.As(err, &hookErr) {
+		return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: false}, failApplyJournal(journal, err)
+	}
+	var rebuildErr error
+	if len(req.Targets) > 0 {
+		// A full import would also adopt drift outside the targets; merge instead.
+		rebuildErr = rebuildManifestTargeted(ctx, rootDir, plan.Desired, req.Targets)
+	} else {
+		rebuildErr = rebuildManifest(ctx, rootDir)
+	}
+	if rebuildErr != nil {
+		return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: false}, failApplyJournal(journal, rebuildErr)
 	}
-	if err := rebuildManifest(ctx, rootDir); err != nil {
-		return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: false}, err
+	if err := journal.Remove(); err != nil {
+		return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: true}, err
 	}
 
 	renderer.Blank()
 	renderer.Section("Result")
 	adds, updates, removes := countWorkspaceChangeKinds(plan)
 	renderer.BulletSuccess(fmt.Sprintf("applied: add=%d update=%d remove=%d", adds, updates, removes))
-	renderer.Bullet(fmt.Sprintf("%s rewritten", manifest.FileName))
+	if len(req.Targets) > 0 {
+		renderer.Bullet(fmt.Sprintf("%s updated for targeted entries only", manifest.FileName))
+		if req.Skipped > 0 {
+			renderer.BulletWarn(fmt.Sprintf("%d change(s) still pending", req.Skipped))
+		}
+	} else {
+		renderer.Bullet(fmt.Sprintf("%s rewritten", manifest.FileName))
+	}
+	if hookErr != nil {
+		for _, failure := range hookErr.Failures {
+			renderer.BulletError(fmt.Sprintf("hook %s", failure))
+		}
+		return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: true}, hookErr
+	}
 	return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: true}, nil
 }
 
+// failApplyJournal keeps the journal for --resume/--rollback and points the user at them.
+func failApplyJournal(journal *apply.Journal, cause error) error {
+	if err := journal.Fail(cause); err != nil {
+		return errors.Join(cause, err)
+	}
+	return fmt.Errorf("%w\napply stopped partway; run gion apply --resume to continue or gion apply --rollback to undo", cause)
+}
+
+func formatApplyTargets(targets []manifestplan.Target) string {
+	return strings.Join(formatApplyTargetList(targets), ", ")
+}
+
+func formatApplyTargetList(targets []manifestplan.Target) []string {
+	values := make([]string, 0, len(targets))
+	for _, target := range targets {
+		values = append(values, target.String())
+	}
+	return values
+}
+
 func repoSpecsForApplyPlan(plan manifestplan.Result) []string {
 	unique := map[string]struct{}{}
 	for _, change := range plan.Changes {
@@ -107,3 +107,56 @@ func TestApply_BranchRenameInPlace_SucceedsWithDirtyWorktree(t *testing.T) {
 		t.Fatalf("rewritten branch: got %q, want %q", ws.Repos[0].Branch, "WS-2")
 	}
 }
+
+func TestApply_RepoMoveRenamesRecordedPR(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	prURL := "https://example.com/org/repo/pull/7"
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{
+		Mode:         workspace.MetadataModeRepo,
+		PullRequests: []workspace.PullRequest{{Alias: "repo", URL: prURL}},
+	}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	runGit(t, workspace.WorktreePath(rootDir, "WS-1", "repo"), "remote", "set-url", "origin", repoSpec)
+
+	desired := manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{
+		"WS-1": {Mode: workspace.MetadataModeRepo, Repos: []manifest.Repo{
+			{Alias: "app", RepoKey: "example.com/org/repo", Branch: "WS-1"},
+		}},
+	}}
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	if _, err := runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-1"))
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if meta.PullRequestURL("app") != prURL || meta.PullRequestURL("repo") != "" {
+		t.Fatalf("expected the PR to move to alias app, got %+v", meta.PullRequests)
+	}
+}
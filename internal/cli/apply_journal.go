@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/apply"
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+// runApplyResume re-runs the plan recorded by an interrupted apply, skipping the steps
+// the journal marks as done, then rewrites gion.yaml as a normal apply would.
+func runApplyResume(ctx context.Context, rootDir string, noPrompt bool, concurrency int) error {
+	journal, ok, err := apply.LoadJournal(rootDir)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no interrupted apply to resume")
+	}
+	targets, err := manifestplan.ParseTargets(journal.Targets)
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	renderer.Section("Info")
+	renderer.Bullet(fmt.Sprintf("resuming apply started %s", journal.StartedAt.Local().Format("2006-01-02 15:04:05")))
+	if journal.Error != "" {
+		renderer.BulletWarn(fmt.Sprintf("previous error: %s", journal.Error))
+	}
+	renderer.Bullet(fmt.Sprintf("%d completed step(s) will be skipped", len(journal.Steps)))
+	renderer.Blank()
+
+	_, err = runApplyInternalRequest(ctx, rootDir, renderer, noPrompt, applyRequest{
+		Plan:        journal.Plan(),
+		Targets:     targets,
+		Skipped:     journal.Skipped,
+		Concurrency: concurrency,
+		Journal:     journal,
+	})
+	return err
+}
+
+// runApplyRollback undoes what an interrupted apply created. Removals cannot be undone
+// and are only reported. gion.yaml is left as is, so the next plan shows the same changes.
+func runApplyRollback(ctx context.Context, rootDir string, noPrompt bool) error {
+	journal, ok, err := apply.LoadJournal(rootDir)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no interrupted apply to roll back")
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	output.SetStepLogger(renderer)
+	defer output.SetStepLogger(nil)
+
+	renderer.Section("Plan")
+	reversible := 0
+	for i := len(journal.Steps) - 1; i >= 0; i-- {
+		step := journal.Steps[i]
+		if step.Reversible() {
+			reversible++
+			renderer.Bullet(fmt.Sprintf("undo %s", step))
+			continue
+		}
+		renderer.BulletWarn(fmt.Sprintf("%s (cannot be undone)", step))
+	}
+	if reversible == 0 {
+		renderer.Bullet("nothing to undo")
+	}
+
+	if reversible > 0 && !noPrompt {
+		renderer.Blank()
+		confirm, err := ui.PromptConfirmInlinePlan("Roll back? (default: No)", theme, useColor)
+		if err != nil {
+			if errors.Is(err, ui.ErrPromptCanceled) {
+				return nil
+			}
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	var result apply.RollbackResult
+	if reversible > 0 {
+		renderer.Blank()
+		renderer.Section("Apply")
+		result, err = apply.Rollback(ctx, rootDir, journal, output.Step)
+		if err != nil {
+			return fmt.Errorf("%w\nrollback incomplete; fix the issue and run gion apply --rollback again", err)
+		}
+	} else {
+		result.Irreversible = journal.Steps
+	}
+	if err := journal.Remove(); err != nil {
+		return err
+	}
+
+	renderer.Blank()
+	renderer.Section("Result")
+	renderer.BulletSuccess(fmt.Sprintf("rolled back: %d step(s)", len(result.Reverted)))
+	for _, step := range result.Irreversible {
+		renderer.BulletWarn(fmt.Sprintf("not undone: %s", step))
+	}
+	renderer.Bullet(fmt.Sprintf("%s unchanged (run gion plan to review)", manifest.FileName))
+	return nil
+}
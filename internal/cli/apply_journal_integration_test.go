@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/apply"
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/paths"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+// setupPartiallyFailedApply applies a workspace whose second repo has no remote yet,
+// leaving a journal with the first worktree created.
+func setupPartiallyFailedApply(t *testing.T) (ctx context.Context, tmp, rootDir, remotePath string) {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx = context.Background()
+	tmp = t.TempDir()
+	rootDir = filepath.Join(tmp, "gion")
+	repoSpec, remotePath := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+
+	desired := manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{
+		"WS-1": {Mode: workspace.MetadataModeRepo, Repos: []manifest.Repo{
+			{Alias: "repo", RepoKey: "example.com/org/repo.git", Branch: "WS-1"},
+			{Alias: "other", RepoKey: "example.com/org/other.git", Branch: "WS-1"},
+		}},
+	}}
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	_, err = runApplyInternalRequest(ctx, rootDir, renderer, true, applyRequest{Plan: plan, Concurrency: 1})
+	if err == nil || !strings.Contains(err.Error(), "gion apply --resume") {
+		t.Fatalf("expected partial failure pointing at --resume, got %v", err)
+	}
+	journal, ok, err := apply.LoadJournal(rootDir)
+	if err != nil || !ok {
+		t.Fatalf("expected journal, ok=%v err=%v", ok, err)
+	}
+	if journal.Status != apply.JournalStatusFailed || len(journal.Steps) != 2 {
+		t.Fatalf("unexpected journal: status=%s steps=%+v", journal.Status, journal.Steps)
+	}
+	return ctx, tmp, rootDir, remotePath
+}
+
+func TestApply_RefusesToStartWhileJournalPending(t *testing.T) {
+	ctx, _, rootDir, _ := setupPartiallyFailedApply(t)
+
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	_, err = runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan)
+	if err == nil || !strings.Contains(err.Error(), "did not finish") {
+		t.Fatalf("expected pending journal error, got %v", err)
+	}
+}
+
+func TestApply_ResumeCompletesInterruptedApply(t *testing.T) {
+	ctx, _, rootDir, remotePath := setupPartiallyFailedApply(t)
+
+	otherRemote := filepath.Join(filepath.Dir(remotePath), "other.git")
+	runGit(t, "", "clone", "--bare", remotePath, otherRemote)
+	if _, err := repo.Get(ctx, rootDir, "https://example.com/org/other.git"); err != nil {
+		t.Fatalf("repo get other: %v", err)
+	}
+
+	if err := runApplyResume(ctx, rootDir, true, 1); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	for _, alias := range []string{"repo", "other"} {
+		if exists, _ := paths.DirExists(workspace.WorktreePath(rootDir, "WS-1", alias)); !exists {
+			t.Fatalf("worktree %s missing after resume", alias)
+		}
+	}
+	if _, ok, _ := apply.LoadJournal(rootDir); ok {
+		t.Fatalf("journal should be removed after a successful resume")
+	}
+	after, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan after resume: %v", err)
+	}
+	if len(after.Changes) != 0 {
+		t.Fatalf("expected no drift after resume, got %+v", after.Changes)
+	}
+}
+
+func TestApply_RollbackUndoesCreatedWorktreesAndBranches(t *testing.T) {
+	ctx, _, rootDir, _ := setupPartiallyFailedApply(t)
+
+	if err := runApplyRollback(ctx, rootDir, true); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if exists, _ := paths.DirExists(workspace.WorkspaceDir(rootDir, "WS-1")); exists {
+		t.Fatalf("workspace created by the failed apply should be removed")
+	}
+	store, err := repo.Open(ctx, rootDir, "https://example.com/org/repo.git", false)
+	if err != nil {
+		t.Fatalf("repo open: %v", err)
+	}
+	if out := runGit(t, store.StorePath, "branch", "--list", "WS-1"); out != "" {
+		t.Fatalf("branch created by the failed apply should be deleted, got %q", out)
+	}
+	if _, ok, _ := apply.LoadJournal(rootDir); ok {
+		t.Fatalf("journal should be removed after rollback")
+	}
+}
+
+func TestApply_ResumeAdoptsWorktreeMissingFromJournal(t *testing.T) {
+	ctx, _, rootDir, remotePath := setupPartiallyFailedApply(t)
+
+	// Simulate an interrupt between creating the worktree and journaling it.
+	journal, _, err := apply.LoadJournal(rootDir)
+	if err != nil {
+		t.Fatalf("load journal: %v", err)
+	}
+	var steps []apply.JournalStep
+	for _, step := range journal.Steps {
+		if step.Kind == apply.JournalAddWorktree && step.Alias == "repo" {
+			continue
+		}
+		steps = append(steps, step)
+	}
+	journal.Steps = steps
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal journal: %v", err)
+	}
+	if err := os.WriteFile(apply.JournalPath(rootDir), data, 0o600); err != nil {
+		t.Fatalf("write journal: %v", err)
+	}
+
+	otherRemote := filepath.Join(filepath.Dir(remotePath), "other.git")
+	runGit(t, "", "clone", "--bare", remotePath, otherRemote)
+	if _, err := repo.Get(ctx, rootDir, "https://example.com/org/other.git"); err != nil {
+		t.Fatalf("repo get other: %v", err)
+	}
+	if err := runApplyResume(ctx, rootDir, true, 1); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	after, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan after resume: %v", err)
+	}
+	if len(after.Changes) != 0 {
+		t.Fatalf("expected no drift after resume, got %+v", after.Changes)
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/paths"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func TestApply_CreatesWorktreesAcrossStoresInParallel(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, remotePath := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	runGit(t, "", "clone", "--bare", remotePath, filepath.Join(filepath.Dir(remotePath), "other.git"))
+	for _, spec := range []string{repoSpec, strings.Replace(repoSpec, "/repo.git", "/other.git", 1)} {
+		if _, err := repo.Get(ctx, rootDir, spec); err != nil {
+			t.Fatalf("repo get %s: %v", spec, err)
+		}
+	}
+
+	repos := []manifest.Repo{
+		{Alias: "repo", RepoKey: "example.com/org/repo.git"},
+		{Alias: "other", RepoKey: "example.com/org/other.git"},
+	}
+	desired := manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{}}
+	for _, id := range []string{"WS-1", "WS-2", "WS-3"} {
+		wsRepos := make([]manifest.Repo, 0, len(repos))
+		for _, repoEntry := range repos {
+			repoEntry.Branch = id
+			wsRepos = append(wsRepos, repoEntry)
+		}
+		desired.Workspaces[id] = manifest.Workspace{Mode: workspace.MetadataModeRepo, Repos: wsRepos}
+	}
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	got, err := runApplyInternalRequest(ctx, rootDir, renderer, true, applyRequest{Plan: plan, Concurrency: 2})
+	if err != nil {
+		t.Fatalf("apply: %v\n%s", err, buf.String())
+	}
+	if !got.Applied {
+		t.Fatalf("expected applied, got %+v", got)
+	}
+
+	for _, id := range []string{"WS-1", "WS-2", "WS-3"} {
+		for _, repoEntry := range repos {
+			path := workspace.WorktreePath(rootDir, id, repoEntry.Alias)
+			if exists, err := paths.DirExists(path); err != nil || !exists {
+				t.Fatalf("worktree missing: %s (err=%v)", path, err)
+			}
+			if branch := runGit(t, path, "rev-parse", "--abbrev-ref", "HEAD"); branch != id {
+				t.Fatalf("%s branch: got %q, want %q", path, branch, id)
+			}
+		}
+		if !strings.Contains(buf.String(), "worktree add "+id+"/repo") {
+			t.Fatalf("expected qualified step for %s in output:\n%s", id, buf.String())
+		}
+	}
+
+	after, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan after apply: %v", err)
+	}
+	if len(after.Changes) != 0 {
+		t.Fatalf("expected no drift after apply, got %+v", after.Changes)
+	}
+}
@@ -0,0 +1,444 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// bitbucketPageLen and bitbucketMaxPages bound list requests (open PRs/issues).
+	bitbucketPageLen  = 50
+	bitbucketMaxPages = 10
+	// bitbucketServerHostEnv names the Bitbucket Server/Data Center host that gets
+	// BITBUCKET_SERVER_TOKEN; the token is not sent to any other host.
+	bitbucketServerHostEnv = "GION_BITBUCKET_SERVER_HOST"
+)
+
+// bitbucketCloudProvider serves bitbucket.org (API 2.0). The hosts.yaml token (else
+// BITBUCKET_TOKEN) is sent as a bearer token (repository/workspace access token), or with
+// the hosts.yaml username (else BITBUCKET_USERNAME) as basic auth (app password).
+type bitbucketCloudProvider struct{}
+
+func (bitbucketCloudProvider) Name() string {
+	return "bitbucket"
+}
+
+type bitbucketCloudIssueItem struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type bitbucketCloudRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type bitbucketCloudPRItem struct {
+	ID          int               `json:"id"`
+	Title       string            `json:"title"`
+	Source      bitbucketCloudRef `json:"source"`
+	Destination bitbucketCloudRef `json:"destination"`
+	Description string            `json:"description"`
+}
+
+func (bitbucketCloudProvider) FetchIssues(ctx context.Context, host, owner, repoName string) ([]issueSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" {
+		return nil, fmt.Errorf("owner/repo is required")
+	}
+	query := url.Values{"q": {`state="new" OR state="open"`}, "sort": {"-updated_on"}}
+	var issues []issueSummary
+	err := bitbucketCloudGetPages(ctx, host, bitbucketCloudRepoPath(owner, repoName)+"/issues", query, func(values []bitbucketCloudIssueItem) {
+		for _, item := range values {
+			if item.ID == 0 {
+				continue
+			}
+			issues = append(issues, issueSummary{Number: item.ID, Title: strings.TrimSpace(item.Title)})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func (bitbucketCloudProvider) FetchIssue(ctx context.Context, host, owner, repoName string, number int) (issueSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" || number <= 0 {
+		return issueSummary{}, fmt.Errorf("owner/repo and issue number are required")
+	}
+	var item bitbucketCloudIssueItem
+	if err := bitbucketCloudGet(ctx, host, fmt.Sprintf("%s/issues/%d", bitbucketCloudRepoPath(owner, repoName), number), nil, &item); err != nil {
+		return issueSummary{}, err
+	}
+	if item.ID == 0 {
+		return issueSummary{}, fmt.Errorf("issue not found")
+	}
+	return issueSummary{Number: item.ID, Title: strings.TrimSpace(item.Title)}, nil
+}
+
+func (bitbucketCloudProvider) FetchPRs(ctx context.Context, host, owner, repoName string) ([]prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" {
+		return nil, fmt.Errorf("owner/repo is required")
+	}
+	query := url.Values{"state": {"OPEN"}, "sort": {"-updated_on"}}
+	var prs []prSummary
+	err := bitbucketCloudGetPages(ctx, host, bitbucketCloudRepoPath(owner, repoName)+"/pullrequests", query, func(values []bitbucketCloudPRItem) {
+		for _, item := range values {
+			if item.ID == 0 {
+				continue
+			}
+			prs = append(prs, normalizeBitbucketCloudPR(item))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+func (bitbucketCloudProvider) FetchPR(ctx context.Context, host, owner, repoName string, number int) (prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" || number <= 0 {
+		return prSummary{}, fmt.Errorf("owner/repo and PR number are required")
+	}
+	var item bitbucketCloudPRItem
+	if err := bitbucketCloudGet(ctx, host, fmt.Sprintf("%s/pullrequests/%d", bitbucketCloudRepoPath(owner, repoName), number), nil, &item); err != nil {
+		return prSummary{}, err
+	}
+	return normalizeBitbucketCloudPR(item), nil
+}
+
+func normalizeBitbucketCloudPR(item bitbucketCloudPRItem) prSummary {
+	return prSummary{
+		Number:   item.ID,
+		Title:    strings.TrimSpace(item.Title),
+		HeadRef:  strings.TrimSpace(item.Source.Branch.Name),
+		BaseRef:  strings.TrimSpace(item.Destination.Branch.Name),
+		HeadRepo: strings.TrimSpace(item.Source.Repository.FullName),
+		BaseRepo: strings.TrimSpace(item.Destination.Repository.FullName),
+		Body:     item.Description,
+	}
+}
+
+func bitbucketCloudRepoPath(owner, repoName string) string {
+	return "repositories/" + url.PathEscape(strings.TrimSpace(owner)) + "/" + url.PathEscape(strings.TrimSuffix(strings.TrimSpace(repoName), ".git"))
+}
+
+// bitbucketCloudPage is a paginated API 2.0 response; Next is the URL of the next page.
+type bitbucketCloudPage[T any] struct {
+	Values []T    `json:"values"`
+	Next   string `json:"next"`
+}
+
+func bitbucketCloudGetPages[T any](ctx context.Context, host, path string, query url.Values, collect func([]T)) error {
+	paged := url.Values{"pagelen": {strconv.Itoa(bitbucketPageLen)}}
+	for key, values := range query {
+		paged[key] = values
+	}
+	next := ""
+	for i := 0; i < bitbucketMaxPages; i++ {
+		var page bitbucketCloudPage[T]
+		var err error
+		if i == 0 {
+			err = bitbucketCloudGet(ctx, host, path, paged, &page)
+		} else {
+			err = bitbucketCloudGetURL(ctx, host, next, &page)
+		}
+		if err != nil {
+			return err
+		}
+		collect(page.Values)
+		if page.Next == "" {
+			return nil
+		}
+		next = page.Next
+	}
+	return nil
+}
+
+func bitbucketCloudGet(ctx context.Context, host, path string, query url.Values, out any) error {
+	return bitbucketCloudDo(ctx, host, http.MethodGet, path, query, nil, out)
+}
+
+func bitbucketCloudDo(ctx context.Context, host, method, path string, query url.Values, payload, out any) error {
+	entry, err := loadHostConfig(host)
+	if err != nil {
+		return err
+	}
+	endpoint := hostAPIURL(entry, "https://api.bitbucket.org/2.0") + "/" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return bitbucketCloudDoURL(ctx, host, method, endpoint, payload, out)
+}
+
+func bitbucketCloudGetURL(ctx context.Context, host, endpoint string, out any) error {
+	return bitbucketCloudDoURL(ctx, host, http.MethodGet, endpoint, nil, out)
+}
+
+func bitbucketCloudDoURL(ctx context.Context, host, method, endpoint string, payload, out any) error {
+	entry, err := loadHostConfig(host)
+	if err != nil {
+		return err
+	}
+	header := map[string]string{}
+	if token := hostToken(host, entry, envToken{key: "BITBUCKET_TOKEN", hosts: []string{"bitbucket.org"}}); token != "" {
+		// The username goes with the token: hosts.yaml for its token, else the environment.
+		username := strings.TrimSpace(entry.Username)
+		if strings.TrimSpace(entry.Token) == "" {
+			if envUsername := strings.TrimSpace(os.Getenv("BITBUCKET_USERNAME")); envUsername != "" {
+				username = envUsername
+			}
+		}
+		if username != "" {
+			header["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+token))
+		} else {
+			header["Authorization"] = "Bearer " + token
+		}
+	}
+	_, err = providerDoJSON(ctx, "bitbucket", method, endpoint, header, payload, out)
+	return err
+}
+
+// bitbucketServerProvider serves Bitbucket Server/Data Center (REST API 1.0). The owner is
+// the project key. BITBUCKET_SERVER_TOKEN (or the hosts.yaml token) is sent as a bearer
+// token (HTTP access token). Bitbucket Server has no issue tracker.
+type bitbucketServerProvider struct{}
+
+func (bitbucketServerProvider) Name() string {
+	return "bitbucket-server"
+}
+
+type bitbucketServerRef struct {
+	DisplayID  string `json:"displayId"`
+	Repository struct {
+		Slug    string `json:"slug"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+	} `json:"repository"`
+}
+
+type bitbucketServerPRItem struct {
+	ID          int                `json:"id"`
+	Title       string             `json:"title"`
+	FromRef     bitbucketServerRef `json:"fromRef"`
+	ToRef       bitbucketServerRef `json:"toRef"`
+	Description string             `json:"description"`
+}
+
+func (bitbucketServerProvider) FetchIssues(ctx context.Context, host, owner, repoName string) ([]issueSummary, error) {
+	return nil, fmt.Errorf("bitbucket server has no issue tracker: %s", host)
+}
+
+func (bitbucketServerProvider) FetchIssue(ctx context.Context, host, owner, repoName string, number int) (issueSummary, error) {
+	return issueSummary{}, fmt.Errorf("bitbucket server has no issue tracker: %s", host)
+}
+
+func (bitbucketServerProvider) FetchPRs(ctx context.Context, host, owner, repoName string) ([]prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" {
+		return nil, fmt.Errorf("owner/repo is required")
+	}
+	var prs []prSummary
+	start := 0
+	for i := 0; i < bitbucketMaxPages; i++ {
+		query := url.Values{
+			"state": {"OPEN"},
+			"order": {"NEWEST"},
+			"limit": {strconv.Itoa(bitbucketPageLen)},
+			"start": {strconv.Itoa(start)},
+		}
+		var page struct {
+			Values        []bitbucketServerPRItem `json:"values"`
+			IsLastPage    bool                    `json:"isLastPage"`
+			NextPageStart int                     `json:"nextPageStart"`
+		}
+		if err := bitbucketServerGet(ctx, host, bitbucketServerRepoPath(owner, repoName)+"/pull-requests", query, &page); err != nil {
+			return nil, err
+		}
+		for _, item := range page.Values {
+			if item.ID == 0 {
+				continue
+			}
+			prs = append(prs, normalizeBitbucketServerPR(item))
+		}
+		if page.IsLastPage {
+			break
+		}
+		start = page.NextPageStart
+	}
+	return prs, nil
+}
+
+func (bitbucketServerProvider) FetchPR(ctx context.Context, host, owner, repoName string, number int) (prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" || number <= 0 {
+		return prSummary{}, fmt.Errorf("owner/repo and PR number are required")
+	}
+	var item bitbucketServerPRItem
+	if err := bitbucketServerGet(ctx, host, fmt.Sprintf("%s/pull-requests/%d", bitbucketServerRepoPath(owner, repoName), number), nil, &item); err != nil {
+		return prSummary{}, err
+	}
+	return normalizeBitbucketServerPR(item), nil
+}
+
+func normalizeBitbucketServerPR(item bitbucketServerPRItem) prSummary {
+	fullName := func(ref bitbucketServerRef) string {
+		return strings.TrimSpace(ref.Repository.Project.Key) + "/" + strings.TrimSpace(ref.Repository.Slug)
+	}
+	return prSummary{
+		Number:   item.ID,
+		Title:    strings.TrimSpace(item.Title),
+		HeadRef:  strings.TrimSpace(item.FromRef.DisplayID),
+		BaseRef:  strings.TrimSpace(item.ToRef.DisplayID),
+		HeadRepo: fullName(item.FromRef),
+		BaseRepo: fullName(item.ToRef),
+		Body:     item.Description,
+	}
+}
+
+func bitbucketServerRepoPath(owner, repoName string) string {
+	return "projects/" + url.PathEscape(strings.TrimSpace(owner)) + "/repos/" + url.PathEscape(strings.TrimSuffix(strings.TrimSpace(repoName), ".git"))
+}
+
+func bitbucketServerGet(ctx context.Context, host, path string, query url.Values, out any) error {
+	return bitbucketServerDo(ctx, host, http.MethodGet, path, query, nil, out)
+}
+
+func bitbucketServerDo(ctx context.Context, host, method, path string, query url.Values, payload, out any) error {
+	entry, err := loadHostConfig(host)
+	if err != nil {
+		return err
+	}
+	endpoint := hostAPIURL(entry, "https://"+strings.TrimSpace(host)+"/rest/api/1.0") + "/" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	header := map[string]string{}
+	if token := hostToken(host, entry, envToken{key: "BITBUCKET_SERVER_TOKEN", hosts: []string{envHost(bitbucketServerHostEnv)}}); token != "" {
+		header["Authorization"] = "Bearer " + token
+	}
+	_, err = providerDoJSON(ctx, "bitbucket-server", method, endpoint, header, payload, out)
+	return err
+}
+
+// Bitbucket status reports the PR state only (OPEN, MERGED, DECLINED, SUPERSEDED).
+func bitbucketPRState(state string) string {
+	switch strings.ToUpper(state) {
+	case "MERGED":
+		return remoteStateMerged
+	case "OPEN":
+		return remoteStateOpen
+	default:
+		return remoteStateClosed
+	}
+}
+
+func (bitbucketCloudProvider) FetchPRStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State string `json:"state"`
+	}
+	if err := bitbucketCloudGet(ctx, host, fmt.Sprintf("%s/pullrequests/%d", bitbucketCloudRepoPath(owner, repoName), number), nil, &item); err != nil {
+		return remoteStatus{}, err
+	}
+	return remoteStatus{Kind: remoteKindPR, Number: number, State: bitbucketPRState(item.State)}, nil
+}
+
+func (bitbucketCloudProvider) FetchIssueStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State string `json:"state"`
+	}
+	if err := bitbucketCloudGet(ctx, host, fmt.Sprintf("%s/issues/%d", bitbucketCloudRepoPath(owner, repoName), number), nil, &item); err != nil {
+		return remoteStatus{}, err
+	}
+	state := remoteStateClosed
+	switch item.State {
+	case "new", "open":
+		state = remoteStateOpen
+	}
+	return remoteStatus{Kind: remoteKindIssue, Number: number, State: state}, nil
+}
+
+func (bitbucketServerProvider) FetchPRStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State string `json:"state"`
+	}
+	if err := bitbucketServerGet(ctx, host, fmt.Sprintf("%s/pull-requests/%d", bitbucketServerRepoPath(owner, repoName), number), nil, &item); err != nil {
+		return remoteStatus{}, err
+	}
+	return remoteStatus{Kind: remoteKindPR, Number: number, State: bitbucketPRState(item.State)}, nil
+}
+
+func (bitbucketServerProvider) FetchIssueStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	return remoteStatus{}, fmt.Errorf("bitbucket server has no issue tracker: %s", host)
+}
+
+func (bitbucketCloudProvider) CreatePR(ctx context.Context, host, owner, repoName string, req prCreateRequest) (createdPR, error) {
+	payload := map[string]any{
+		"title":       req.Title,
+		"description": req.Body,
+		"source":      map[string]any{"branch": map[string]string{"name": req.Head}},
+		"destination": map[string]any{"branch": map[string]string{"name": req.Base}},
+	}
+	var item struct {
+		ID    int `json:"id"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+	if err := bitbucketCloudDo(ctx, host, http.MethodPost, bitbucketCloudRepoPath(owner, repoName)+"/pullrequests", nil, payload, &item); err != nil {
+		return createdPR{}, err
+	}
+	return createdPR{Number: item.ID, URL: item.Links.HTML.Href}, nil
+}
+
+func (bitbucketCloudProvider) UpdatePRBody(ctx context.Context, host, owner, repoName string, number int, body string) error {
+	prPath := fmt.Sprintf("%s/pullrequests/%d", bitbucketCloudRepoPath(owner, repoName), number)
+	return bitbucketCloudDo(ctx, host, http.MethodPut, prPath, nil, map[string]string{"description": body}, nil)
+}
+
+func (bitbucketServerProvider) CreatePR(ctx context.Context, host, owner, repoName string, req prCreateRequest) (createdPR, error) {
+	payload := map[string]any{
+		"title":       req.Title,
+		"description": req.Body,
+		"fromRef":     map[string]string{"id": "refs/heads/" + req.Head},
+		"toRef":       map[string]string{"id": "refs/heads/" + req.Base},
+	}
+	var item struct {
+		ID    int `json:"id"`
+		Links struct {
+			Self []struct {
+				Href string `json:"href"`
+			} `json:"self"`
+		} `json:"links"`
+	}
+	if err := bitbucketServerDo(ctx, host, http.MethodPost, bitbucketServerRepoPath(owner, repoName)+"/pull-requests", nil, payload, &item); err != nil {
+		return createdPR{}, err
+	}
+	created := createdPR{Number: item.ID}
+	if len(item.Links.Self) > 0 {
+		created.URL = item.Links.Self[0].Href
+	}
+	return created, nil
+}
+
+// UpdatePRBody sends the PR's current version, which Bitbucket Server requires for updates.
+func (bitbucketServerProvider) UpdatePRBody(ctx context.Context, host, owner, repoName string, number int, body string) error {
+	prPath := fmt.Sprintf("%s/pull-requests/%d", bitbucketServerRepoPath(owner, repoName), number)
+	var item struct {
+		Version int `json:"version"`
+	}
+	if err := bitbucketServerGet(ctx, host, prPath, nil, &item); err != nil {
+		return err
+	}
+	return bitbucketServerDo(ctx, host, http.MethodPut, prPath, nil, map[string]any{"description": body, "version": item.Version}, nil)
+}
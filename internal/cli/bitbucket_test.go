@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBitbucketCloudProviderFetchPRs(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:app-password"))
+		if got := r.Header.Get("Authorization"); got != want {
+			t.Errorf("unexpected Authorization header: %q", got)
+		}
+		if r.URL.Path != "/repositories/ws/repo/pullrequests" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "Repository not found"}}`))
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`{"values": [{"id": 2, "title": "fork", "source": {"branch": {"name": "f"}, "repository": {"full_name": "someone/repo"}}, "destination": {"branch": {"name": "main"}, "repository": {"full_name": "ws/repo"}}}]}`))
+			return
+		}
+		fmt.Fprintf(w, `{"values": [{"id": 1, "title": " Fix ", "source": {"branch": {"name": "fix"}, "repository": {"full_name": "ws/repo"}}, "destination": {"branch": {"name": "main"}, "repository": {"full_name": "ws/repo"}}}], "next": "%s/repositories/ws/repo/pullrequests?page=2"}`, server.URL)
+	}))
+	defer server.Close()
+	t.Setenv("BITBUCKET_TOKEN", "")
+	t.Setenv("BITBUCKET_USERNAME", "")
+	setupHostsFile(t, fmt.Sprintf("bitbucket.org:\n  username: alice\n  token: app-password\n  api_url: %s\n", server.URL))
+
+	prs, err := bitbucketCloudProvider{}.FetchPRs(context.Background(), "bitbucket.org", "ws", "repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("expected 2 PRs, got %+v", prs)
+	}
+	if prs[0].Number != 1 || prs[0].Title != "Fix" || prs[0].HeadRef != "fix" || prs[0].BaseRef != "main" || prs[0].HeadRepo != "ws/repo" {
+		t.Fatalf("unexpected PR: %+v", prs[0])
+	}
+	if prs[1].HeadRepo == prs[1].BaseRepo {
+		t.Fatalf("expected fork PR: %+v", prs[1])
+	}
+
+	_, err = bitbucketCloudProvider{}.FetchIssue(context.Background(), "bitbucket.org", "ws", "repo", 1)
+	if err == nil || !strings.Contains(err.Error(), "Repository not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestBitbucketServerProviderFetchPRs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected Authorization header: %q", got)
+		}
+		if r.URL.Path != "/projects/PROJ/repos/repo/pull-requests" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": [{"message": "Repository PROJ/missing does not exist."}]}`))
+			return
+		}
+		ref := func(branch string) string {
+			return fmt.Sprintf(`{"displayId": %q, "repository": {"slug": "repo", "project": {"key": "PROJ"}}}`, branch)
+		}
+		if r.URL.Query().Get("start") == "25" {
+			fmt.Fprintf(w, `{"values": [{"id": 8, "title": "second", "fromRef": %s, "toRef": %s}], "isLastPage": true}`, ref("b"), ref("main"))
+			return
+		}
+		fmt.Fprintf(w, `{"values": [{"id": 7, "title": "first", "fromRef": %s, "toRef": %s}], "isLastPage": false, "nextPageStart": 25}`, ref("a"), ref("main"))
+	}))
+	defer server.Close()
+	t.Setenv("BITBUCKET_SERVER_TOKEN", "secret")
+	t.Setenv(bitbucketServerHostEnv, "scm.example.com")
+	setupHostsFile(t, fmt.Sprintf("scm.example.com:\n  api_url: %s\n", server.URL))
+
+	prs, err := bitbucketServerProvider{}.FetchPRs(context.Background(), "scm.example.com", "PROJ", "repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 2 || prs[0].Number != 7 || prs[1].Number != 8 || prs[0].HeadRef != "a" || prs[0].BaseRepo != "PROJ/repo" || prs[0].HeadRepo != prs[0].BaseRepo {
+		t.Fatalf("unexpected PRs: %+v", prs)
+	}
+
+	_, err = bitbucketServerProvider{}.FetchPR(context.Background(), "scm.example.com", "PROJ", "missing", 1)
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if _, err := (bitbucketServerProvider{}).FetchIssues(context.Background(), "scm.example.com", "PROJ", "repo"); err == nil {
+		t.Fatalf("expected issues to be unsupported")
+	}
+}
+
+func TestBitbucketServerProviderCreatePRAndUpdateBody(t *testing.T) {
+	var updated string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/PROJ/repos/repo/pull-requests":
+			var payload struct {
+				FromRef struct {
+					ID string `json:"id"`
+				} `json:"fromRef"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			if payload.FromRef.ID != "refs/heads/WS-1" {
+				t.Errorf("unexpected fromRef: %q", payload.FromRef.ID)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 3, "version": 0, "links": {"self": [{"href": "https://scm.example.com/projects/PROJ/repos/repo/pull-requests/3"}]}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/PROJ/repos/repo/pull-requests/3":
+			_, _ = w.Write([]byte(`{"id": 3, "version": 2}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/projects/PROJ/repos/repo/pull-requests/3":
+			body, _ := io.ReadAll(r.Body)
+			updated = string(body)
+			_, _ = w.Write([]byte(`{"id": 3, "version": 3}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": [{"message": "not found"}]}`))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("BITBUCKET_SERVER_TOKEN", "")
+	setupHostsFile(t, fmt.Sprintf("scm.example.com:\n  provider: bitbucket-server\n  token: secret\n  api_url: %s\n", server.URL))
+
+	pr, err := bitbucketServerProvider{}.CreatePR(context.Background(), "scm.example.com", "PROJ", "repo", prCreateRequest{Head: "WS-1", Base: "main", Title: "Fix"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Number != 3 || pr.URL != "https://scm.example.com/projects/PROJ/repos/repo/pull-requests/3" {
+		t.Fatalf("unexpected PR: %+v", pr)
+	}
+	if err := (bitbucketServerProvider{}).UpdatePRBody(context.Background(), "scm.example.com", "PROJ", "repo", 3, "linked"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated != `{"description":"linked","version":2}` {
+		t.Fatalf("expected the update to carry the current version, got %s", updated)
+	}
+}
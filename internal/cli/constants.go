@@ -4,3 +4,11 @@ import "time"
 
 const defaultRepoProtocol = "ssh"
 const defaultPrefetchTimeout = 60 * time.Second
+const defaultLockTimeout = 30 * time.Second
+
+// Exit codes of `gion plan --detailed-exitcode`; 1 stays reserved for errors.
+const (
+	planExitNoChanges   = 0
+	planExitChanges     = 2
+	planExitDestructive = 3
+)
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/execcmd"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runExec(ctx context.Context, rootDir string, args []string) error {
+	execFlags := flag.NewFlagSet("exec", flag.ContinueOnError)
+	var repos stringSliceFlag
+	var jobs int
+	var allWorkspaces bool
+	var helpFlag bool
+	execFlags.Var(&repos, "repo", "run only in this repo alias (repeatable)")
+	execFlags.IntVar(&jobs, "jobs", 1, "number of repos to run in parallel")
+	execFlags.BoolVar(&allWorkspaces, "all-workspaces", false, "run in every workspace")
+	execFlags.BoolVar(&helpFlag, "help", false, "show help")
+	execFlags.BoolVar(&helpFlag, "h", false, "show help")
+	execFlags.SetOutput(os.Stdout)
+	execFlags.Usage = func() {
+		printExecHelp(os.Stdout)
+	}
+	flagArgs, command, hasCommand := splitCommandArgs(args)
+	positional, err := parseInterspersed(execFlags, flagArgs)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printExecHelp(os.Stdout)
+		return nil
+	}
+	if !hasCommand || len(command) == 0 || len(positional) > 1 || allWorkspaces == (len(positional) == 1) {
+		return fmt.Errorf("usage: gion exec (<WORKSPACE_ID> | --all-workspaces) [--repo <alias>]... [--jobs <n>] -- <command> [args...]")
+	}
+	if jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1")
+	}
+
+	selection := execcmd.Selection{Aliases: uniqueNonEmptyStrings(repos)}
+	if !allWorkspaces {
+		selection.WorkspaceIDs = positional
+	}
+	targets, warnings, err := execcmd.Targets(ctx, rootDir, selection)
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	label := func(target execcmd.Target) string {
+		if allWorkspaces {
+			return target.String()
+		}
+		return target.Alias
+	}
+	width := 0
+	for _, target := range targets {
+		width = max(width, len(label(target)))
+	}
+
+	renderer.Section("Inputs")
+	renderer.Bullet(fmt.Sprintf("command: %s", formatCommandArgs(command)))
+	if allWorkspaces {
+		renderer.Bullet("workspaces: all")
+	} else {
+		renderer.Bullet(fmt.Sprintf("workspace: %s", positional[0]))
+	}
+	if len(selection.Aliases) > 0 {
+		renderer.Bullet(fmt.Sprintf("repos: %s", strings.Join(selection.Aliases, ", ")))
+	}
+	if len(warnings) > 0 {
+		renderer.Blank()
+		renderer.Section("Info")
+		for _, warn := range warnings {
+			renderer.BulletWarn(warn.Error())
+		}
+	}
+	if len(targets) == 0 {
+		renderer.Blank()
+		renderer.Section("Result")
+		renderer.Bullet("no repos")
+		return nil
+	}
+
+	renderer.Blank()
+	renderer.Section("Steps")
+	results := execcmd.Run(ctx, targets, command, execcmd.Options{
+		Jobs:    jobs,
+		RootDir: rootDir,
+		Output: func(target execcmd.Target, line string) {
+			fmt.Fprintf(os.Stdout, "%s%s %s\n", output.Indent, renderer.MutedText(fmt.Sprintf("%-*s |", width, label(target))), line)
+		},
+	})
+
+	renderer.Blank()
+	renderer.Section("Result")
+	failed := 0
+	for _, result := range results {
+		row := fmt.Sprintf("%-*s  exit %-3d %s", width, label(result.Target), result.ExitCode, result.Duration.Round(time.Millisecond))
+		if result.Err == nil {
+			renderer.BulletSuccess(row)
+			continue
+		}
+		failed++
+		if result.ExitCode < 0 {
+			row += fmt.Sprintf("  (%v)", result.Err)
+		}
+		renderer.BulletError(row)
+	}
+	if failed > 0 {
+		return fmt.Errorf("command failed in %d of %d repo(s)", failed, len(results))
+	}
+	return nil
+}
+
+// formatCommandArgs joins argv for display, quoting arguments that contain whitespace.
+func formatCommandArgs(argv []string) string {
+	parts := make([]string, 0, len(argv))
+	for _, arg := range argv {
+		if arg == "" || strings.ContainsAny(arg, " \t\n") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitCommandArgs splits args at the first "--" into gion arguments and the command.
+func splitCommandArgs(args []string) ([]string, []string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			return args[:i], args[i+1:], true
+		}
+	}
+	return args, nil, false
+}
+
+// parseInterspersed parses flags that may appear before or after positional arguments
+// and returns the positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
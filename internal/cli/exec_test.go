@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestExecArgs(t *testing.T) {
+	args := []string{"WS-1", "--jobs", "2", "--repo", "api", "--", "make", "test", "--jobs", "4"}
+	flagArgs, command, ok := splitCommandArgs(args)
+	if !ok || strings.Join(command, " ") != "make test --jobs 4" {
+		t.Fatalf("unexpected command: %v (ok=%v)", command, ok)
+	}
+
+	fs := flag.NewFlagSet("exec", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	jobs := fs.Int("jobs", 1, "")
+	var repos stringSliceFlag
+	fs.Var(&repos, "repo", "")
+	positional, err := parseInterspersed(fs, flagArgs)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if strings.Join(positional, ",") != "WS-1" || *jobs != 2 || strings.Join(repos, ",") != "api" {
+		t.Fatalf("unexpected parse: positional=%v jobs=%d repos=%v", positional, *jobs, repos)
+	}
+
+	if got := formatCommandArgs([]string{"sh", "-c", "echo hi", ""}); got != `sh -c "echo hi" ""` {
+		t.Fatalf("formatCommandArgs: got %q", got)
+	}
+}
@@ -0,0 +1,13 @@
+package cli
+
+import "fmt"
+
+// ExitCodeError asks the caller to exit with Code without reporting an error. Commands
+// return it when the exit status itself carries the result (e.g. gion plan --detailed-exitcode).
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// giteaPageLimit and giteaMaxPages bound list requests (open PRs/issues, newest first).
+	giteaPageLimit = 50
+	giteaMaxPages  = 10
+	// giteaHostEnv and forgejoHostEnv name a self-hosted instance. Its host is treated as
+	// Gitea/Forgejo and gets GITEA_TOKEN/FORGEJO_TOKEN, which otherwise go only to gitea.com
+	// and codeberg.org.
+	giteaHostEnv   = "GION_GITEA_HOST"
+	forgejoHostEnv = "GION_FORGEJO_HOST"
+)
+
+// giteaProvider serves Gitea and Forgejo (same REST API, /api/v1). Tokens come from the
+// host's hosts.yaml token, else GITEA_TOKEN (FORGEJO_TOKEN first for Forgejo) when it
+// belongs to the host.
+type giteaProvider struct {
+	name string
+}
+
+func (p giteaProvider) Name() string {
+	return p.name
+}
+
+func (p giteaProvider) FetchIssues(ctx context.Context, host, owner, repoName string) ([]issueSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" {
+		return nil, fmt.Errorf("owner/repo is required")
+	}
+	query := url.Values{"state": {"open"}, "type": {"issues"}, "sort": {"recentupdate"}}
+	var issues []issueSummary
+	err := p.getPages(ctx, host, giteaRepoPath(owner, repoName)+"/issues", query, func(body []byte) error {
+		page, err := parseGitHubIssues(body)
+		issues = append(issues, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func (p giteaProvider) FetchIssue(ctx context.Context, host, owner, repoName string, number int) (issueSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" || number <= 0 {
+		return issueSummary{}, fmt.Errorf("owner/repo and issue number are required")
+	}
+	var item githubIssueItem
+	if err := p.get(ctx, host, fmt.Sprintf("%s/issues/%d", giteaRepoPath(owner, repoName), number), nil, &item); err != nil {
+		return issueSummary{}, err
+	}
+	if item.Number == 0 {
+		return issueSummary{}, fmt.Errorf("issue not found")
+	}
+	return issueSummary{Number: item.Number, Title: strings.TrimSpace(item.Title)}, nil
+}
+
+func (p giteaProvider) FetchPRs(ctx context.Context, host, owner, repoName string) ([]prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" {
+		return nil, fmt.Errorf("owner/repo is required")
+	}
+	query := url.Values{"state": {"open"}, "sort": {"recentupdate"}}
+	var prs []prSummary
+	err := p.getPages(ctx, host, giteaRepoPath(owner, repoName)+"/pulls", query, func(body []byte) error {
+		page, err := parseGitHubPRs(body)
+		prs = append(prs, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+func (p giteaProvider) FetchPR(ctx context.Context, host, owner, repoName string, number int) (prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" || number <= 0 {
+		return prSummary{}, fmt.Errorf("owner/repo and PR number are required")
+	}
+	var item githubPRItem
+	if err := p.get(ctx, host, fmt.Sprintf("%s/pulls/%d", giteaRepoPath(owner, repoName), number), nil, &item); err != nil {
+		return prSummary{}, err
+	}
+	return normalizeGitHubPR(item), nil
+}
+
+func giteaRepoPath(owner, repoName string) string {
+	return "repos/" + url.PathEscape(strings.TrimSpace(owner)) + "/" + url.PathEscape(strings.TrimSuffix(strings.TrimSpace(repoName), ".git"))
+}
+
+// getPages requests page 1, 2, ... until a short page, up to giteaMaxPages pages.
+func (p giteaProvider) getPages(ctx context.Context, host, path string, query url.Values, page func(body []byte) error) error {
+	for i := 1; i <= giteaMaxPages; i++ {
+		paged := url.Values{}
+		for key, values := range query {
+			paged[key] = values
+		}
+		paged.Set("limit", strconv.Itoa(giteaPageLimit))
+		paged.Set("page", strconv.Itoa(i))
+		var body json.RawMessage
+		if err := p.get(ctx, host, path, paged, &body); err != nil {
+			return err
+		}
+		if err := page(body); err != nil {
+			return err
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil || len(items) < giteaPageLimit {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p giteaProvider) get(ctx context.Context, host, path string, query url.Values, out any) error {
+	return p.do(ctx, host, http.MethodGet, path, query, nil, out)
+}
+
+func (p giteaProvider) do(ctx context.Context, host, method, path string, query url.Values, payload, out any) error {
+	entry, err := loadHostConfig(host)
+	if err != nil {
+		return err
+	}
+	endpoint := hostAPIURL(entry, "https://"+strings.TrimSpace(host)+"/api/v1") + "/" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	tokens := []envToken{{key: "GITEA_TOKEN", hosts: []string{"gitea.com", envHost(giteaHostEnv)}}}
+	if p.name == "forgejo" {
+		tokens = append([]envToken{{key: "FORGEJO_TOKEN", hosts: []string{"codeberg.org", envHost(forgejoHostEnv)}}}, tokens...)
+	}
+	header := map[string]string{}
+	if token := hostToken(host, entry, tokens...); token != "" {
+		header["Authorization"] = "token " + token
+	}
+	_, err = providerDoJSON(ctx, p.name, method, endpoint, header, payload, out)
+	return err
+}
+
+func (p giteaProvider) FetchPRStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State  string `json:"state"`
+		Merged bool   `json:"merged"`
+		Draft  bool   `json:"draft"`
+		Head   struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	repoPath := giteaRepoPath(owner, repoName)
+	if err := p.get(ctx, host, fmt.Sprintf("%s/pulls/%d", repoPath, number), nil, &item); err != nil {
+		return remoteStatus{}, err
+	}
+	status := remoteStatus{Kind: remoteKindPR, Number: number, State: remoteStateOpen}
+	switch {
+	case item.Merged:
+		return remoteStatus{Kind: remoteKindPR, Number: number, State: remoteStateMerged}, nil
+	case item.State == "closed":
+		return remoteStatus{Kind: remoteKindPR, Number: number, State: remoteStateClosed}, nil
+	case item.Draft:
+		status.State = remoteStateDraft
+	}
+	if item.Head.SHA != "" {
+		var combined struct {
+			State      string `json:"state"`
+			TotalCount int    `json:"total_count"`
+		}
+		if err := p.get(ctx, host, fmt.Sprintf("%s/commits/%s/status", repoPath, item.Head.SHA), nil, &combined); err != nil {
+			return remoteStatus{}, err
+		}
+		if combined.TotalCount > 0 {
+			status.Checks = githubStatusResult(combined.State)
+		}
+	}
+	return status, nil
+}
+
+func (p giteaProvider) FetchIssueStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State string `json:"state"`
+	}
+	if err := p.get(ctx, host, fmt.Sprintf("%s/issues/%d", giteaRepoPath(owner, repoName), number), nil, &item); err != nil {
+		return remoteStatus{}, err
+	}
+	return remoteStatus{Kind: remoteKindIssue, Number: number, State: githubIssueState(item.State)}, nil
+}
+
+func (p giteaProvider) CreatePR(ctx context.Context, host, owner, repoName string, req prCreateRequest) (createdPR, error) {
+	payload := map[string]string{"head": req.Head, "base": req.Base, "title": req.Title, "body": req.Body}
+	var item struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := p.do(ctx, host, http.MethodPost, giteaRepoPath(owner, repoName)+"/pulls", nil, payload, &item); err != nil {
+		return createdPR{}, err
+	}
+	return createdPR{Number: item.Number, URL: item.HTMLURL}, nil
+}
+
+func (p giteaProvider) UpdatePRBody(ctx context.Context, host, owner, repoName string, number int, body string) error {
+	return p.do(ctx, host, http.MethodPatch, fmt.Sprintf("%s/pulls/%d", giteaRepoPath(owner, repoName), number), nil, map[string]string{"body": body}, nil)
+}
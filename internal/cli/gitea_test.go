@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGiteaProviderFetchIssuesPaginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token secret" {
+			t.Errorf("unexpected Authorization header: %q", got)
+		}
+		if r.URL.Path != "/repos/owner/repo/issues" || r.URL.Query().Get("type") != "issues" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"repo not found"}`))
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`[{"number": 99, "title": "last", "pull_request": null}]`))
+			return
+		}
+		var items []string
+		for i := 1; i <= giteaPageLimit; i++ {
+			items = append(items, fmt.Sprintf(`{"number": %d, "title": "issue %d", "pull_request": null}`, i, i))
+		}
+		items[0] = `{"number": 1, "title": "a pull request", "pull_request": {"merged": false}}`
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+	defer server.Close()
+	t.Setenv("GITEA_TOKEN", "")
+	setupHostsFile(t, fmt.Sprintf("git.example.com:\n  provider: gitea\n  token: secret\n  api_url: %s\n", server.URL))
+
+	issues, err := giteaProvider{name: "gitea"}.FetchIssues(context.Background(), "git.example.com", "owner", "repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != giteaPageLimit || issues[0].Number != 2 || issues[len(issues)-1].Number != 99 {
+		t.Fatalf("unexpected issues: %d %+v", len(issues), issues[0])
+	}
+
+	_, err = giteaProvider{name: "gitea"}.FetchPR(context.Background(), "git.example.com", "owner", "missing", 1)
+	if err == nil || !strings.Contains(err.Error(), "repo not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestGiteaProviderFetchPR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token env-token" {
+			t.Errorf("unexpected Authorization header: %q", got)
+		}
+		_, _ = w.Write([]byte(`{"number": 4, "title": "Fix", "head": {"ref": "fix", "repo": {"full_name": "owner/repo"}}, "base": {"ref": "main", "repo": {"full_name": "owner/repo"}}}`))
+	}))
+	defer server.Close()
+	t.Setenv("FORGEJO_TOKEN", "env-token")
+	t.Setenv(forgejoHostEnv, "code.example.com")
+	setupHostsFile(t, fmt.Sprintf("code.example.com:\n  api_url: %s\n", server.URL))
+
+	pr, err := giteaProvider{name: "forgejo"}.FetchPR(context.Background(), "code.example.com", "owner", "repo", 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Number != 4 || pr.HeadRef != "fix" || pr.BaseRef != "main" || pr.HeadRepo != "owner/repo" {
+		t.Fatalf("unexpected PR: %+v", pr)
+	}
+}
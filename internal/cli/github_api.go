@@ -0,0 +1,409 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// githubPerPage and githubMaxPages bound list requests (open PRs/issues, newest first).
+	githubPerPage  = 100
+	githubMaxPages = 10
+	// githubMaxRetryWait is the longest rate-limit wait gion sits through before giving up.
+	githubMaxRetryWait = 60 * time.Second
+	githubMaxRetries   = 2
+)
+
+var (
+	// githubLookPath and githubSleep are replaced in tests.
+	githubLookPath = exec.LookPath
+	githubSleep    = func(ctx context.Context, d time.Duration) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+)
+
+// githubAPI talks to the GitHub REST API directly (no gh CLI).
+type githubAPI struct {
+	baseURL string
+	token   string
+}
+
+// githubAPIForHost decides how GitHub metadata is fetched for host. A token (environment
+// or hosts.yaml) selects the REST API; without one the gh CLI is used when installed (it has
+// its own login), and the REST API is used unauthenticated otherwise.
+func githubAPIForHost(host string) (githubAPI, bool, error) {
+	entry, err := loadHostConfig(host)
+	if err != nil {
+		return githubAPI{}, false, err
+	}
+	api := githubAPI{
+		baseURL: hostAPIURL(entry, githubDefaultAPIURL(host)),
+		token:   githubToken(host, entry),
+	}
+	if api.token == "" {
+		if _, err := githubLookPath("gh"); err == nil {
+			return githubAPI{}, false, nil
+		}
+	}
+	return api, true, nil
+}
+
+// githubToken returns the hosts.yaml token, else the environment (GH_TOKEN/GITHUB_TOKEN
+// for github.com, GH_ENTERPRISE_TOKEN/GITHUB_ENTERPRISE_TOKEN for other hosts, like gh).
+// The github.com variables are deliberately not used for other hosts, so a github.com
+// token is never sent to an Enterprise Server.
+func githubToken(host string, entry hostConfig) string {
+	if isGitHubDotCom(host) {
+		return hostToken(host, entry, envToken{key: "GH_TOKEN", hosts: []string{host}}, envToken{key: "GITHUB_TOKEN", hosts: []string{host}})
+	}
+	return hostToken(host, entry, envToken{key: "GH_ENTERPRISE_TOKEN", hosts: []string{host}}, envToken{key: "GITHUB_ENTERPRISE_TOKEN", hosts: []string{host}})
+}
+
+func githubDefaultAPIURL(host string) string {
+	if isGitHubDotCom(host) {
+		return "https://api.github.com"
+	}
+	return "https://" + strings.TrimSpace(host) + "/api/v3"
+}
+
+func isGitHubDotCom(host string) bool {
+	host = strings.TrimSpace(host)
+	return host == "" || strings.EqualFold(host, "github.com")
+}
+
+func (api githubAPI) FetchIssues(ctx context.Context, owner, repoName string) ([]issueSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" {
+		return nil, fmt.Errorf("owner/repo is required")
+	}
+	var issues []issueSummary
+	err := api.getPages(ctx, fmt.Sprintf("repos/%s/%s/issues", owner, repoName), githubListQuery(), func(body []byte) error {
+		page, err := parseGitHubIssues(body)
+		issues = append(issues, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func (api githubAPI) FetchIssue(ctx context.Context, owner, repoName string, number int) (issueSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" || number <= 0 {
+		return issueSummary{}, fmt.Errorf("owner/repo and issue number are required")
+	}
+	var item githubIssueItem
+	if _, err := api.get(ctx, api.endpoint(fmt.Sprintf("repos/%s/%s/issues/%d", owner, repoName, number), nil), &item); err != nil {
+		return issueSummary{}, err
+	}
+	if item.Number == 0 {
+		return issueSummary{}, fmt.Errorf("issue not found")
+	}
+	return issueSummary{Number: item.Number, Title: strings.TrimSpace(item.Title)}, nil
+}
+
+func (api githubAPI) FetchPRs(ctx context.Context, owner, repoName string) ([]prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" {
+		return nil, fmt.Errorf("owner/repo is required")
+	}
+	var prs []prSummary
+	err := api.getPages(ctx, fmt.Sprintf("repos/%s/%s/pulls", owner, repoName), githubListQuery(), func(body []byte) error {
+		page, err := parseGitHubPRs(body)
+		prs = append(prs, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+func (api githubAPI) FetchPR(ctx context.Context, owner, repoName string, number int) (prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" || number <= 0 {
+		return prSummary{}, fmt.Errorf("owner/repo and PR number are required")
+	}
+	var item githubPRItem
+	if _, err := api.get(ctx, api.endpoint(fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repoName, number), nil), &item); err != nil {
+		return prSummary{}, err
+	}
+	return normalizeGitHubPR(item), nil
+}
+
+func githubListQuery() url.Values {
+	return url.Values{
+		"state":     {"open"},
+		"sort":      {"updated"},
+		"direction": {"desc"},
+		"per_page":  {strconv.Itoa(githubPerPage)},
+	}
+}
+
+func (api githubAPI) endpoint(path string, query url.Values) string {
+	endpoint := api.baseURL + "/" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint
+}
+
+// getPages follows the Link rel="next" header, up to githubMaxPages pages. A next page on
+// another scheme or host than baseURL is refused: the token would be sent along.
+func (api githubAPI) getPages(ctx context.Context, path string, query url.Values, page func(body []byte) error) error {
+	next := api.endpoint(path, query)
+	for i := 0; i < githubMaxPages && next != ""; i++ {
+		var raw json.RawMessage
+		header, err := api.get(ctx, next, &raw)
+		if err != nil {
+			return err
+		}
+		if err := page(raw); err != nil {
+			return err
+		}
+		next = linkNextPage(header.Get("Link"))
+		if next != "" && !sameOrigin(next, api.baseURL) {
+			return fmt.Errorf("github api: next page is not on %s: %s", api.baseURL, next)
+		}
+	}
+	return nil
+}
+
+// sameOrigin reports whether two URLs share scheme and host (including the port).
+func sameOrigin(rawURL, baseURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host)
+}
+
+func (api githubAPI) header() map[string]string {
+	header := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	if api.token != "" {
+		header["Authorization"] = "Bearer " + api.token
+	}
+	return header
+}
+
+// send issues a write request. Writes are not retried on rate limits: a POST that timed
+// out may still have created the PR.
+func (api githubAPI) send(ctx context.Context, method, endpoint string, payload, out any) error {
+	_, err := providerDoJSON(ctx, "github", method, endpoint, api.header(), payload, out)
+	return err
+}
+
+func (api githubAPI) get(ctx context.Context, endpoint string, out any) (http.Header, error) {
+	header := api.header()
+	for attempt := 0; ; attempt++ {
+		resp, body, err := providerGet(ctx, "github", endpoint, header)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			if err := json.Unmarshal(body, out); err != nil {
+				return nil, fmt.Errorf("parse github api response: %w", err)
+			}
+			return resp.Header, nil
+		}
+		wait, limited := githubRateLimitWait(resp, time.Now())
+		if !limited {
+			return nil, fmt.Errorf("github api failed: %s: %s", resp.Status, providerErrorMessage(body))
+		}
+		if attempt >= githubMaxRetries || wait > githubMaxRetryWait {
+			return nil, githubRateLimitError(resp, api.token != "")
+		}
+		if err := githubSleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// githubRateLimitWait reports whether resp is a rate-limit response and how long to wait
+// before retrying: Retry-After for secondary limits, X-RateLimit-Reset for the primary one.
+func githubRateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if strings.TrimSpace(resp.Header.Get("X-RateLimit-Remaining")) != "0" {
+		return 0, resp.StatusCode == http.StatusTooManyRequests
+	}
+	reset, err := strconv.ParseInt(strings.TrimSpace(resp.Header.Get("X-RateLimit-Reset")), 10, 64)
+	if err != nil {
+		return githubMaxRetryWait + time.Second, true
+	}
+	wait := time.Unix(reset, 0).Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+func githubRateLimitError(resp *http.Response, authenticated bool) error {
+	msg := "github api rate limit exceeded"
+	if reset, err := strconv.ParseInt(strings.TrimSpace(resp.Header.Get("X-RateLimit-Reset")), 10, 64); err == nil {
+		msg += fmt.Sprintf(" (resets at %s)", time.Unix(reset, 0).Local().Format("15:04:05"))
+	}
+	if !authenticated {
+		msg += "; set GITHUB_TOKEN for a higher limit"
+	}
+	return errors.New(msg)
+}
+
+func (api githubAPI) FetchPRStatus(ctx context.Context, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State  string `json:"state"`
+		Merged bool   `json:"merged"`
+		Draft  bool   `json:"draft"`
+		Head   struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	repoPath := fmt.Sprintf("repos/%s/%s", owner, repoName)
+	if _, err := api.get(ctx, api.endpoint(fmt.Sprintf("%s/pulls/%d", repoPath, number), nil), &item); err != nil {
+		return remoteStatus{}, err
+	}
+	status := remoteStatus{Kind: remoteKindPR, Number: number, State: remoteStateOpen}
+	switch {
+	case item.Merged:
+		status.State = remoteStateMerged
+	case item.State == "closed":
+		status.State = remoteStateClosed
+	case item.Draft:
+		status.State = remoteStateDraft
+	}
+	if status.State == remoteStateMerged || status.State == remoteStateClosed {
+		return status, nil
+	}
+
+	var reviews []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State string `json:"state"`
+	}
+	if _, err := api.get(ctx, api.endpoint(fmt.Sprintf("%s/pulls/%d/reviews", repoPath, number), url.Values{"per_page": {"100"}}), &reviews); err != nil {
+		return remoteStatus{}, err
+	}
+	latest := map[string]string{}
+	for _, review := range reviews {
+		switch review.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[review.User.Login] = review.State
+		}
+	}
+	status.Review = summarizeReviews(latest)
+
+	if item.Head.SHA == "" {
+		return status, nil
+	}
+	var runs struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if _, err := api.get(ctx, api.endpoint(fmt.Sprintf("%s/commits/%s/check-runs", repoPath, item.Head.SHA), url.Values{"per_page": {"100"}}), &runs); err != nil {
+		return remoteStatus{}, err
+	}
+	var combined struct {
+		State      string `json:"state"`
+		TotalCount int    `json:"total_count"`
+	}
+	if _, err := api.get(ctx, api.endpoint(fmt.Sprintf("%s/commits/%s/status", repoPath, item.Head.SHA), nil), &combined); err != nil {
+		return remoteStatus{}, err
+	}
+	var results []string
+	for _, run := range runs.CheckRuns {
+		results = append(results, githubCheckResult(run.Status, run.Conclusion))
+	}
+	if combined.TotalCount > 0 {
+		results = append(results, githubStatusResult(combined.State))
+	}
+	status.Checks = summarizeChecks(results)
+	return status, nil
+}
+
+func (api githubAPI) FetchIssueStatus(ctx context.Context, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State string `json:"state"`
+	}
+	if _, err := api.get(ctx, api.endpoint(fmt.Sprintf("repos/%s/%s/issues/%d", owner, repoName, number), nil), &item); err != nil {
+		return remoteStatus{}, err
+	}
+	return remoteStatus{Kind: remoteKindIssue, Number: number, State: githubIssueState(item.State)}, nil
+}
+
+// githubCheckResult maps a check run (status, conclusion) onto a summarizeChecks input.
+func githubCheckResult(status, conclusion string) string {
+	if !strings.EqualFold(status, "completed") {
+		return remoteChecksPending
+	}
+	switch strings.ToLower(conclusion) {
+	case "success", "neutral":
+		return remoteChecksPassing
+	case "skipped", "stale":
+		return ""
+	default:
+		return remoteChecksFailing
+	}
+}
+
+// githubStatusResult maps a commit status state onto a summarizeChecks input.
+func githubStatusResult(state string) string {
+	switch strings.ToLower(state) {
+	case "success":
+		return remoteChecksPassing
+	case "pending", "expected":
+		return remoteChecksPending
+	case "failure", "error":
+		return remoteChecksFailing
+	default:
+		return ""
+	}
+}
+
+func githubIssueState(state string) string {
+	if strings.EqualFold(state, "closed") {
+		return remoteStateClosed
+	}
+	return remoteStateOpen
+}
+
+func (api githubAPI) CreatePR(ctx context.Context, owner, repoName string, req prCreateRequest) (createdPR, error) {
+	payload := map[string]string{"title": req.Title, "body": req.Body, "head": req.Head, "base": req.Base}
+	var item struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := api.send(ctx, http.MethodPost, api.endpoint(fmt.Sprintf("repos/%s/%s/pulls", owner, repoName), nil), payload, &item); err != nil {
+		return createdPR{}, err
+	}
+	return createdPR{Number: item.Number, URL: item.HTMLURL}, nil
+}
+
+func (api githubAPI) UpdatePRBody(ctx context.Context, owner, repoName string, number int, body string) error {
+	return api.send(ctx, http.MethodPatch, api.endpoint(fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repoName, number), nil), map[string]string{"body": body}, nil)
+}
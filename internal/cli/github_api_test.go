@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tasuku43/gion/internal/app/prcreate"
+)
+
+func setupGitHubHostsFile(t *testing.T, host, apiURL, token string) {
+	t.Helper()
+	for _, key := range []string{"GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"} {
+		t.Setenv(key, "")
+	}
+	setupHostsFile(t, fmt.Sprintf("%s:\n  token: %s\n  api_url: %s\n", host, token, apiURL))
+}
+
+func TestGitHubAPIFetchPRsPaginates(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected Authorization header: %q", got)
+		}
+		if r.URL.Path != "/repos/owner/repo/pulls" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`[{"number": 2, "title": "second", "head": {"ref": "b", "repo": {"full_name": "owner/repo"}}, "base": {"ref": "main", "repo": {"full_name": "owner/repo"}}}]`))
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/pulls?page=2>; rel="next", <%s/repos/owner/repo/pulls?page=2>; rel="last"`, server.URL, server.URL))
+		_, _ = w.Write([]byte(`[{"number": 1, "title": "first", "head": {"ref": "a", "repo": {"full_name": "owner/repo"}}, "base": {"ref": "main", "repo": {"full_name": "owner/repo"}}}]`))
+	}))
+	defer server.Close()
+	setupGitHubHostsFile(t, "ghe.example.com", server.URL, "secret")
+
+	prs, err := githubProvider{}.FetchPRs(context.Background(), "ghe.example.com", "owner", "repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 2 || prs[0].Number != 1 || prs[1].Number != 2 || prs[1].HeadRef != "b" {
+		t.Fatalf("unexpected PRs: %+v", prs)
+	}
+
+	_, err = githubProvider{}.FetchPR(context.Background(), "ghe.example.com", "owner", "missing", 1)
+	if err == nil || !strings.Contains(err.Error(), "Not Found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestGitHubAPIRefusesNextPageOnAnotherHost(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Link", `<https://attacker.example.com/repos/owner/repo/pulls?page=2>; rel="next"`)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	setupGitHubHostsFile(t, "ghe.example.com", server.URL, "secret")
+
+	_, err := githubProvider{}.FetchPRs(context.Background(), "ghe.example.com", "owner", "repo")
+	if err == nil || !strings.Contains(err.Error(), "attacker.example.com") {
+		t.Fatalf("expected the next page to be refused, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one request, got %d", calls)
+	}
+}
+
+func TestGitHubAPIRetriesSecondaryRateLimit(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"number": 5, "title": "Broken login"}`))
+	}))
+	defer server.Close()
+	setupGitHubHostsFile(t, "ghe.example.com", server.URL, "secret")
+
+	var waited []time.Duration
+	prevSleep := githubSleep
+	githubSleep = func(_ context.Context, d time.Duration) error {
+		waited = append(waited, d)
+		return nil
+	}
+	t.Cleanup(func() { githubSleep = prevSleep })
+
+	issue, err := githubProvider{}.FetchIssue(context.Background(), "ghe.example.com", "owner", "repo", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issue.Number != 5 || calls != 2 {
+		t.Fatalf("unexpected result: issue=%+v calls=%d", issue, calls)
+	}
+	if len(waited) != 1 || waited[0] != 3*time.Second {
+		t.Fatalf("unexpected waits: %v", waited)
+	}
+}
+
+func TestGitHubAPIPrimaryRateLimitError(t *testing.T) {
+	reset := time.Now().Add(30 * time.Minute).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	}))
+	defer server.Close()
+	setupGitHubHostsFile(t, "ghe.example.com", server.URL, "")
+	prevLookPath := githubLookPath
+	githubLookPath = func(string) (string, error) { return "", errors.New("not found") }
+	t.Cleanup(func() { githubLookPath = prevLookPath })
+
+	_, err := githubProvider{}.FetchIssues(context.Background(), "ghe.example.com", "owner", "repo")
+	if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") || !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestGitHubAPIForHost(t *testing.T) {
+	setupGitHubHostsFile(t, "ghe.example.com", "", "")
+	prevLookPath := githubLookPath
+	t.Cleanup(func() { githubLookPath = prevLookPath })
+
+	githubLookPath = func(string) (string, error) { return "/usr/bin/gh", nil }
+	if _, ok, err := githubAPIForHost("github.com"); err != nil || ok {
+		t.Fatalf("expected gh fallback without a token: ok=%v err=%v", ok, err)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "token")
+	api, ok, err := githubAPIForHost("github.com")
+	if err != nil || !ok || api.token != "token" || api.baseURL != "https://api.github.com" {
+		t.Fatalf("unexpected api: %+v ok=%v err=%v", api, ok, err)
+	}
+
+	githubLookPath = func(string) (string, error) { return "", errors.New("not found") }
+	api, ok, err = githubAPIForHost("ghe.example.com")
+	if err != nil || !ok || api.token != "" || api.baseURL != "https://ghe.example.com/api/v3" {
+		t.Fatalf("unexpected api: %+v ok=%v err=%v", api, ok, err)
+	}
+}
+
+func TestGitHubAPICreatePRAndUpdateBody(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/pulls":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 5, "html_url": "https://ghe.example.com/owner/repo/pull/5"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/pulls/5":
+			_, _ = w.Write([]byte(`{"number": 5, "body": "Edited on the web.\n\nRelated PRs:\n- web: https://ghe.example.com/owner/web/pull/1"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/repo/pulls/5":
+			_, _ = w.Write([]byte(`{"number": 5}`))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"Validation Failed"}`))
+		}
+	}))
+	defer server.Close()
+	setupGitHubHostsFile(t, "ghe.example.com", server.URL, "secret")
+
+	pr, err := githubProvider{}.CreatePR(context.Background(), "ghe.example.com", "owner", "repo", prCreateRequest{Head: "WS-1", Base: "main", Title: "Fix", Body: "details"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Number != 5 || pr.URL != "https://ghe.example.com/owner/repo/pull/5" {
+		t.Fatalf("unexpected PR: %+v", pr)
+	}
+	if err := (githubProvider{}).UpdatePRBody(context.Background(), "ghe.example.com", "owner", "repo", 5, "linked"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A PR recorded by an earlier run is located by its URL, which needs the host's provider;
+	// only its Related PRs section is replaced, so edits made on the web are kept.
+	setupHostsFile(t, fmt.Sprintf("ghe.example.com:\n  provider: github\n  token: secret\n  api_url: %s\n", server.URL))
+	links := []prcreate.Link{{Alias: "repo", URL: pr.URL}, {Alias: "web", URL: "https://ghe.example.com/owner/web/pull/2"}}
+	if err := updateRecordedPRBody(context.Background(), pr.URL, "repo", links); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		`POST /repos/owner/repo/pulls {"base":"main","body":"details","head":"WS-1","title":"Fix"}`,
+		`PATCH /repos/owner/repo/pulls/5 {"body":"linked"}`,
+		`GET /repos/owner/repo/pulls/5 `,
+		`PATCH /repos/owner/repo/pulls/5 {"body":"Edited on the web.\n\nRelated PRs:\n- web: https://ghe.example.com/owner/web/pull/2"}`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected requests:\n%s", strings.Join(requests, "\n"))
+	}
+
+	_, err = githubProvider{}.CreatePR(context.Background(), "ghe.example.com", "owner", "other", prCreateRequest{Head: "WS-1", Base: "main", Title: "Fix"})
+	if err == nil || !strings.Contains(err.Error(), "Validation Failed") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
@@ -0,0 +1,311 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// gitlabURLEnv points at a self-hosted GitLab instance (e.g. https://git.example.com or
+	// https://example.com/gitlab). Its host is treated as GitLab even without "gitlab" in
+	// the name, and its URL (path prefix included) is used for API and web links.
+	gitlabURLEnv = "GION_GITLAB_URL"
+	// gitlabTokenEnv holds a personal access token (read_api scope). It is sent only to
+	// gitlab.com and the GION_GITLAB_URL host, and a hosts.yaml token wins over it. Without
+	// a token only public projects can be read.
+	gitlabTokenEnv = "GITLAB_TOKEN"
+)
+
+type gitlabProvider struct{}
+
+func (gitlabProvider) Name() string {
+	return "gitlab"
+}
+
+func (gitlabProvider) FetchIssues(ctx context.Context, host, owner, repoName string) ([]issueSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" {
+		return nil, fmt.Errorf("owner/repo is required")
+	}
+	var raw []gitlabIssueItem
+	query := url.Values{"state": {"opened"}, "order_by": {"updated_at"}, "sort": {"desc"}, "per_page": {"50"}}
+	if err := gitlabGet(ctx, host, gitlabProjectPath(owner, repoName)+"/issues", query, &raw); err != nil {
+		return nil, err
+	}
+	var issues []issueSummary
+	for _, item := range raw {
+		if item.IID == 0 {
+			continue
+		}
+		issues = append(issues, issueSummary{Number: item.IID, Title: strings.TrimSpace(item.Title)})
+	}
+	return issues, nil
+}
+
+func (gitlabProvider) FetchIssue(ctx context.Context, host, owner, repoName string, number int) (issueSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" || number <= 0 {
+		return issueSummary{}, fmt.Errorf("owner/repo and issue number are required")
+	}
+	var item gitlabIssueItem
+	if err := gitlabGet(ctx, host, fmt.Sprintf("%s/issues/%d", gitlabProjectPath(owner, repoName), number), nil, &item); err != nil {
+		return issueSummary{}, err
+	}
+	if item.IID == 0 {
+		return issueSummary{}, fmt.Errorf("issue not found")
+	}
+	return issueSummary{Number: item.IID, Title: strings.TrimSpace(item.Title)}, nil
+}
+
+func (gitlabProvider) FetchPRs(ctx context.Context, host, owner, repoName string) ([]prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" {
+		return nil, fmt.Errorf("owner/repo is required")
+	}
+	var raw []gitlabMRItem
+	query := url.Values{"state": {"opened"}, "order_by": {"updated_at"}, "sort": {"desc"}, "per_page": {"50"}}
+	if err := gitlabGet(ctx, host, gitlabProjectPath(owner, repoName)+"/merge_requests", query, &raw); err != nil {
+		return nil, err
+	}
+	var prs []prSummary
+	for _, item := range raw {
+		if item.IID == 0 {
+			continue
+		}
+		prs = append(prs, normalizeGitLabMR(item, owner, repoName))
+	}
+	return prs, nil
+}
+
+func (gitlabProvider) FetchPR(ctx context.Context, host, owner, repoName string, number int) (prSummary, error) {
+	if strings.TrimSpace(owner) == "" || strings.TrimSpace(repoName) == "" || number <= 0 {
+		return prSummary{}, fmt.Errorf("owner/repo and MR number are required")
+	}
+	var item gitlabMRItem
+	if err := gitlabGet(ctx, host, fmt.Sprintf("%s/merge_requests/%d", gitlabProjectPath(owner, repoName), number), nil, &item); err != nil {
+		return prSummary{}, err
+	}
+	if item.IID == 0 {
+		return prSummary{}, fmt.Errorf("merge request not found")
+	}
+	return normalizeGitLabMR(item, owner, repoName), nil
+}
+
+type gitlabIssueItem struct {
+	IID   int    `json:"iid"`
+	Title string `json:"title"`
+}
+
+type gitlabMRItem struct {
+	IID             int    `json:"iid"`
+	Title           string `json:"title"`
+	SourceBranch    string `json:"source_branch"`
+	TargetBranch    string `json:"target_branch"`
+	SourceProjectID int    `json:"source_project_id"`
+	TargetProjectID int    `json:"target_project_id"`
+	Description     string `json:"description"`
+}
+
+// normalizeGitLabMR maps a merge request onto prSummary. The API only returns project IDs,
+// so a fork's head repo is reported by ID (the review flow rejects forks anyway).
+func normalizeGitLabMR(item gitlabMRItem, owner, repoName string) prSummary {
+	baseRepo := fmt.Sprintf("%s/%s", strings.TrimSpace(owner), strings.TrimSuffix(strings.TrimSpace(repoName), ".git"))
+	headRepo := baseRepo
+	if item.SourceProjectID != item.TargetProjectID {
+		headRepo = fmt.Sprintf("project %d", item.SourceProjectID)
+	}
+	return prSummary{
+		Number:   item.IID,
+		Title:    strings.TrimSpace(item.Title),
+		HeadRef:  strings.TrimSpace(item.SourceBranch),
+		BaseRef:  strings.TrimSpace(item.TargetBranch),
+		HeadRepo: headRepo,
+		BaseRepo: baseRepo,
+		Body:     item.Description,
+	}
+}
+
+// gitlabProjectPath is the API path of a project, addressed by its URL-encoded full path.
+func gitlabProjectPath(owner, repoName string) string {
+	return "projects/" + url.PathEscape(strings.TrimSpace(owner)) + "%2F" + url.PathEscape(strings.TrimSuffix(strings.TrimSpace(repoName), ".git"))
+}
+
+func gitlabGet(ctx context.Context, host, path string, query url.Values, out any) error {
+	return gitlabDo(ctx, host, http.MethodGet, path, query, nil, out)
+}
+
+func gitlabDo(ctx context.Context, host, method, path string, query url.Values, payload, out any) error {
+	entry, err := loadHostConfig(host)
+	if err != nil {
+		return err
+	}
+	endpoint := hostAPIURL(entry, gitlabBaseURL(host)+"/api/v4") + "/" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	header := map[string]string{}
+	if token := hostToken(host, entry, envToken{key: gitlabTokenEnv, hosts: []string{"gitlab.com", configuredGitLabHost()}}); token != "" {
+		header["PRIVATE-TOKEN"] = token
+	}
+	_, err = providerDoJSON(ctx, "gitlab", method, endpoint, header, payload, out)
+	return err
+}
+
+// gitlabBaseURL returns the instance URL for host: GION_GITLAB_URL when it names the same
+// host, else https://<host>.
+func gitlabBaseURL(host string) string {
+	if configured, ok := configuredGitLabURL(); ok && strings.EqualFold(configured.Hostname(), strings.TrimSpace(host)) {
+		return strings.TrimRight(configured.String(), "/")
+	}
+	return "https://" + strings.TrimSpace(host)
+}
+
+func configuredGitLabURL() (*url.URL, bool) {
+	raw := strings.TrimSpace(os.Getenv(gitlabURLEnv))
+	if raw == "" {
+		return nil, false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Hostname() == "" {
+		return nil, false
+	}
+	return u, true
+}
+
+func isGitLabHost(host string) bool {
+	return providerNameForHost(host) == "gitlab"
+}
+
+// isConfiguredGitLabHost reports whether host is the host of GION_GITLAB_URL.
+func isConfiguredGitLabHost(host string) bool {
+	configured := configuredGitLabHost()
+	return configured != "" && strings.EqualFold(configured, strings.TrimSpace(host))
+}
+
+// configuredGitLabHost returns the host of GION_GITLAB_URL ("" when unset).
+func configuredGitLabHost() string {
+	configured, ok := configuredGitLabURL()
+	if !ok {
+		return ""
+	}
+	return configured.Hostname()
+}
+
+// trimGitLabPathPrefix drops the path prefix of a configured instance (GION_GITLAB_URL
+// https://example.com/gitlab serves projects under /gitlab/...).
+func trimGitLabPathPrefix(host string, parts []string) []string {
+	configured, ok := configuredGitLabURL()
+	if !ok || !strings.EqualFold(configured.Hostname(), strings.TrimSpace(host)) {
+		return parts
+	}
+	prefix := strings.Split(strings.Trim(configured.Path, "/"), "/")
+	if len(prefix) == 1 && prefix[0] == "" {
+		return parts
+	}
+	if len(parts) < len(prefix) {
+		return parts
+	}
+	for i, segment := range prefix {
+		if parts[i] != segment {
+			return parts
+		}
+	}
+	return parts[len(prefix):]
+}
+
+func (gitlabProvider) FetchPRStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State        string `json:"state"`
+		Draft        bool   `json:"draft"`
+		HeadPipeline *struct {
+			Status string `json:"status"`
+		} `json:"head_pipeline"`
+	}
+	mrPath := fmt.Sprintf("%s/merge_requests/%d", gitlabProjectPath(owner, repoName), number)
+	if err := gitlabGet(ctx, host, mrPath, nil, &item); err != nil {
+		return remoteStatus{}, err
+	}
+	status := remoteStatus{Kind: remoteKindPR, Number: number, State: remoteStateOpen}
+	switch item.State {
+	case "merged":
+		return remoteStatus{Kind: remoteKindPR, Number: number, State: remoteStateMerged}, nil
+	case "closed", "locked":
+		return remoteStatus{Kind: remoteKindPR, Number: number, State: remoteStateClosed}, nil
+	}
+	if item.Draft {
+		status.State = remoteStateDraft
+	}
+	if item.HeadPipeline != nil {
+		status.Checks = gitlabPipelineResult(item.HeadPipeline.Status)
+	}
+
+	var approvals struct {
+		ApprovalsLeft int `json:"approvals_left"`
+		ApprovedBy    []struct {
+			User struct {
+				Username string `json:"username"`
+			} `json:"user"`
+		} `json:"approved_by"`
+	}
+	if err := gitlabGet(ctx, host, mrPath+"/approvals", nil, &approvals); err != nil {
+		return remoteStatus{}, err
+	}
+	switch {
+	case approvals.ApprovalsLeft > 0:
+		status.Review = remoteReviewRequired
+	case len(approvals.ApprovedBy) > 0:
+		status.Review = remoteReviewApproved
+	}
+	return status, nil
+}
+
+func (gitlabProvider) FetchIssueStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State string `json:"state"`
+	}
+	if err := gitlabGet(ctx, host, fmt.Sprintf("%s/issues/%d", gitlabProjectPath(owner, repoName), number), nil, &item); err != nil {
+		return remoteStatus{}, err
+	}
+	state := remoteStateOpen
+	if item.State == "closed" {
+		state = remoteStateClosed
+	}
+	return remoteStatus{Kind: remoteKindIssue, Number: number, State: state}, nil
+}
+
+// gitlabPipelineResult maps a pipeline status onto a summarizeChecks result.
+func gitlabPipelineResult(status string) string {
+	switch status {
+	case "success":
+		return remoteChecksPassing
+	case "failed", "canceled":
+		return remoteChecksFailing
+	case "skipped", "":
+		return ""
+	default:
+		return remoteChecksPending
+	}
+}
+
+func (gitlabProvider) CreatePR(ctx context.Context, host, owner, repoName string, req prCreateRequest) (createdPR, error) {
+	payload := map[string]string{
+		"source_branch": req.Head,
+		"target_branch": req.Base,
+		"title":         req.Title,
+		"description":   req.Body,
+	}
+	var item struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	if err := gitlabDo(ctx, host, http.MethodPost, gitlabProjectPath(owner, repoName)+"/merge_requests", nil, payload, &item); err != nil {
+		return createdPR{}, err
+	}
+	return createdPR{Number: item.IID, URL: item.WebURL}, nil
+}
+
+func (gitlabProvider) UpdatePRBody(ctx context.Context, host, owner, repoName string, number int, body string) error {
+	mrPath := fmt.Sprintf("%s/merge_requests/%d", gitlabProjectPath(owner, repoName), number)
+	return gitlabDo(ctx, host, http.MethodPut, mrPath, nil, map[string]string{"description": body}, nil)
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newFakeGitLab(t *testing.T, routes map[string]string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"401 Unauthorized"}`))
+			return
+		}
+		body, ok := routes[r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 Project Not Found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(gitlabURLEnv, server.URL)
+	t.Setenv(gitlabTokenEnv, "secret")
+	return "127.0.0.1"
+}
+
+func TestGitLabProviderFetchPRs(t *testing.T) {
+	host := newFakeGitLab(t, map[string]string{
+		"/api/v4/projects/owner%2Frepo/merge_requests": `[
+			{"iid": 3, "title": " Fix bug ", "source_branch": "fix/bug", "target_branch": "main", "source_project_id": 10, "target_project_id": 10},
+			{"iid": 4, "title": "From fork", "source_branch": "feature", "target_branch": "main", "source_project_id": 99, "target_project_id": 10}
+		]`,
+	})
+	if !isGitLabHost(host) {
+		t.Fatalf("expected %s to be treated as GitLab", host)
+	}
+	prs, err := gitlabProvider{}.FetchPRs(context.Background(), host, "owner", "repo.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("expected 2 MRs, got %d", len(prs))
+	}
+	first := prs[0]
+	if first.Number != 3 || first.Title != "Fix bug" || first.HeadRef != "fix/bug" || first.BaseRef != "main" || first.HeadRepo != "owner/repo" || first.BaseRepo != "owner/repo" {
+		t.Fatalf("unexpected MR: %+v", first)
+	}
+	if prs[1].HeadRepo == prs[1].BaseRepo {
+		t.Fatalf("expected fork MR to have a different head repo: %+v", prs[1])
+	}
+}
+
+func TestGitLabProviderFetchIssue(t *testing.T) {
+	host := newFakeGitLab(t, map[string]string{
+		"/api/v4/projects/owner%2Frepo/issues/45": `{"iid": 45, "title": "Broken login"}`,
+	})
+	issue, err := gitlabProvider{}.FetchIssue(context.Background(), host, "owner", "repo", 45)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issue.Number != 45 || issue.Title != "Broken login" {
+		t.Fatalf("unexpected issue: %+v", issue)
+	}
+}
+
+func TestGitLabProviderError(t *testing.T) {
+	host := newFakeGitLab(t, map[string]string{})
+	_, err := gitlabProvider{}.FetchIssues(context.Background(), host, "owner", "missing")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "404 Project Not Found") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
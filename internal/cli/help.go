@@ -30,6 +30,11 @@ func printGlobalHelp(w io.Writer) {
 	fmt.Fprintln(w, helpCommand(theme, useColor, "plan", fmt.Sprintf("show %s diff (no changes)", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "import", fmt.Sprintf("rebuild %s from filesystem", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "apply", fmt.Sprintf("apply %s to filesystem", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "status [<WORKSPACE_ID> ...]", "show git status of workspace repos (alias: st)"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "exec <WORKSPACE_ID> -- <cmd>", "run a command in every repo of a workspace"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "sync <WORKSPACE_ID>", "rebase or merge workspace branches onto their base ref"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "push <WORKSPACE_ID>", "push workspace branches to origin and set upstream"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "pr create <WORKSPACE_ID>", "push and open one cross-linked PR per repo"))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "repo <subcommand>", "repo commands (get/ls/rm)"))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "doctor [--fix | --self]", "check workspace/repo health"))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "version", "print version"))
@@ -39,6 +44,7 @@ func printGlobalHelp(w io.Writer) {
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--root <path>", "override root"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--debug", "write debug logs to file"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--lock-timeout <duration>", "wait for a concurrent gion command to release the root (default: 30s)"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--version", "print version"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--help, -h", "show help"))
 }
@@ -57,6 +63,16 @@ func printCommandHelp(cmd string, w io.Writer) bool {
 		printImportHelp(w)
 	case "apply":
 		printApplyHelp(w)
+	case "exec":
+		printExecHelp(w)
+	case "status", "st":
+		printStatusHelp(w)
+	case "sync":
+		printSyncHelp(w)
+	case "push":
+		printPushHelp(w)
+	case "pr":
+		printPRHelp(w)
 	case "init":
 		printInitHelp(w)
 	case "version":
@@ -67,6 +83,63 @@ func printCommandHelp(cmd string, w io.Writer) bool {
 	return true
 }
 
+func printExecHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion exec (<WORKSPACE_ID> | --all-workspaces) [--repo <alias>]... [--jobs <n>] -- <command> [args...]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--repo <alias>", "run only in this repo (repeatable)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--jobs <n>", "run in up to n repos at once (default: 1)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--all-workspaces", "run in every workspace instead of one"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Output lines are prefixed with the repo alias; exits non-zero if the command fails in any repo.")
+}
+
+func printStatusHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion status [<WORKSPACE_ID> ...] [-v] [--json]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Aliases: gion st")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "<WORKSPACE_ID>", "workspaces to show (default: all)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "-v, --verbose", "list changed files under each repo"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--json", "print JSON"))
+}
+
+func printSyncHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion sync <WORKSPACE_ID> [--strategy rebase|merge|ff-only]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--strategy <name>", "rebase, merge, or ff-only (default: rebase)"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Fetches the repo stores, then moves each branch onto its base_ref. Dirty repos are skipped,")
+	fmt.Fprintln(w, "as are pushed commits a rebase would rewrite (use --strategy merge); the first conflict is")
+	fmt.Fprintln(w, "aborted and stops the sync.")
+}
+
+func printPushHelp(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gion push <WORKSPACE_ID>")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Fetches the repo stores, then pushes every repo branch with commits origin does not have to")
+	fmt.Fprintln(w, "origin/<branch> and sets it as upstream. Repos with nothing to push are skipped (only their")
+	fmt.Fprintln(w, "upstream is set when origin/<branch> exists); uncommitted changes are not pushed.")
+}
+
+func printPRHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion pr <subcommand>")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, helpSectionTitle(theme, useColor, "Subcommands:"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "create <WORKSPACE_ID>", "push and open one PR per repo with commits ahead of its base"))
+}
+
+func printPRCreateHelp(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gion pr create <WORKSPACE_ID>")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Opens a PR/MR for every repo branch with commits ahead of its base (base_ref, else the")
+	fmt.Fprintln(w, "workspace base branch, else origin's default branch), pushing the branch first when needed.")
+	fmt.Fprintln(w, "The first line of the workspace description is the title and the rest is the body; each")
+	fmt.Fprintln(w, "new PR links its sibling PRs. PR URLs are recorded in the workspace metadata, and repos")
+	fmt.Fprintln(w, "that already have a recorded PR are skipped.")
+}
+
 func printRepoHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
 	fmt.Fprintln(w, "Usage: gion repo <subcommand>")
@@ -101,14 +174,21 @@ func printManifestHelp(w io.Writer) {
 	fmt.Fprintln(w, helpCommand(theme, useColor, "ls", "list workspace inventory with drift tags"))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "add [mode flags] [args]", fmt.Sprintf("add workspace to %s then apply (default)", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "rm [<WORKSPACE_ID> ...]", fmt.Sprintf("remove workspace entries from %s then apply (default)", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "mv <OLD_ID> <NEW_ID>", fmt.Sprintf("rename a workspace in %s then apply (default)", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "archive <WORKSPACE_ID>", "save unpushed commits and local changes, then remove the workspace"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "restore <WORKSPACE_ID>", "recreate an archived workspace"))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "gc", fmt.Sprintf("conservatively remove safe workspaces from %s then apply (default)", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "validate", fmt.Sprintf("validate %s inventory", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "history", fmt.Sprintf("list previous %s revisions", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "undo [N]", fmt.Sprintf("restore a previous %s revision then apply (default)", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "preset <subcommand>", "preset inventory commands (aliases: pre, p)"))
 }
 
 func printManifestLsHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
-	fmt.Fprintln(w, "Usage: gion manifest ls [--no-prompt]")
+	fmt.Fprintln(w, "Usage: gion manifest ls [--remote [--refresh]] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--remote", "show PR/issue status (state, review, checks) for workspaces created from a PR or issue"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--refresh", "ignore the remote status cache (with --remote)"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "accepted for compatibility (no effect)"))
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, helpSectionTitle(theme, useColor, "Statuses:"))
@@ -125,8 +205,8 @@ func printManifestAddHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
 	fmt.Fprintln(w, "Usage: gion manifest add [--preset <name> | --review [<PR URL>] | --issue <ISSUE_URL> | --repo <repo>] [<WORKSPACE_ID>] [--branch <name>] [--base <ref>] [--no-apply] [--no-prompt]")
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--preset <name>", "preset name"))
-	fmt.Fprintln(w, helpFlag(theme, useColor, "--review [<PR URL>]", "add review workspace from PR (GitHub only)"))
-	fmt.Fprintln(w, helpFlag(theme, useColor, "--issue <ISSUE_URL>", "add issue workspace from issue (GitHub only)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--review [<PR URL>]", "add review workspace from PR/MR (GitHub/GitLab/Gitea/Bitbucket)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--issue <ISSUE_URL>", "add issue workspace from issue (GitHub/GitLab/Gitea/Bitbucket Cloud)"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--repo <repo>", "add workspace from a repo"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--branch <name>", "override branch name (repo/issue modes only)"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--base <ref>", "override base ref (issue mode; applies to all repos in no-prompt)"))
@@ -141,9 +221,33 @@ func printManifestRmHelp(w io.Writer) {
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
 }
 
+func printManifestMvHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest mv <OLD_WORKSPACE_ID> <NEW_WORKSPACE_ID> [--no-apply] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-apply", fmt.Sprintf("update %s only (do not run gion apply)", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
+}
+
+func printManifestArchiveHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest archive <WORKSPACE_ID> [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "archive without confirmation"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Writes a bundle of each repo's commits that origin lacks and a patch of its uncommitted")
+	fmt.Fprintln(w, fmt.Sprintf("changes to <root>/archive/<WORKSPACE_ID>/, then removes the workspace and its %s entry.", manifest.FileName))
+	fmt.Fprintln(w, "Ignored files are not archived; stash entries are not either, but stay in the repo store.")
+}
+
+func printManifestRestoreHelp(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gion manifest restore <WORKSPACE_ID>")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, fmt.Sprintf("Recreates an archived workspace (branches, local changes, %s entry) and deletes the archive.", manifest.FileName))
+}
+
 func printManifestGcHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
-	fmt.Fprintln(w, "Usage: gion manifest gc [--no-apply] [--no-fetch] [--no-prompt]")
+	fmt.Fprintln(w, "Usage: gion manifest gc [--merge-detection=strict|squash] [--no-apply] [--no-fetch] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--merge-detection", "strict: branch head is on the target (default); squash: also accept squash and rebase merges"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-apply", fmt.Sprintf("update %s only (do not run gion apply)", manifest.FileName)))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-fetch", "disable git fetch for repo stores"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
@@ -155,6 +259,23 @@ func printManifestValidateHelp(w io.Writer) {
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "accepted for compatibility (no effect)"))
 }
 
+func printManifestHistoryHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest history [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "accepted for compatibility (no effect)"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, helpSectionTitle(theme, useColor, "Notes:"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "revisions", fmt.Sprintf("newest first; the last %d are kept under <root>/.gion/manifest-history", manifest.HistoryLimit)))
+}
+
+func printManifestUndoHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest undo [N] [--no-apply] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "N", "revision number from gion manifest history (default: 1, the state before the last change)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-apply", fmt.Sprintf("update %s only (do not run gion apply)", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
+}
+
 func printManifestPresetHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
 	fmt.Fprintln(w, "Usage: gion manifest preset <subcommand>")
@@ -209,11 +330,22 @@ func printImportHelp(w io.Writer) {
 }
 
 func printPlanHelp(w io.Writer) {
-	fmt.Fprintln(w, "Usage: gion plan")
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion plan [--format text|json|yaml] [--out <file>] [--detailed-exitcode]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--format <format>", "output format: text (default), json, yaml"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--out <file>", "save the plan for gion apply <file>"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--detailed-exitcode", "exit 0 = no changes, 1 = error, 2 = changes pending, 3 = destructive changes pending"))
 }
 
 func printApplyHelp(w io.Writer) {
-	fmt.Fprintln(w, "Usage: gion apply")
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion apply [--target <workspace-id>[/<alias>]]... [--concurrency <n>] [<planfile>]")
+	fmt.Fprintln(w, "       gion apply --resume | --rollback")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--target <selector>", "apply only changes for a workspace or workspace/alias (repeatable)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--concurrency <n>", "repo stores to create worktrees for in parallel (default: 4; 1 = sequential)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--resume", "continue an apply that stopped partway"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--rollback", "undo worktrees/branches created by an apply that stopped partway"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "<planfile>", "apply a plan saved by gion plan --out (refused if state changed)"))
 }
 
 func helpTheme(w io.Writer) (ui.Theme, bool) {
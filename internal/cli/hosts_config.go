@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// hostsFileName lives in <user config dir>/gion (e.g. ~/.config/gion/hosts.yaml) and
+// configures the review/issue providers per host:
+//
+//	github.com:
+//	  token: ghp_xxx
+//	git.example.com:
+//	  provider: gitea
+//	  token: xxx
+//	bitbucket.example.com:
+//	  provider: bitbucket-server
+//	  api_url: https://bitbucket.example.com/rest/api/1.0
+//
+// provider overrides the host name heuristics of providerNameForHost; token and api_url
+// override the provider's environment variables and default API URL.
+const hostsFileName = "hosts.yaml"
+
+type hostConfig struct {
+	Provider string `yaml:"provider"`
+	Token    string `yaml:"token"`
+	// Username switches Bitbucket Cloud to basic auth (username + app password as token).
+	Username string `yaml:"username"`
+	APIURL   string `yaml:"api_url"`
+}
+
+func hostsFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gion", hostsFileName), nil
+}
+
+// loadHostConfig returns the hosts.yaml entry of host (zero value when the file or the
+// entry does not exist).
+func loadHostConfig(host string) (hostConfig, error) {
+	path, err := hostsFilePath()
+	if err != nil {
+		return hostConfig{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return hostConfig{}, nil
+		}
+		return hostConfig{}, err
+	}
+	var hosts map[string]hostConfig
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return hostConfig{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	host = strings.TrimSpace(host)
+	if host == "" {
+		host = "github.com"
+	}
+	for name, entry := range hosts {
+		if strings.EqualFold(strings.TrimSpace(name), host) {
+			return entry, nil
+		}
+	}
+	return hostConfig{}, nil
+}
+
+// envToken is a token environment variable and the hosts it belongs to.
+type envToken struct {
+	key   string
+	hosts []string
+}
+
+// hostToken returns the hosts.yaml token of host, else the first environment token that
+// belongs to host. Environment tokens are never sent to other hosts, so a host that only
+// looks like a provider (gitlab.attacker.example) gets none.
+func hostToken(host string, entry hostConfig, tokens ...envToken) string {
+	if token := strings.TrimSpace(entry.Token); token != "" {
+		return token
+	}
+	host = strings.TrimSpace(host)
+	for _, candidate := range tokens {
+		for _, tokenHost := range candidate.hosts {
+			if tokenHost == "" || !strings.EqualFold(strings.TrimSpace(tokenHost), host) {
+				continue
+			}
+			if token := strings.TrimSpace(os.Getenv(candidate.key)); token != "" {
+				return token
+			}
+		}
+	}
+	return ""
+}
+
+// envHost returns the host named by the environment variable key ("" when unset).
+func envHost(key string) string {
+	return strings.TrimSpace(os.Getenv(key))
+}
+
+// hostAPIURL returns the hosts.yaml api_url, else fallback.
+func hostAPIURL(entry hostConfig, fallback string) string {
+	if configured := strings.TrimSpace(entry.APIURL); configured != "" {
+		return strings.TrimRight(configured, "/")
+	}
+	return fallback
+}
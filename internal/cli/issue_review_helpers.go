@@ -42,7 +42,9 @@ func buildIssueRepoChoices(rootDir string) ([]issueRepoChoice, error) {
 			continue
 		}
 		host := parts[0]
-		if !isGitHubHost(host) {
+		providerName, ok := reviewProviderForHost(host)
+		if !ok || providerName == "bitbucket-server" {
+			// Bitbucket Server has no issue tracker.
 			continue
 		}
 		owner := parts[1]
@@ -52,7 +54,7 @@ func buildIssueRepoChoices(rootDir string) ([]issueRepoChoice, error) {
 		choices = append(choices, issueRepoChoice{
 			Label:    label,
 			Value:    value,
-			Provider: "github",
+			Provider: providerName,
 			Host:     host,
 			Owner:    owner,
 			Repo:     repoName,
@@ -103,6 +105,8 @@ type prSummary struct {
 	BaseRef  string
 	HeadRepo string
 	BaseRepo string
+	// Body is the PR description as stored by the provider.
+	Body string
 }
 
 func buildReviewRepoChoices(rootDir string) ([]reviewRepoChoice, error) {
@@ -120,7 +124,8 @@ func buildReviewRepoChoices(rootDir string) ([]reviewRepoChoice, error) {
 		host := parts[0]
 		owner := parts[1]
 		repoName := parts[2]
-		if !isGitHubHost(host) {
+		providerName, ok := reviewProviderForHost(host)
+		if !ok {
 			continue
 		}
 		label := fmt.Sprintf("%s (%s/%s)", repoName, owner, repoName)
@@ -129,7 +134,7 @@ func buildReviewRepoChoices(rootDir string) ([]reviewRepoChoice, error) {
 		choices = append(choices, reviewRepoChoice{
 			Label:    label,
 			Value:    value,
-			Provider: "github",
+			Provider: providerName,
 			Host:     host,
 			Owner:    owner,
 			Repo:     repoName,
@@ -164,11 +169,6 @@ func buildPRChoices(prs []prSummary) []ui.PromptChoice {
 	return choices
 }
 
-func isGitHubHost(host string) bool {
-	lower := strings.ToLower(strings.TrimSpace(host))
-	return strings.Contains(lower, "github")
-}
-
 type githubIssueItem struct {
 	Number      int             `json:"number"`
 	Title       string          `json:"title"`
@@ -255,7 +255,9 @@ func parseGitHubIssues(data []byte) ([]issueSummary, error) {
 		if item.Number == 0 {
 			continue
 		}
-		if len(item.PullRequest) != 0 {
+		// Pull requests show up in the issues list (Gitea sends "pull_request": null
+		// for plain issues).
+		if len(item.PullRequest) != 0 && string(item.PullRequest) != "null" {
 			continue
 		}
 		issues = append(issues, issueSummary{
@@ -269,6 +271,7 @@ func parseGitHubIssues(data []byte) ([]issueSummary, error) {
 type githubPRItem struct {
 	Number int    `json:"number"`
 	Title  string `json:"title"`
+	Body   string `json:"body"`
 	Head   struct {
 		Ref  string `json:"ref"`
 		Repo struct {
@@ -327,6 +330,119 @@ func fetchGitHubPRs(ctx context.Context, host, owner, repoName string) ([]prSumm
 	return parseGitHubPRs([]byte(stdout))
 }
 
+// fetchGitHubPRStatus asks gh for the PR state, review decision, and check rollup.
+func fetchGitHubPRStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State             string `json:"state"`
+		IsDraft           bool   `json:"isDraft"`
+		ReviewDecision    string `json:"reviewDecision"`
+		StatusCheckRollup []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			State      string `json:"state"`
+		} `json:"statusCheckRollup"`
+	}
+	if err := runGitHubCLIView(ctx, "pr", host, owner, repoName, number, "state,isDraft,reviewDecision,statusCheckRollup", &item); err != nil {
+		return remoteStatus{}, err
+	}
+	status := remoteStatus{Kind: remoteKindPR, Number: number, State: remoteStateOpen}
+	switch {
+	case strings.EqualFold(item.State, "MERGED"):
+		status.State = remoteStateMerged
+	case strings.EqualFold(item.State, "CLOSED"):
+		status.State = remoteStateClosed
+	case item.IsDraft:
+		status.State = remoteStateDraft
+	}
+	switch strings.ToUpper(item.ReviewDecision) {
+	case "APPROVED":
+		status.Review = remoteReviewApproved
+	case "CHANGES_REQUESTED":
+		status.Review = remoteReviewChangesRequested
+	case "REVIEW_REQUIRED":
+		status.Review = remoteReviewRequired
+	}
+	var results []string
+	for _, check := range item.StatusCheckRollup {
+		if check.State != "" {
+			results = append(results, githubStatusResult(check.State))
+			continue
+		}
+		results = append(results, githubCheckResult(check.Status, check.Conclusion))
+	}
+	status.Checks = summarizeChecks(results)
+	return status, nil
+}
+
+func fetchGitHubIssueStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	var item struct {
+		State string `json:"state"`
+	}
+	if err := runGitHubCLIView(ctx, "issue", host, owner, repoName, number, "state", &item); err != nil {
+		return remoteStatus{}, err
+	}
+	return remoteStatus{Kind: remoteKindIssue, Number: number, State: githubIssueState(item.State)}, nil
+}
+
+// createGitHubPR runs `gh pr create`, which prints the URL of the new PR.
+func createGitHubPR(ctx context.Context, host, owner, repoName string, req prCreateRequest) (createdPR, error) {
+	stdout, stderr, err := runExternalCommand(ctx, "gh", []string{"pr", "create", "--repo", githubCLIRepoArg(host, owner, repoName), "--head", req.Head, "--base", req.Base, "--title", req.Title, "--body", req.Body})
+	if err != nil {
+		msg := strings.TrimSpace(stderr)
+		if msg != "" {
+			return createdPR{}, fmt.Errorf("gh pr create failed: %s", msg)
+		}
+		return createdPR{}, fmt.Errorf("gh pr create failed: %w", err)
+	}
+	words := strings.Fields(stdout)
+	if len(words) == 0 {
+		return createdPR{}, fmt.Errorf("gh pr create printed no PR URL")
+	}
+	prURL := words[len(words)-1]
+	parsed, err := parsePRURL(prURL)
+	if err != nil {
+		return createdPR{}, fmt.Errorf("gh pr create printed an unexpected URL: %s", prURL)
+	}
+	return createdPR{Number: parsed.Number, URL: prURL}, nil
+}
+
+func updateGitHubPRBody(ctx context.Context, host, owner, repoName string, number int, body string) error {
+	_, stderr, err := runExternalCommand(ctx, "gh", []string{"pr", "edit", strconv.Itoa(number), "--repo", githubCLIRepoArg(host, owner, repoName), "--body", body})
+	if err != nil {
+		msg := strings.TrimSpace(stderr)
+		if msg != "" {
+			return fmt.Errorf("gh pr edit failed: %s", msg)
+		}
+		return fmt.Errorf("gh pr edit failed: %w", err)
+	}
+	return nil
+}
+
+// githubCLIRepoArg is the --repo value for gh: OWNER/REPO, or HOST/OWNER/REPO off github.com.
+func githubCLIRepoArg(host, owner, repoName string) string {
+	repoArg := fmt.Sprintf("%s/%s", owner, strings.TrimSuffix(repoName, ".git"))
+	if host != "" && !strings.EqualFold(host, "github.com") {
+		repoArg = host + "/" + repoArg
+	}
+	return repoArg
+}
+
+// runGitHubCLIView runs `gh <pr|issue> view <number> --repo [HOST/]OWNER/REPO --json fields`.
+func runGitHubCLIView(ctx context.Context, kind, host, owner, repoName string, number int, fields string, out any) error {
+	stdout, stderr, err := runExternalCommand(ctx, "gh", []string{kind, "view", strconv.Itoa(number), "--repo", githubCLIRepoArg(host, owner, repoName), "--json", fields})
+	if err != nil {
+		msg := strings.TrimSpace(stderr)
+		if msg != "" {
+			return fmt.Errorf("gh %s view failed: %s", kind, msg)
+		}
+		return fmt.Errorf("gh %s view failed: %w", kind, err)
+	}
+	if err := json.Unmarshal([]byte(stdout), out); err != nil {
+		return fmt.Errorf("parse gh %s view response: %w", kind, err)
+	}
+	return nil
+}
+
 func parseGitHubPRs(data []byte) ([]prSummary, error) {
 	var raw []githubPRItem
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -350,6 +466,7 @@ func normalizeGitHubPR(item githubPRItem) prSummary {
 		BaseRef:  strings.TrimSpace(item.Base.Ref),
 		HeadRepo: strings.TrimSpace(item.Head.Repo.FullName),
 		BaseRepo: strings.TrimSpace(item.Base.Repo.FullName),
+		Body:     item.Body,
 	}
 }
 
@@ -437,7 +554,7 @@ func parseIssueURL(raw string) (issueRequest, error) {
 		return issueRequest{}, fmt.Errorf("invalid issue URL: %w", err)
 	}
 	host := strings.TrimSpace(u.Hostname())
-	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	parts := trimGitLabPathPrefix(host, strings.Split(strings.Trim(u.Path, "/"), "/"))
 	if len(parts) < 4 {
 		return issueRequest{}, fmt.Errorf("invalid issue URL path: %s", u.Path)
 	}
@@ -461,7 +578,7 @@ func parseIssueURL(raw string) (issueRequest, error) {
 		provider := issueProvider(host, repoIdx, i)
 		if provider == "gitlab" {
 			if len(ownerParts) != 1 {
-				return issueRequest{}, fmt.Errorf("nested groups are not supported: %s", strings.Join(ownerParts, "/"))
+				return issueRequest{}, gitlabNestedGroupError(ownerParts)
 			}
 		} else if len(ownerParts) != 1 {
 			return issueRequest{}, fmt.Errorf("invalid issue URL path: %s", u.Path)
@@ -478,13 +595,18 @@ func parseIssueURL(raw string) (issueRequest, error) {
 	return issueRequest{}, fmt.Errorf("unsupported issue URL: %s", raw)
 }
 
+// gitlabNestedGroupError rejects a project in a GitLab subgroup: repo keys and store paths
+// are host/owner/repo, so the project could not be cloned into a workspace anyway.
+func gitlabNestedGroupError(groups []string) error {
+	return fmt.Errorf("nested groups are not supported (repos must be <host>/<group>/<project>): %s", strings.Join(groups, "/"))
+}
+
 func issueProvider(host string, repoIdx, issueIdx int) string {
-	lowerHost := strings.ToLower(strings.TrimSpace(host))
-	if repoIdx < issueIdx-1 || strings.Contains(lowerHost, "gitlab") {
+	if repoIdx < issueIdx-1 {
 		return "gitlab"
 	}
-	if strings.Contains(lowerHost, "bitbucket") {
-		return "bitbucket"
+	if name := providerNameForHost(host); name != "" {
+		return name
 	}
 	return "github"
 }
@@ -506,7 +628,8 @@ func parsePRURL(raw string) (prRequest, error) {
 	if host == "" {
 		return prRequest{}, fmt.Errorf("invalid PR URL host: %s", raw)
 	}
-	if !isGitHubHost(host) {
+	provider, ok := reviewProviderForHost(host)
+	if !ok {
 		return prRequest{}, fmt.Errorf("unsupported PR host: %s", host)
 	}
 	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
@@ -514,15 +637,68 @@ func parsePRURL(raw string) (prRequest, error) {
 		return prRequest{}, fmt.Errorf("invalid PR/MR URL path: %s", u.Path)
 	}
 
-	// GitHub style: /owner/repo/pull/123
+	if provider == "gitlab" {
+		// GitLab style: /group/project/-/merge_requests/123 (a self-hosted instance may
+		// live under a path prefix).
+		parts = trimGitLabPathPrefix(host, parts)
+		for i := 0; i < len(parts)-1; i++ {
+			if parts[i] != "merge_requests" || i < 3 || parts[i-1] != "-" {
+				continue
+			}
+			num, err := strconv.Atoi(parts[i+1])
+			if err != nil {
+				return prRequest{}, fmt.Errorf("invalid MR number: %s", parts[i+1])
+			}
+			if i != 3 {
+				return prRequest{}, gitlabNestedGroupError(parts[:i-2])
+			}
+			return prRequest{
+				Provider: "gitlab",
+				Host:     host,
+				Owner:    parts[0],
+				Repo:     parts[1],
+				Number:   num,
+			}, nil
+		}
+		return prRequest{}, fmt.Errorf("unsupported PR/MR URL: %s", raw)
+	}
+
+	if provider == "bitbucket-server" {
+		// Bitbucket Server style: /projects/KEY/repos/slug/pull-requests/123
+		for i := 0; i < len(parts)-1; i++ {
+			if parts[i] != "pull-requests" || i < 4 || parts[i-4] != "projects" || parts[i-2] != "repos" {
+				continue
+			}
+			num, err := strconv.Atoi(parts[i+1])
+			if err != nil {
+				return prRequest{}, fmt.Errorf("invalid PR number: %s", parts[i+1])
+			}
+			return prRequest{
+				Provider: provider,
+				Host:     host,
+				Owner:    parts[i-3],
+				Repo:     parts[i-1],
+				Number:   num,
+			}, nil
+		}
+		return prRequest{}, fmt.Errorf("unsupported PR/MR URL: %s", raw)
+	}
+
+	// GitHub style: /owner/repo/pull/123 (Gitea/Forgejo: pulls, Bitbucket: pull-requests)
+	segment := map[string]string{
+		"github":    "pull",
+		"gitea":     "pulls",
+		"forgejo":   "pulls",
+		"bitbucket": "pull-requests",
+	}[provider]
 	for i := 0; i < len(parts)-1; i++ {
-		if parts[i] == "pull" && i >= 2 {
+		if segment != "" && parts[i] == segment && i >= 2 {
 			num, err := strconv.Atoi(parts[i+1])
 			if err != nil {
 				return prRequest{}, fmt.Errorf("invalid PR number: %s", parts[i+1])
 			}
 			return prRequest{
-				Provider: "github",
+				Provider: provider,
 				Host:     host,
 				Owner:    parts[i-2],
 				Repo:     parts[i-1],
@@ -546,12 +722,26 @@ func buildRepoURLFromParts(host, owner, repoName string) string {
 
 func buildIssueURLFromParts(host, owner, repoName string, number int) string {
 	repoName = strings.TrimSuffix(repoName, ".git")
+	if isGitLabHost(host) {
+		return fmt.Sprintf("%s/%s/%s/-/issues/%d", gitlabBaseURL(host), owner, repoName, number)
+	}
 	return fmt.Sprintf("https://%s/%s/%s/issues/%d", host, owner, repoName, number)
 }
 
 func buildPRURLFromParts(host, owner, repoName string, number int) string {
 	repoName = strings.TrimSuffix(repoName, ".git")
-	return fmt.Sprintf("https://%s/%s/%s/pull/%d", host, owner, repoName, number)
+	switch providerNameForHost(host) {
+	case "gitlab":
+		return fmt.Sprintf("%s/%s/%s/-/merge_requests/%d", gitlabBaseURL(host), owner, repoName, number)
+	case "gitea", "forgejo":
+		return fmt.Sprintf("https://%s/%s/%s/pulls/%d", host, owner, repoName, number)
+	case "bitbucket":
+		return fmt.Sprintf("https://%s/%s/%s/pull-requests/%d", host, owner, repoName, number)
+	case "bitbucket-server":
+		return fmt.Sprintf("https://%s/projects/%s/repos/%s/pull-requests/%d", host, owner, repoName, number)
+	default:
+		return fmt.Sprintf("https://%s/%s/%s/pull/%d", host, owner, repoName, number)
+	}
 }
 
 func formatPRBaseRef(baseBranch string) string {
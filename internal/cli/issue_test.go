@@ -1,6 +1,9 @@
 package cli
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestParseIssueURLGitHub(t *testing.T) {
 	req, err := parseIssueURL("https://github.com/owner/repo/issues/123")
@@ -46,7 +49,7 @@ func TestParseIssueURLUnsupported(t *testing.T) {
 	if _, err := parseIssueURL("https://github.com/owner/repo/pull/1"); err == nil {
 		t.Fatalf("expected error for non-issue URL")
 	}
-	if _, err := parseIssueURL("https://gitlab.com/group/sub/repo/-/issues/1"); err == nil {
-		t.Fatalf("expected error for nested groups (not supported)")
+	if _, err := parseIssueURL("https://gitlab.com/group/sub/repo/-/issues/1"); err == nil || !strings.Contains(err.Error(), "nested groups are not supported") {
+		t.Fatalf("expected error for nested groups (not supported), got %v", err)
 	}
 }
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/gion/internal/infra/paths"
+	"github.com/tasuku43/gion/internal/infra/rootlock"
+)
+
+// commandWritesRoot reports whether the command may write gion.yaml, workspace metadata,
+// worktrees, or repo stores, and therefore must hold the root lock. Read-only commands
+// (plan, manifest ls/validate/history, repo ls, doctor without --fix) stay lock-free.
+func commandWritesRoot(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	for _, arg := range args[1:] {
+		switch arg {
+		case "-h", "-help", "--help":
+			return false
+		}
+	}
+	sub := ""
+	if len(args) > 1 {
+		sub = args[1]
+	}
+	switch args[0] {
+	case "init", "import", "apply", "sync", "push":
+		return true
+	case "doctor":
+		for _, arg := range args[1:] {
+			if arg == "--fix" || arg == "-fix" {
+				return true
+			}
+		}
+		return false
+	case "repo":
+		return sub == "get" || sub == "rm"
+	case "pr":
+		return sub == "create"
+	case "manifest", "man", "m":
+		switch sub {
+		case "add", "rm", "mv", "gc", "undo", "archive", "restore":
+			return true
+		case "preset", "pre", "p":
+			if len(args) > 2 {
+				return args[2] == "add" || args[2] == "rm"
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// commandLine renders the invoked command for lock owners and manifest history.
+func commandLine(args []string) string {
+	return "gion " + strings.Join(args, " ")
+}
+
+// acquireRootLock takes the root lock for mutating commands. A root that does not exist
+// yet (before `gion init`) has nothing to protect, so no lock is taken.
+func acquireRootLock(ctx context.Context, rootDir string, timeout time.Duration, args []string) (*rootlock.Lock, error) {
+	if exists, err := paths.DirExists(rootDir); err != nil || !exists {
+		return nil, err
+	}
+	return rootlock.Acquire(ctx, rootDir, rootlock.Options{
+		Timeout: timeout,
+		Command: commandLine(args),
+		OnWait: func(owner rootlock.Owner) {
+			fmt.Fprintf(os.Stderr, "waiting for lock held by %s (up to %s)...\n", owner, timeout)
+		},
+	})
+}
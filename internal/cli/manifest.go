@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/tasuku43/gion/internal/app/manifestls"
@@ -26,10 +27,20 @@ func runManifest(ctx context.Context, rootDir string, args []string, noPrompt bo
 		return runManifestAdd(ctx, rootDir, args[1:], noPrompt)
 	case "rm":
 		return runManifestRm(ctx, rootDir, args[1:], noPrompt)
+	case "mv":
+		return runManifestMv(ctx, rootDir, args[1:], noPrompt)
+	case "archive":
+		return runManifestArchive(ctx, rootDir, args[1:], noPrompt)
+	case "restore":
+		return runManifestRestore(ctx, rootDir, args[1:])
 	case "gc":
 		return runManifestGc(ctx, rootDir, args[1:], noPrompt)
 	case "validate":
 		return runManifestValidate(ctx, rootDir, args[1:])
+	case "history":
+		return runManifestHistory(ctx, rootDir, args[1:])
+	case "undo":
+		return runManifestUndo(ctx, rootDir, args[1:], noPrompt)
 	case "preset", "pre", "p":
 		return runManifestPreset(ctx, rootDir, args[1:], noPrompt)
 	default:
@@ -42,9 +53,13 @@ func runManifestLs(ctx context.Context, rootDir string, args []string) error {
 	lsFlags.SetOutput(os.Stdout)
 	var helpFlag bool
 	var noPrompt bool
+	var remote bool
+	var refresh bool
 	lsFlags.BoolVar(&helpFlag, "help", false, "show help")
 	lsFlags.BoolVar(&helpFlag, "h", false, "show help")
 	lsFlags.BoolVar(&noPrompt, "no-prompt", false, "disable interactive prompt (no effect)")
+	lsFlags.BoolVar(&remote, "remote", false, "show PR/issue status for workspaces created from a PR or issue")
+	lsFlags.BoolVar(&refresh, "refresh", false, "ignore cached remote status (with --remote)")
 	lsFlags.Usage = func() {
 		printManifestLsHelp(os.Stdout)
 	}
@@ -60,7 +75,10 @@ func runManifestLs(ctx context.Context, rootDir string, args []string) error {
 		return nil
 	}
 	if lsFlags.NArg() != 0 {
-		return fmt.Errorf("usage: gion manifest ls [--no-prompt]")
+		return fmt.Errorf("usage: gion manifest ls [--remote [--refresh]] [--no-prompt]")
+	}
+	if refresh && !remote {
+		return fmt.Errorf("--refresh requires --remote")
 	}
 
 	theme := ui.DefaultTheme()
@@ -76,6 +94,18 @@ func runManifestLs(ctx context.Context, rootDir string, args []string) error {
 	for _, warn := range result.Warnings {
 		warningLines = append(warningLines, compactError(warn))
 	}
+	var statuses map[string]remoteStatus
+	if remote {
+		var sourceURLs []string
+		for _, entry := range result.ManifestEntries {
+			sourceURLs = append(sourceURLs, entry.SourceURL)
+		}
+		var remoteWarnings []error
+		statuses, remoteWarnings = fetchRemoteStatuses(ctx, rootDir, sourceURLs, refresh, time.Now())
+		for _, warn := range remoteWarnings {
+			warningLines = append(warningLines, compactError(warn))
+		}
+	}
 	if len(warningLines) > 0 {
 		renderWarningsSection(renderer, "warnings", warningLines, false)
 		renderer.Blank()
@@ -93,15 +123,19 @@ func runManifestLs(ctx context.Context, rootDir string, args []string) error {
 
 	renderer.Section("Result")
 	for _, entry := range result.ManifestEntries {
-		renderer.Bullet(formatManifestLsLine(renderer, entry.WorkspaceID, entry.Drift, entry.Risk, entry.HasWorkspace, entry.Description, false))
+		remoteTag := ""
+		if status, ok := statuses[entry.SourceURL]; ok {
+			remoteTag = formatRemoteStatusTag(renderer, status)
+		}
+		renderer.Bullet(formatManifestLsLine(renderer, entry.WorkspaceID, entry.Drift, entry.Risk, entry.HasWorkspace, remoteTag, entry.Description, false))
 	}
 	for _, entry := range result.ExtraEntries {
-		renderer.Bullet(formatManifestLsLine(renderer, entry.WorkspaceID, entry.Drift, entry.Risk, entry.HasWorkspace, "", true))
+		renderer.Bullet(formatManifestLsLine(renderer, entry.WorkspaceID, entry.Drift, entry.Risk, entry.HasWorkspace, "", "", true))
 	}
 	return nil
 }
 
-func formatManifestLsLine(r *ui.Renderer, workspaceID string, drift manifestls.DriftStatus, riskKind workspace.WorkspaceStateKind, hasWorkspace bool, description string, isExtra bool) string {
+func formatManifestLsLine(r *ui.Renderer, workspaceID string, drift manifestls.DriftStatus, riskKind workspace.WorkspaceStateKind, hasWorkspace bool, remoteTag string, description string, isExtra bool) string {
 	line := strings.TrimSpace(workspaceID)
 	if line == "" {
 		line = "<unknown>"
@@ -117,6 +151,9 @@ func formatManifestLsLine(r *ui.Renderer, workspaceID string, drift manifestls.D
 			line += " " + tag
 		}
 	}
+	if remoteTag != "" {
+		line += " " + remoteTag
+	}
 	desc := strings.TrimSpace(description)
 	if !isExtra && desc != "" {
 		line += " - " + desc
@@ -129,11 +166,11 @@ func formatRiskTag(r *ui.Renderer, kind workspace.WorkspaceStateKind) string {
 		return ""
 	}
 	switch kind {
-	case workspace.WorkspaceStateUnknown:
+	case workspace.WorkspaceStateUnknown, workspace.WorkspaceStateInProgress:
 		return r.ErrorText(fmt.Sprintf("[%s]", kind))
 	case workspace.WorkspaceStateDirty:
 		return r.ErrorText(fmt.Sprintf("[%s]", kind))
-	case workspace.WorkspaceStateUnpushed, workspace.WorkspaceStateDiverged:
+	case workspace.WorkspaceStateUnpushed, workspace.WorkspaceStateDiverged, workspace.WorkspaceStateUnpublished, workspace.WorkspaceStateStashed:
 		return r.WarnText(fmt.Sprintf("[%s]", kind))
 	default:
 		return ""
@@ -168,9 +168,6 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 			if !ok {
 				return nil, fmt.Errorf("selected repo not found")
 			}
-			if strings.ToLower(selected.Provider) != "github" {
-				return nil, fmt.Errorf("issue picker supports GitHub only for now: %s", selected.Host)
-			}
 			provider, err := providerByName(selected.Provider)
 			if err != nil {
 				return nil, err
@@ -673,9 +670,6 @@ func manifestAddIssueURL(ctx context.Context, rootDir, issueURL, branch, baseRef
 	if err != nil {
 		return err
 	}
-	if !strings.EqualFold(strings.TrimSpace(req.Provider), "github") {
-		return fmt.Errorf("unsupported issue provider: %s", req.Provider)
-	}
 	provider, err := providerByName(req.Provider)
 	if err != nil {
 		return err
@@ -753,8 +747,8 @@ func manifestAddIssueSelected(ctx context.Context, rootDir string, repoSpec stri
 	if err != nil {
 		return err
 	}
-	if !isGitHubHost(spec.Host) {
-		return fmt.Errorf("issue picker supports GitHub only for now: %s", spec.Host)
+	if _, ok := reviewProviderForHost(spec.Host); !ok {
+		return fmt.Errorf("no issue provider for host: %s", spec.Host)
 	}
 	host := strings.TrimSpace(spec.Host)
 	owner := strings.TrimSpace(spec.Owner)
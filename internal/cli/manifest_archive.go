@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/archive"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runManifestArchive(ctx context.Context, rootDir string, args []string, globalNoPrompt bool) error {
+	archiveFlags := flag.NewFlagSet("manifest archive", flag.ContinueOnError)
+	var noPromptFlag bool
+	var helpFlag bool
+	archiveFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
+	archiveFlags.BoolVar(&helpFlag, "help", false, "show help")
+	archiveFlags.BoolVar(&helpFlag, "h", false, "show help")
+	archiveFlags.SetOutput(os.Stdout)
+	archiveFlags.Usage = func() {
+		printManifestArchiveHelp(os.Stdout)
+	}
+	positional, err := parseInterspersed(archiveFlags, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printManifestArchiveHelp(os.Stdout)
+		return nil
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: gion manifest archive <WORKSPACE_ID> [--no-prompt]")
+	}
+	workspaceID := positional[0]
+	noPrompt := globalNoPrompt || noPromptFlag
+
+	plan, err := archive.Prepare(ctx, rootDir, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	output.SetStepLogger(renderer)
+	defer output.SetStepLogger(nil)
+
+	renderer.Section("Inputs")
+	renderer.Bullet(fmt.Sprintf("workspace: %s", workspaceID))
+	renderer.Bullet(fmt.Sprintf("archive: %s", archive.Dir(rootDir, workspaceID)))
+	if len(plan.Warnings) > 0 {
+		renderer.Blank()
+		renderer.Section("Info")
+		for _, warn := range plan.Warnings {
+			renderer.BulletWarn(compactError(warn))
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Plan")
+	for _, repoEntry := range plan.Record.Repos {
+		renderer.Bullet(fmt.Sprintf("%s: %s", repoEntry.Alias, formatArchiveRepo(repoEntry)))
+	}
+	for _, repoEntry := range plan.Record.Repos {
+		if repoEntry.IgnoredFiles > 0 {
+			renderer.Bullet(renderer.MutedText(fmt.Sprintf("%s: %d ignored file(s) are not archived and are deleted with the worktree", repoEntry.Alias, repoEntry.IgnoredFiles)))
+		}
+		if repoEntry.Stashes > 0 {
+			renderer.Bullet(renderer.MutedText(fmt.Sprintf("%s: %d stash entry(ies) are not archived; they stay in the repo store", repoEntry.Alias, repoEntry.Stashes)))
+		}
+	}
+	renderer.Bullet(fmt.Sprintf("remove workspace %s (after the archive is written)", workspaceID))
+	if plan.InManifest {
+		renderer.Bullet(fmt.Sprintf("remove %s from %s", workspaceID, manifest.FileName))
+	}
+
+	if !noPrompt {
+		renderer.Blank()
+		confirm, err := ui.PromptConfirmInlinePlan(fmt.Sprintf("Archive workspace %s? (default: No)", workspaceID), theme, useColor)
+		if err != nil {
+			if errors.Is(err, ui.ErrPromptCanceled) {
+				return nil
+			}
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Steps")
+	record, err := archive.Archive(ctx, rootDir, plan, archive.Options{Step: output.Step})
+	if err != nil {
+		return err
+	}
+
+	renderer.Blank()
+	renderer.Section("Result")
+	renderer.BulletSuccess(fmt.Sprintf("archived %s (%d repo(s))", record.WorkspaceID, len(record.Repos)))
+	renderer.Bullet(fmt.Sprintf("archive: %s", archive.Dir(rootDir, workspaceID)))
+	renderer.Blank()
+	renderer.Section("Suggestion")
+	renderer.Bullet(fmt.Sprintf("gion manifest restore %s", workspaceID))
+	return nil
+}
+
+func runManifestRestore(ctx context.Context, rootDir string, args []string) error {
+	restoreFlags := flag.NewFlagSet("manifest restore", flag.ContinueOnError)
+	var helpFlag bool
+	restoreFlags.BoolVar(&helpFlag, "help", false, "show help")
+	restoreFlags.BoolVar(&helpFlag, "h", false, "show help")
+	restoreFlags.SetOutput(os.Stdout)
+	restoreFlags.Usage = func() {
+		printManifestRestoreHelp(os.Stdout)
+	}
+	positional, err := parseInterspersed(restoreFlags, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printManifestRestoreHelp(os.Stdout)
+		return nil
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: gion manifest restore <WORKSPACE_ID>")
+	}
+	workspaceID := positional[0]
+
+	record, err := archive.Load(rootDir, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	output.SetStepLogger(renderer)
+	defer output.SetStepLogger(nil)
+
+	renderer.Section("Inputs")
+	renderer.Bullet(fmt.Sprintf("workspace: %s", workspaceID))
+	renderer.Bullet(fmt.Sprintf("archive: %s (archived %s)", archive.Dir(rootDir, workspaceID), record.ArchivedAt.Local().Format("2006-01-02 15:04")))
+
+	renderer.Blank()
+	renderer.Section("Plan")
+	for _, repoEntry := range record.Repos {
+		renderer.Bullet(fmt.Sprintf("%s: %s", repoEntry.Alias, formatArchiveRepo(repoEntry)))
+	}
+
+	renderer.Blank()
+	renderer.Section("Steps")
+	if _, err := archive.Restore(ctx, rootDir, workspaceID, archive.Options{Step: output.Step}); err != nil {
+		return err
+	}
+
+	renderer.Blank()
+	renderer.Section("Result")
+	renderer.BulletSuccess(fmt.Sprintf("restored %s (%d repo(s))", workspaceID, len(record.Repos)))
+	renderer.Bullet(fmt.Sprintf("%s updated; archive removed", manifest.FileName))
+	return nil
+}
+
+func formatArchiveRepo(repoEntry archive.Repo) string {
+	return fmt.Sprintf("branch %s, %d local commit(s), %d uncommitted change(s)", repoEntry.Branch, repoEntry.Commits, repoEntry.ChangedFiles)
+}
@@ -24,6 +24,28 @@ type manifestGcCandidate struct {
 	Reason      string
 }
 
+// manifestGcMergeDetection selects how a branch is proven merged into its target.
+type manifestGcMergeDetection string
+
+const (
+	// manifestGcMergeStrict accepts only branches whose head is an ancestor of the target.
+	manifestGcMergeStrict manifestGcMergeDetection = "strict"
+	// manifestGcMergeSquash also accepts branches whose commits (rebase merge) or whole
+	// diff (squash merge) have an equivalent patch on the target.
+	manifestGcMergeSquash manifestGcMergeDetection = "squash"
+)
+
+func parseManifestGcMergeDetection(value string) (manifestGcMergeDetection, error) {
+	switch manifestGcMergeDetection(strings.TrimSpace(value)) {
+	case manifestGcMergeStrict:
+		return manifestGcMergeStrict, nil
+	case manifestGcMergeSquash:
+		return manifestGcMergeSquash, nil
+	default:
+		return "", fmt.Errorf("unsupported merge detection: %s (use strict or squash)", value)
+	}
+}
+
 type manifestGcFetchTarget struct {
 	Remote string
 	Branch string
@@ -41,8 +63,10 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 	var noFetch bool
 	var noPromptFlag bool
 	var helpFlag bool
+	var mergeDetectionFlag string
 	gcFlags.BoolVar(&noApply, "no-apply", false, "do not run gion apply")
 	gcFlags.BoolVar(&noFetch, "no-fetch", false, "disable git fetch for repo stores")
+	gcFlags.StringVar(&mergeDetectionFlag, "merge-detection", string(manifestGcMergeStrict), "merge detection mode (strict|squash)")
 	gcFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
 	gcFlags.BoolVar(&helpFlag, "help", false, "show help")
 	gcFlags.BoolVar(&helpFlag, "h", false, "show help")
@@ -61,7 +85,11 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 		return nil
 	}
 	if gcFlags.NArg() != 0 {
-		return fmt.Errorf("usage: gion manifest gc [--no-apply] [--no-fetch] [--no-prompt]")
+		return fmt.Errorf("usage: gion manifest gc [--merge-detection=strict|squash] [--no-apply] [--no-fetch] [--no-prompt]")
+	}
+	mergeDetection, err := parseManifestGcMergeDetection(mergeDetectionFlag)
+	if err != nil {
+		return err
 	}
 
 	noPrompt := globalNoPrompt || noPromptFlag
@@ -151,12 +179,13 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 			skipped++
 			continue
 		}
-		if state.Kind != workspace.WorkspaceStateClean {
+		if !manifestGcStateAllowed(state, ws, mergeDetection) {
 			skipped++
 			continue
 		}
 
 		var repoTargets []string
+		var reasons []string
 		allMerged := true
 		for _, repoEntry := range ws.Repos {
 			if err := fetchErrors[repoEntry.RepoKey]; err != nil {
@@ -178,6 +207,10 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 			repoTargets = append(repoTargets, fmt.Sprintf("%s=%s", repoEntryLabel(repoEntry), target))
 
 			merged, err := strictMergedIntoTarget(ctx, rootDir, repoEntry, target)
+			reason := "merged"
+			if err == nil && !merged && mergeDetection == manifestGcMergeSquash {
+				reason, merged, err = patchMergedIntoTarget(ctx, rootDir, repoEntry, target)
+			}
 			if err != nil {
 				warnings = append(warnings, fmt.Errorf("%s: %s: merged check failed: %w", id, repoEntryLabel(repoEntry), err))
 				allMerged = false
@@ -187,6 +220,7 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 				allMerged = false
 				break
 			}
+			reasons = append(reasons, reason)
 		}
 
 		if !allMerged {
@@ -196,7 +230,7 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 		candidates = append(candidates, manifestGcCandidate{
 			WorkspaceID: id,
 			Targets:     repoTargets,
-			Reason:      "merged",
+			Reason:      strings.Join(uniqueNonEmptyStrings(reasons), ","),
 		})
 	}
 
@@ -294,6 +328,36 @@ func renderManifestGcWarnings(r *ui.Renderer, warningLines []string) {
 	renderTreeLines(r, warningLines, treeLineWarn)
 }
 
+// manifestGcStateAllowed reports whether a workspace in state may be collected once its
+// branches are proven merged. Only clean workspaces may under strict detection. Squash and
+// rebase merges leave the branch commits off origin (unpublished, unpushed, or diverged),
+// so squash detection also lets those repos through: the merged check must then prove
+// every branch of ws merged. Uncommitted changes, stashes, and unknown states never pass.
+func manifestGcStateAllowed(state workspace.WorkspaceState, ws manifest.Workspace, mergeDetection manifestGcMergeDetection) bool {
+	if state.Kind == workspace.WorkspaceStateClean {
+		return true
+	}
+	if mergeDetection != manifestGcMergeSquash {
+		return false
+	}
+	checked := make(map[string]bool, len(ws.Repos))
+	for _, repoEntry := range ws.Repos {
+		checked[strings.TrimSpace(repoEntry.Alias)] = true
+	}
+	for _, repoState := range state.Repos {
+		switch repoState.Kind {
+		case workspace.RepoStateClean:
+		case workspace.RepoStateUnpublished, workspace.RepoStateUnpushed, workspace.RepoStateDiverged:
+			if !checked[repoState.Alias] {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func repoEntryLabel(entry manifest.Repo) string {
 	alias := strings.TrimSpace(entry.Alias)
 	if alias != "" {
@@ -491,3 +555,71 @@ func strictMergedIntoTarget(ctx context.Context, rootDir string, entry manifest.
 	}
 	return ok, nil
 }
+
+// patchMergedIntoTarget recognizes branches merged without a merge commit. A rebase merge
+// leaves a patch-equivalent copy of every branch commit on the target; a squash merge
+// leaves one commit whose patch equals the branch's whole diff from the merge base. Any
+// commit without an equivalent means the branch is not provably merged.
+func patchMergedIntoTarget(ctx context.Context, rootDir string, entry manifest.Repo, target string) (string, bool, error) {
+	branch := strings.TrimSpace(entry.Branch)
+	if branch == "" {
+		return "", false, fmt.Errorf("branch is required")
+	}
+	storePath, exists, err := repo.Exists(rootDir, repo.SpecFromKey(entry.RepoKey))
+	if err != nil {
+		return "", false, err
+	}
+	if !exists {
+		return "", false, fmt.Errorf("repo store not found (run: gion repo get %s)", repo.SpecFromKey(entry.RepoKey))
+	}
+
+	headRef := fmt.Sprintf("refs/heads/%s", branch)
+	targetRef := fmt.Sprintf("refs/remotes/%s", strings.TrimSpace(target))
+	headHash, headOK, err := gitcmd.ShowRef(ctx, storePath, headRef)
+	if err != nil {
+		return "", false, err
+	}
+	if !headOK {
+		return "", false, fmt.Errorf("ref not found: %s", headRef)
+	}
+	mergeBase, err := gitcmd.MergeBase(ctx, storePath, headRef, targetRef)
+	if err != nil {
+		return "", false, err
+	}
+	// No commits of its own: a created-only branch, never a candidate.
+	if mergeBase == headHash {
+		return "", false, nil
+	}
+
+	commits, err := gitcmd.Cherry(ctx, storePath, targetRef, headRef)
+	if err != nil {
+		return "", false, err
+	}
+	if len(commits) > 0 && allCherryEquivalent(commits) {
+		return "rebase-merged", true, nil
+	}
+
+	// Squash the branch into one commit on the merge base (the object is unreferenced and
+	// left to git gc) and look for its patch on the target.
+	squashed, err := gitcmd.CommitTree(ctx, storePath, headRef+"^{tree}", mergeBase, "gion squash check")
+	if err != nil {
+		return "", false, err
+	}
+	squashedCommits, err := gitcmd.Cherry(ctx, storePath, targetRef, squashed)
+	if err != nil {
+		return "", false, err
+	}
+	if len(squashedCommits) == 1 && squashedCommits[0].Equivalent {
+		return "squash-merged", true, nil
+	}
+	return "", false, nil
+}
+
+func allCherryEquivalent(entries []gitcmd.CherryEntry) bool {
+	for _, entry := range entries {
+		if !entry.Equivalent {
+			return false
+		}
+	}
+	return true
+}
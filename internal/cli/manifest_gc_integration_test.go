@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestPatchMergedIntoTarget(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+	seedDir := filepath.Join(tmp, "seed")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	store, err := repo.Get(ctx, rootDir, repoSpec)
+	if err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	entry := manifest.Repo{Alias: "repo", RepoKey: "example.com/org/repo.git", Branch: "WS-1"}
+
+	commitFile := func(dir, name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(t, dir, "add", ".")
+		runGit(t, dir, "commit", "-m", name)
+	}
+	check := func(wantReason string, wantMerged bool) {
+		t.Helper()
+		if err := fetchRemoteBranch(ctx, store.StorePath, manifestGcFetchTarget{Remote: "origin", Branch: "main"}); err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+		strict, err := strictMergedIntoTarget(ctx, rootDir, entry, "origin/main")
+		if err != nil {
+			t.Fatalf("strict check: %v", err)
+		}
+		if strict {
+			t.Fatalf("expected strict check to miss a merge without merge commit")
+		}
+		reason, merged, err := patchMergedIntoTarget(ctx, rootDir, entry, "origin/main")
+		if err != nil {
+			t.Fatalf("patch check: %v", err)
+		}
+		if merged != wantMerged || reason != wantReason {
+			t.Fatalf("patchMergedIntoTarget() = (%q, %v), want (%q, %v)", reason, merged, wantReason, wantMerged)
+		}
+	}
+
+	// Created-only branch: nothing to prove merged.
+	check("", false)
+
+	commitFile(worktreePath, "a.txt")
+	commitFile(worktreePath, "b.txt")
+	runGit(t, worktreePath, "push", "origin", "WS-1")
+	check("", false)
+
+	// Rebase merge: both commits are replayed onto a main that moved on.
+	commitFile(seedDir, "main.txt")
+	runGit(t, seedDir, "fetch", "origin", "WS-1")
+	runGit(t, seedDir, "cherry-pick", "FETCH_HEAD~1", "FETCH_HEAD")
+	runGit(t, seedDir, "push", "origin", "main")
+	check("rebase-merged", true)
+
+	// A commit added after the merge is not on main.
+	commitFile(worktreePath, "c.txt")
+	check("", false)
+
+	// Squash merge of a two-commit branch: neither commit has an equivalent on main,
+	// only their combined diff does.
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-2", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-2", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	entry = manifest.Repo{Alias: "repo", RepoKey: "example.com/org/repo.git", Branch: "WS-2"}
+	worktreePath = workspace.WorktreePath(rootDir, "WS-2", "repo")
+	commitFile(worktreePath, "d.txt")
+	commitFile(worktreePath, "e.txt")
+	runGit(t, worktreePath, "push", "origin", "WS-2")
+	check("", false)
+
+	commitFile(seedDir, "main2.txt")
+	runGit(t, seedDir, "fetch", "origin", "WS-2")
+	runGit(t, seedDir, "merge", "--squash", "FETCH_HEAD")
+	runGit(t, seedDir, "commit", "-m", "squash WS-2")
+	runGit(t, seedDir, "push", "origin", "main")
+	check("squash-merged", true)
+}
+
+func TestManifestGcSquashRemovesSquashMergedBranchWithoutUpstream(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+	seedDir := filepath.Join(tmp, "seed")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	entry := manifest.Workspace{
+		Mode:  workspace.MetadataModeRepo,
+		Repos: []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo.git", Branch: "WS-1"}},
+	}
+	if err := manifest.Save(rootDir, manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{"WS-1": entry}}); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	// The branch is never pushed: its commits reach main only as one squash commit, so
+	// the workspace stays unpublished.
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	runGit(t, worktreePath, "branch", "--unset-upstream")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(worktreePath, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(t, worktreePath, "add", ".")
+		runGit(t, worktreePath, "commit", "-m", name)
+	}
+	runGit(t, seedDir, "fetch", worktreePath, "WS-1")
+	runGit(t, seedDir, "merge", "--squash", "FETCH_HEAD")
+	runGit(t, seedDir, "commit", "-m", "squash WS-1")
+	runGit(t, seedDir, "push", "origin", "main")
+
+	hasWorkspace := func() bool {
+		t.Helper()
+		file, err := manifest.Load(rootDir)
+		if err != nil {
+			t.Fatalf("load manifest: %v", err)
+		}
+		_, ok := file.Workspaces["WS-1"]
+		return ok
+	}
+
+	if err := runManifestGc(ctx, rootDir, []string{"--no-apply"}, true); err != nil {
+		t.Fatalf("gc strict: %v", err)
+	}
+	if !hasWorkspace() {
+		t.Fatalf("expected strict detection to keep WS-1")
+	}
+	if err := runManifestGc(ctx, rootDir, []string{"--merge-detection=squash", "--no-apply"}, true); err != nil {
+		t.Fatalf("gc squash: %v", err)
+	}
+	if hasWorkspace() {
+		t.Fatalf("expected squash detection to remove WS-1")
+	}
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runManifestHistory(ctx context.Context, rootDir string, args []string) error {
+	historyFlags := flag.NewFlagSet("manifest history", flag.ContinueOnError)
+	historyFlags.SetOutput(os.Stdout)
+	var helpFlag bool
+	var noPrompt bool
+	historyFlags.BoolVar(&helpFlag, "help", false, "show help")
+	historyFlags.BoolVar(&helpFlag, "h", false, "show help")
+	historyFlags.BoolVar(&noPrompt, "no-prompt", false, "disable interactive prompt (no effect)")
+	historyFlags.Usage = func() {
+		printManifestHistoryHelp(os.Stdout)
+	}
+	if err := historyFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	_ = noPrompt
+	if helpFlag {
+		printManifestHistoryHelp(os.Stdout)
+		return nil
+	}
+	if historyFlags.NArg() != 0 {
+		return fmt.Errorf("usage: gion manifest history [--no-prompt]")
+	}
+
+	revisions, err := manifest.History(rootDir)
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	renderer.Section("Result")
+	if len(revisions) == 0 {
+		renderer.Bullet(fmt.Sprintf("no %s history", manifest.FileName))
+		return nil
+	}
+	for i, revision := range revisions {
+		renderer.Bullet(formatManifestRevision(i+1, revision))
+	}
+	renderer.Blank()
+	renderer.Section("Suggestion")
+	renderer.Bullet("gion manifest undo [N]")
+	return nil
+}
+
+func runManifestUndo(ctx context.Context, rootDir string, args []string, globalNoPrompt bool) error {
+	undoFlags := flag.NewFlagSet("manifest undo", flag.ContinueOnError)
+	var noApply bool
+	var noPromptFlag bool
+	var helpFlag bool
+	undoFlags.BoolVar(&noApply, "no-apply", false, "do not run gion apply")
+	undoFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
+	undoFlags.BoolVar(&helpFlag, "help", false, "show help")
+	undoFlags.BoolVar(&helpFlag, "h", false, "show help")
+	undoFlags.SetOutput(os.Stdout)
+	undoFlags.Usage = func() {
+		printManifestUndoHelp(os.Stdout)
+	}
+	if err := undoFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printManifestUndoHelp(os.Stdout)
+		return nil
+	}
+	if undoFlags.NArg() > 1 {
+		return fmt.Errorf("usage: gion manifest undo [N] [--no-apply] [--no-prompt]")
+	}
+	noPrompt := globalNoPrompt || noPromptFlag
+
+	index := 1
+	if undoFlags.NArg() == 1 {
+		value, err := strconv.Atoi(undoFlags.Arg(0))
+		if err != nil || value < 1 {
+			return fmt.Errorf("invalid revision number: %q (see gion manifest history)", undoFlags.Arg(0))
+		}
+		index = value
+	}
+
+	revisions, err := manifest.History(rootDir)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		return fmt.Errorf("no %s history to undo", manifest.FileName)
+	}
+	if index > len(revisions) {
+		return fmt.Errorf("revision %d not found (history has %d revision(s); see gion manifest history)", index, len(revisions))
+	}
+	revision := revisions[index-1]
+	data, err := manifest.ReadRevision(rootDir, revision)
+	if err != nil {
+		return err
+	}
+	restored, err := manifest.Parse(data)
+	if err != nil {
+		return fmt.Errorf("revision %d: %w", index, err)
+	}
+	originalBytes, err := os.ReadFile(manifest.Path(rootDir))
+	if err != nil {
+		return fmt.Errorf("read %s: %w", manifest.FileName, err)
+	}
+
+	summary := fmt.Sprintf("restored %s to revision %d", manifest.FileName, index)
+	return applyManifestMutation(ctx, rootDir, restored, manifestMutationOptions{
+		NoApply:       noApply,
+		NoPrompt:      noPrompt,
+		OriginalBytes: originalBytes,
+		UpdatedBytes:  data,
+		Hooks: manifestMutationHooks{
+			ShowPrelude: func(r *ui.Renderer) {
+				r.Section("Inputs")
+				r.Bullet(fmt.Sprintf("revision: %s", formatManifestRevision(index, revision)))
+			},
+			RenderNoApply: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(summary)
+				r.Blank()
+				r.Section("Suggestion")
+				r.Bullet("gion apply")
+			},
+			RenderNoChanges: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(summary)
+				r.Bullet("no changes")
+			},
+			RenderInfoBeforeApply: func(r *ui.Renderer, _ manifestplan.Result, _ bool) {
+				r.Section("Info")
+				r.Bullet(fmt.Sprintf("manifest: %s", summary))
+				r.Bullet("apply: reconciling entire root (destructive removals require confirmation)")
+			},
+		},
+	})
+}
+
+func formatManifestRevision(n int, revision manifest.Revision) string {
+	line := fmt.Sprintf("%d: %s", n, revision.ReplacedAt.Local().Format("2006-01-02 15:04:05"))
+	switch {
+	case revision.Command != "":
+		line += fmt.Sprintf(" written by %s", revision.Command)
+	case revision.Edited:
+		line += " edited outside gion"
+	}
+	if revision.ReplacedBy != "" {
+		line += fmt.Sprintf(" (replaced by %s)", revision.ReplacedBy)
+	}
+	return line
+}
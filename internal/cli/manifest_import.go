@@ -4,9 +4,20 @@ import (
 	"context"
 
 	"github.com/tasuku43/gion/internal/app/manifestimport"
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
 )
 
 func rebuildManifest(ctx context.Context, rootDir string) error {
 	_, err := manifestimport.Import(ctx, rootDir)
 	return err
 }
+
+func rebuildManifestTargeted(ctx context.Context, rootDir string, desired manifest.File, targets []manifestplan.Target) error {
+	imported, warnings, err := manifestimport.Build(ctx, rootDir)
+	if err != nil {
+		return err
+	}
+	_, err = manifestimport.Write(rootDir, manifestplan.MergeTargeted(desired, imported, targets), warnings)
+	return err
+}
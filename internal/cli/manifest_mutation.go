@@ -23,11 +23,18 @@ type manifestMutationOptions struct {
 	NoApply       bool
 	NoPrompt      bool
 	OriginalBytes []byte
-	Hooks         manifestMutationHooks
+	// UpdatedBytes, when set, is written to gion.yaml verbatim instead of encoding the
+	// updated manifest (used to restore a history revision).
+	UpdatedBytes []byte
+	Hooks        manifestMutationHooks
 }
 
 func applyManifestMutation(ctx context.Context, rootDir string, updated manifest.File, opts manifestMutationOptions) error {
-	if err := manifest.Save(rootDir, updated); err != nil {
+	if opts.UpdatedBytes != nil {
+		if err := manifest.SaveBytes(rootDir, opts.UpdatedBytes); err != nil {
+			return err
+		}
+	} else if err := manifest.Save(rootDir, updated); err != nil {
 		return err
 	}
 
@@ -77,7 +84,7 @@ func applyManifestMutation(ctx context.Context, rootDir string, updated manifest
 		return err
 	}
 	if res.Canceled || (res.HadChanges && !res.Confirmed) {
-		if err := os.WriteFile(manifest.Path(rootDir), opts.OriginalBytes, 0o600); err != nil {
+		if err := manifest.Revert(rootDir, opts.OriginalBytes); err != nil {
 			return fmt.Errorf("restore %s: %w", manifest.FileName, err)
 		}
 		renderer.Blank()
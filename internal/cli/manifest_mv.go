@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/paths"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runManifestMv(ctx context.Context, rootDir string, args []string, globalNoPrompt bool) error {
+	mvFlags := flag.NewFlagSet("manifest mv", flag.ContinueOnError)
+	var noApply bool
+	var noPromptFlag bool
+	var helpFlag bool
+	mvFlags.BoolVar(&noApply, "no-apply", false, "do not run gion apply")
+	mvFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
+	mvFlags.BoolVar(&helpFlag, "help", false, "show help")
+	mvFlags.BoolVar(&helpFlag, "h", false, "show help")
+	mvFlags.SetOutput(os.Stdout)
+	mvFlags.Usage = func() {
+		printManifestMvHelp(os.Stdout)
+	}
+	positional, err := parseInterspersed(mvFlags, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printManifestMvHelp(os.Stdout)
+		return nil
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: gion manifest mv <OLD_WORKSPACE_ID> <NEW_WORKSPACE_ID> [--no-apply] [--no-prompt]")
+	}
+	fromID, toID := positional[0], positional[1]
+	noPrompt := globalNoPrompt || noPromptFlag
+
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	manifestPath := manifest.Path(rootDir)
+	originalBytes, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", manifest.FileName, err)
+	}
+
+	entry, ok := desired.Workspaces[fromID]
+	if !ok {
+		return fmt.Errorf("workspace not found in %s: %s", manifest.FileName, fromID)
+	}
+	if fromID == toID {
+		return fmt.Errorf("workspace is already named %s", toID)
+	}
+	if err := workspace.ValidateWorkspaceID(ctx, toID); err != nil {
+		return err
+	}
+	if _, ok := desired.Workspaces[toID]; ok {
+		return fmt.Errorf("workspace already exists in %s: %s", manifest.FileName, toID)
+	}
+	exists, err := paths.DirExists(workspace.WorkspaceDir(rootDir, toID))
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("workspace directory already exists: %s", workspace.WorkspaceDir(rootDir, toID))
+	}
+
+	updated := desired
+	updated.Workspaces = map[string]manifest.Workspace{}
+	for id, ws := range desired.Workspaces {
+		updated.Workspaces[id] = ws
+	}
+	delete(updated.Workspaces, fromID)
+	updated.Workspaces[toID] = entry
+
+	summary := fmt.Sprintf("updated %s (renamed %s -> %s)", manifest.FileName, fromID, toID)
+	return applyManifestMutation(ctx, rootDir, updated, manifestMutationOptions{
+		NoApply:       noApply,
+		NoPrompt:      noPrompt,
+		OriginalBytes: originalBytes,
+		Hooks: manifestMutationHooks{
+			ShowPrelude: func(r *ui.Renderer) {
+				r.Section("Inputs")
+				r.Bullet(fmt.Sprintf("workspace: %s -> %s", fromID, toID))
+			},
+			RenderNoApply: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(summary)
+				r.Blank()
+				r.Section("Suggestion")
+				r.Bullet("gion apply")
+			},
+			RenderNoChanges: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(summary)
+				r.Bullet("no changes")
+			},
+			RenderInfoBeforeApply: func(r *ui.Renderer, _ manifestplan.Result, _ bool) {
+				r.Section("Info")
+				r.Bullet(fmt.Sprintf("manifest: %s", summary))
+				r.Bullet("apply: the workspace is moved in place (git worktree move keeps local changes)")
+			},
+		},
+	})
+}
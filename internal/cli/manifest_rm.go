@@ -154,11 +154,11 @@ func formatManifestRmRiskTag(r *ui.Renderer, kind workspace.WorkspaceStateKind)
 		return ""
 	}
 	switch kind {
-	case workspace.WorkspaceStateUnknown:
+	case workspace.WorkspaceStateUnknown, workspace.WorkspaceStateInProgress:
 		return r.ErrorText(fmt.Sprintf("[%s]", kind))
 	case workspace.WorkspaceStateDirty:
 		return r.ErrorText(fmt.Sprintf("[%s]", kind))
-	case workspace.WorkspaceStateDiverged, workspace.WorkspaceStateUnpushed:
+	case workspace.WorkspaceStateDiverged, workspace.WorkspaceStateUnpushed, workspace.WorkspaceStateUnpublished, workspace.WorkspaceStateStashed:
 		return r.WarnText(fmt.Sprintf("[%s]", kind))
 	default:
 		return ""
@@ -215,29 +215,44 @@ func bestEffortWorkspaceRiskKind(ctx context.Context, rootDir, workspaceID strin
 	}
 	hasDirty := false
 	hasUnknown := false
+	hasInProgress := false
 	hasDiverged := false
+	hasUnpublished := false
 	hasUnpushed := false
+	hasStashed := false
 	for _, repo := range state.Repos {
 		switch repo.Kind {
 		case workspace.RepoStateUnknown:
 			hasUnknown = true
+		case workspace.RepoStateInProgress:
+			hasInProgress = true
 		case workspace.RepoStateDirty:
 			hasDirty = true
 		case workspace.RepoStateDiverged:
 			hasDiverged = true
+		case workspace.RepoStateUnpublished:
+			hasUnpublished = true
 		case workspace.RepoStateUnpushed:
 			hasUnpushed = true
+		case workspace.RepoStateStashed:
+			hasStashed = true
 		}
 	}
 	switch {
 	case hasUnknown:
 		return workspace.WorkspaceStateUnknown
+	case hasInProgress:
+		return workspace.WorkspaceStateInProgress
 	case hasDirty:
 		return workspace.WorkspaceStateDirty
 	case hasDiverged:
 		return workspace.WorkspaceStateDiverged
+	case hasUnpublished:
+		return workspace.WorkspaceStateUnpublished
 	case hasUnpushed:
 		return workspace.WorkspaceStateUnpushed
+	case hasStashed:
+		return workspace.WorkspaceStateStashed
 	default:
 		return workspace.WorkspaceStateClean
 	}
@@ -247,12 +262,18 @@ func manifestRmRiskWarning(kind workspace.WorkspaceStateKind) (warning string, s
 	switch kind {
 	case workspace.WorkspaceStateUnknown:
 		return "unknown", true
+	case workspace.WorkspaceStateInProgress:
+		return "in-progress", true
 	case workspace.WorkspaceStateDirty:
 		return "dirty", true
 	case workspace.WorkspaceStateDiverged:
 		return "diverged", false
+	case workspace.WorkspaceStateUnpublished:
+		return "unpublished", false
 	case workspace.WorkspaceStateUnpushed:
 		return "unpushed", false
+	case workspace.WorkspaceStateStashed:
+		return "stashed", false
 	default:
 		return "", false
 	}
@@ -2,38 +2,99 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/mattn/go-isatty"
 	"github.com/tasuku43/gion/internal/app/manifestplan"
 	"github.com/tasuku43/gion/internal/domain/manifest"
 	"github.com/tasuku43/gion/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	planFormatText = "text"
+	planFormatJSON = "json"
+	planFormatYAML = "yaml"
 )
 
 func runPlan(ctx context.Context, rootDir string, args []string) error {
-	if len(args) == 1 && isHelpArg(args[0]) {
+	planFlags := flag.NewFlagSet("plan", flag.ContinueOnError)
+	var format string
+	var outPath string
+	var detailedExitcode bool
+	var helpFlag bool
+	planFlags.StringVar(&format, "format", planFormatText, "output format (text|json|yaml)")
+	planFlags.StringVar(&outPath, "out", "", "write the plan to a file for gion apply <file>")
+	planFlags.BoolVar(&detailedExitcode, "detailed-exitcode", false, "exit 2 when changes are pending, 3 when any is destructive")
+	planFlags.BoolVar(&helpFlag, "help", false, "show help")
+	planFlags.BoolVar(&helpFlag, "h", false, "show help")
+	planFlags.SetOutput(os.Stdout)
+	planFlags.Usage = func() {
+		printPlanHelp(os.Stdout)
+	}
+	if err := planFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
 		printPlanHelp(os.Stdout)
 		return nil
 	}
-	if len(args) != 0 {
-		return fmt.Errorf("usage: gion plan")
+	if planFlags.NArg() != 0 {
+		return fmt.Errorf("usage: gion plan [--format text|json|yaml] [--out <file>] [--detailed-exitcode]")
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case planFormatText, planFormatJSON, planFormatYAML:
+	default:
+		return fmt.Errorf("unsupported --format: %s (want text, json, or yaml)", format)
 	}
 
 	theme := ui.DefaultTheme()
 	useColor := isatty.IsTerminal(os.Stdout.Fd())
 	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
 
+	// Fingerprint before planning: if anything changes while the plan is computed,
+	// the saved plan is rejected at apply time instead of silently applying stale diffs.
+	var fingerprint manifestplan.Fingerprint
+	outPath = strings.TrimSpace(outPath)
+	if outPath != "" {
+		fp, err := manifestplan.ComputeFingerprint(ctx, rootDir)
+		if err != nil {
+			return err
+		}
+		fingerprint = fp
+	}
+
 	result, err := manifestplan.Plan(ctx, rootDir)
 	if err != nil {
 		var vErr *manifest.ValidationError
-		if errors.As(err, &vErr) {
+		if errors.As(err, &vErr) && format == planFormatText {
 			renderManifestValidationResult(renderer, vErr.Result)
 			return err
 		}
 		return err
 	}
+	if outPath != "" {
+		if err := manifestplan.SavePlanFile(outPath, rootDir, result, fingerprint); err != nil {
+			return err
+		}
+	}
+
+	if format != planFormatText {
+		if err := writePlanDocument(os.Stdout, format, manifestplan.Export(ctx, rootDir, result)); err != nil {
+			return err
+		}
+		return planExitError(result, detailedExitcode)
+	}
 
 	var warningLines []string
 	for _, warn := range result.Warnings {
@@ -47,8 +108,61 @@ func runPlan(ctx context.Context, rootDir string, args []string) error {
 	renderer.Section("Plan")
 	if len(result.Changes) == 0 {
 		renderer.Bullet("no changes")
+	} else {
+		renderPlanChanges(ctx, rootDir, renderer, result)
+	}
+	if outPath != "" {
+		renderer.Blank()
+		renderer.Section("Result")
+		renderer.Bullet(fmt.Sprintf("plan saved: %s", outPath))
+		renderSuggestions(renderer, useColor, []string{fmt.Sprintf("gion apply %s", outPath)})
+	}
+	return planExitError(result, detailedExitcode)
+}
+
+// planExitCode classifies the plan for --detailed-exitcode.
+func planExitCode(result manifestplan.Result) int {
+	switch {
+	case len(result.Changes) == 0:
+		return planExitNoChanges
+	case planHasDestructiveChanges(result):
+		return planExitDestructive
+	default:
+		return planExitChanges
+	}
+}
+
+func planExitError(result manifestplan.Result, detailedExitcode bool) error {
+	if !detailedExitcode {
 		return nil
 	}
-	renderPlanChanges(ctx, rootDir, renderer, result)
+	if code := planExitCode(result); code != planExitNoChanges {
+		return &ExitCodeError{Code: code}
+	}
 	return nil
 }
+
+func writePlanDocument(w io.Writer, format string, doc manifestplan.Document) error {
+	switch format {
+	case planFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("encode plan json: %w", err)
+		}
+		return nil
+	case planFormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			_ = enc.Close()
+			return fmt.Errorf("encode plan yaml: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("close plan yaml encoder: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported plan format: %s", format)
+	}
+}
@@ -259,3 +259,115 @@ func TestPlan_WorkspaceRemoveRisk_DirtyAndUnpushed(t *testing.T) {
 		t.Fatalf("expected dirty file to be listed, got:\n%s", got)
 	}
 }
+
+func TestPlan_WorkspaceRemoveRisk_Stashed(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "main", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	runGit(t, worktreePath, "branch", "--set-upstream-to=origin/main")
+	if err := os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("stashed\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, worktreePath, "stash", "push", "-m", "wip")
+
+	if err := manifest.Save(rootDir, manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{}}); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+
+	var out bytes.Buffer
+	renderer := ui.NewRenderer(&out, ui.DefaultTheme(), false)
+	renderPlanChanges(ctx, rootDir, renderer, plan)
+
+	// The worktree itself is clean; only the stash holds local work.
+	got := out.String()
+	if !strings.Contains(got, "risk: stashed (stash=1)") {
+		t.Fatalf("expected stashed risk line, got:\n%s", got)
+	}
+	if kind := bestEffortWorkspaceRiskKind(ctx, rootDir, "WS-1"); kind != workspace.WorkspaceStateStashed {
+		t.Fatalf("expected manifest rm risk %q, got %q", workspace.WorkspaceStateStashed, kind)
+	}
+}
+
+func TestPlan_WorkspaceRemoveRisk_Unpublished(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+
+	// A branch that was never pushed: no upstream, so only the origin branches tell.
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	runGit(t, worktreePath, "branch", "--unset-upstream")
+	if kind := bestEffortWorkspaceRiskKind(ctx, rootDir, "WS-1"); kind != workspace.WorkspaceStateClean {
+		t.Fatalf("expected a branch without local commits to be clean, got %q", kind)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "LOCAL.txt"), []byte("local\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, worktreePath, "add", ".")
+	runGit(t, worktreePath, "commit", "-m", "local commit")
+
+	if err := manifest.Save(rootDir, manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{}}); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+
+	var out bytes.Buffer
+	renderer := ui.NewRenderer(&out, ui.DefaultTheme(), false)
+	renderPlanChanges(ctx, rootDir, renderer, plan)
+
+	got := out.String()
+	if !strings.Contains(got, "risk: unpublished (commits=1 not on origin)") {
+		t.Fatalf("expected unpublished risk line, got:\n%s", got)
+	}
+	if kind := bestEffortWorkspaceRiskKind(ctx, rootDir, "WS-1"); kind != workspace.WorkspaceStateUnpublished {
+		t.Fatalf("expected manifest rm risk %q, got %q", workspace.WorkspaceStateUnpublished, kind)
+	}
+
+	// Pushed without -u: still no upstream, but the commit is on origin.
+	runGit(t, worktreePath, "push", "origin", "WS-1")
+	if kind := bestEffortWorkspaceRiskKind(ctx, rootDir, "WS-1"); kind != workspace.WorkspaceStateClean {
+		t.Fatalf("expected a branch pushed without upstream to be clean, got %q", kind)
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+)
+
+func TestPlanExitCode(t *testing.T) {
+	cases := []struct {
+		name    string
+		changes []manifestplan.WorkspaceChange
+		want    int
+	}{
+		{name: "no changes", want: planExitNoChanges},
+		{
+			name:    "add only",
+			changes: []manifestplan.WorkspaceChange{{Kind: manifestplan.WorkspaceAdd, WorkspaceID: "WS-1"}},
+			want:    planExitChanges,
+		},
+		{
+			name: "branch rename in place",
+			changes: []manifestplan.WorkspaceChange{{Kind: manifestplan.WorkspaceUpdate, WorkspaceID: "WS-1", Repos: []manifestplan.RepoChange{
+				{Kind: manifestplan.RepoUpdate, Alias: "app", FromRepo: "r", ToRepo: "r", FromBranch: "a", ToBranch: "b"},
+			}}},
+			want: planExitChanges,
+		},
+		{
+			name: "remove",
+			changes: []manifestplan.WorkspaceChange{
+				{Kind: manifestplan.WorkspaceAdd, WorkspaceID: "WS-1"},
+				{Kind: manifestplan.WorkspaceRemove, WorkspaceID: "WS-2"},
+			},
+			want: planExitDestructive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := manifestplan.Result{Changes: tc.changes}
+			if got := planExitCode(result); got != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, got)
+			}
+			err := planExitError(result, true)
+			var exitErr *ExitCodeError
+			if tc.want == planExitNoChanges {
+				if err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+			} else if !errors.As(err, &exitErr) || exitErr.Code != tc.want {
+				t.Fatalf("expected exit code %d, got %v", tc.want, err)
+			}
+			if err := planExitError(result, false); err != nil {
+				t.Fatalf("without --detailed-exitcode expected nil, got %v", err)
+			}
+		})
+	}
+}
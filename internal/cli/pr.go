@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/prcreate"
+	"github.com/tasuku43/gion/internal/app/push"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+// prCreateRequest is a PR to open from Head into Base (branch names).
+type prCreateRequest struct {
+	Head  string
+	Base  string
+	Title string
+	Body  string
+}
+
+type createdPR struct {
+	Number int
+	URL    string
+}
+
+// prCreator is implemented by providers that can open PRs/MRs.
+type prCreator interface {
+	CreatePR(ctx context.Context, host, owner, repoName string, req prCreateRequest) (createdPR, error)
+	UpdatePRBody(ctx context.Context, host, owner, repoName string, number int, body string) error
+}
+
+func prCreatorForHost(host string) (prCreator, error) {
+	p, err := providerByName(providerNameForHost(host))
+	if err != nil {
+		return nil, err
+	}
+	creator, ok := p.(prCreator)
+	if !ok {
+		return nil, fmt.Errorf("provider %s cannot create PRs", p.Name())
+	}
+	return creator, nil
+}
+
+func runPR(ctx context.Context, rootDir string, args []string, noPrompt bool) error {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		printPRHelp(os.Stdout)
+		return nil
+	}
+	switch args[0] {
+	case "create":
+		return runPRCreate(ctx, rootDir, args[1:], noPrompt)
+	default:
+		return fmt.Errorf("unknown pr subcommand: %s", args[0])
+	}
+}
+
+func runPRCreate(ctx context.Context, rootDir string, args []string, noPrompt bool) error {
+	createFlags := flag.NewFlagSet("pr create", flag.ContinueOnError)
+	var helpFlag bool
+	createFlags.BoolVar(&helpFlag, "help", false, "show help")
+	createFlags.BoolVar(&helpFlag, "h", false, "show help")
+	createFlags.SetOutput(os.Stdout)
+	createFlags.Usage = func() {
+		printPRCreateHelp(os.Stdout)
+	}
+	positional, err := parseInterspersed(createFlags, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printPRCreateHelp(os.Stdout)
+		return nil
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: gion pr create <WORKSPACE_ID>")
+	}
+	workspaceID := positional[0]
+
+	plan, err := prcreate.Prepare(ctx, rootDir, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	output.SetStepLogger(renderer)
+	defer output.SetStepLogger(nil)
+
+	renderer.Section("Inputs")
+	renderer.Bullet(fmt.Sprintf("workspace: %s", workspaceID))
+	renderer.Bullet(fmt.Sprintf("title: %s", plan.Title))
+	if len(plan.Warnings) > 0 {
+		renderer.Blank()
+		renderer.Section("Info")
+		for _, warn := range plan.Warnings {
+			renderer.BulletWarn(compactError(warn))
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Plan")
+	if len(plan.Repos) == 0 {
+		renderer.Bullet("no repos")
+		return nil
+	}
+	for _, repoEntry := range plan.Repos {
+		if repoEntry.Skip != "" {
+			renderer.Bullet(renderer.MutedText(fmt.Sprintf("%s: skip (%s)", repoEntry.Alias, repoEntry.Skip)))
+			continue
+		}
+		row := fmt.Sprintf("%s: open PR %s -> %s on %s/%s/%s (%d commit(s))", repoEntry.Alias, repoEntry.Branch, repoEntry.Base, repoEntry.Host, repoEntry.Owner, repoEntry.Name, repoEntry.Ahead)
+		switch {
+		case repoEntry.Push.Skip != "":
+		case repoEntry.Push.UpstreamOnly:
+			row += fmt.Sprintf(", set upstream to %s/%s first", push.Remote, repoEntry.Branch)
+		default:
+			row += fmt.Sprintf(", push to %s/%s first", push.Remote, repoEntry.Branch)
+		}
+		renderer.Bullet(row)
+	}
+	creates := plan.Creates()
+	if len(creates) == 0 {
+		renderer.Blank()
+		renderer.Section("Result")
+		renderer.Bullet("no PRs to create")
+		return nil
+	}
+	for _, pr := range plan.Metadata.PullRequests {
+		renderer.Bullet(fmt.Sprintf("%s: link the new PR(s) from %s (its Related PRs section is replaced)", pr.Alias, pr.URL))
+	}
+
+	if !noPrompt {
+		renderer.Blank()
+		confirm, err := ui.PromptConfirmInlinePlan(fmt.Sprintf("Create %d PR(s)? (default: No)", len(creates)), theme, useColor)
+		if err != nil {
+			if errors.Is(err, ui.ErrPromptCanceled) {
+				return nil
+			}
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Steps")
+	pushErrs := map[string]error{}
+	for _, result := range push.Run(ctx, plan.Pushes(), push.Options{Step: output.Step}) {
+		if result.Err != nil {
+			pushErrs[result.Repo.Alias] = result.Err
+		}
+	}
+
+	type opened struct {
+		repo prcreate.Repo
+		pr   createdPR
+	}
+	var created []opened
+	failures := map[string]error{}
+	for _, repoEntry := range creates {
+		if err := pushErrs[repoEntry.Alias]; err != nil {
+			failures[repoEntry.Alias] = err
+			continue
+		}
+		output.Step(fmt.Sprintf("create PR %s %s -> %s", repoEntry.Alias, repoEntry.Branch, repoEntry.Base))
+		pr, err := createWorkspacePR(ctx, repoEntry, plan.Title, plan.Body)
+		if err != nil {
+			failures[repoEntry.Alias] = err
+			continue
+		}
+		created = append(created, opened{repo: repoEntry, pr: pr})
+	}
+
+	// Every PR of the workspace (new and previously recorded) links all the others.
+	meta := plan.Metadata
+	recorded := append([]workspace.PullRequest(nil), meta.PullRequests...)
+	for _, item := range created {
+		meta.SetPullRequest(item.repo.Alias, item.pr.URL)
+	}
+	var links []prcreate.Link
+	for _, pr := range meta.PullRequests {
+		links = append(links, prcreate.Link{Alias: pr.Alias, URL: pr.URL})
+	}
+	linkErrs := map[string]error{}
+	if len(created) > 0 && len(links) > 1 {
+		for _, item := range created {
+			output.Step(fmt.Sprintf("link sibling PRs from %s", item.repo.Alias))
+			creator, err := prCreatorForHost(item.repo.Host)
+			if err == nil {
+				err = creator.UpdatePRBody(ctx, item.repo.Host, item.repo.Owner, item.repo.Name, item.pr.Number, prcreate.BodyWithSiblings(plan.Body, item.repo.Alias, links))
+			}
+			if err != nil {
+				linkErrs[item.repo.Alias] = err
+			}
+		}
+		for _, pr := range recorded {
+			output.Step(fmt.Sprintf("link sibling PRs from %s", pr.Alias))
+			if err := updateRecordedPRBody(ctx, pr.URL, pr.Alias, links); err != nil {
+				linkErrs[pr.Alias] = err
+			}
+		}
+	}
+	var saveErr error
+	if len(created) > 0 {
+		saveErr = workspace.SaveMetadata(workspace.WorkspaceDir(rootDir, workspaceID), meta)
+	}
+
+	renderer.Blank()
+	renderer.Section("Result")
+	for _, item := range created {
+		renderer.BulletSuccess(fmt.Sprintf("%s: %s", item.repo.Alias, item.pr.URL))
+		if err := linkErrs[item.repo.Alias]; err != nil {
+			renderer.BulletWarn(fmt.Sprintf("%s: sibling links not added: %s", item.repo.Alias, compactError(err)))
+		}
+	}
+	if len(created) > 0 && len(links) > 1 {
+		for _, pr := range recorded {
+			if err := linkErrs[pr.Alias]; err != nil {
+				renderer.BulletWarn(fmt.Sprintf("%s: sibling links not added to %s: %s", pr.Alias, pr.URL, compactError(err)))
+				continue
+			}
+			renderer.Bullet(fmt.Sprintf("%s: sibling links added to %s", pr.Alias, pr.URL))
+		}
+	}
+	for _, repoEntry := range creates {
+		if err := failures[repoEntry.Alias]; err != nil {
+			renderer.BulletError(fmt.Sprintf("%s: %s", repoEntry.Alias, compactError(err)))
+		}
+	}
+	for _, repoEntry := range plan.Repos {
+		if repoEntry.Skip != "" {
+			renderer.Bullet(renderer.MutedText(fmt.Sprintf("%s: skipped (%s)", repoEntry.Alias, repoEntry.Skip)))
+		}
+	}
+	if saveErr != nil {
+		return fmt.Errorf("record PR URLs: %w", saveErr)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("PR creation failed in %d of %d repo(s)", len(failures), len(creates))
+	}
+	return nil
+}
+
+func createWorkspacePR(ctx context.Context, repoEntry prcreate.Repo, title, body string) (createdPR, error) {
+	creator, err := prCreatorForHost(repoEntry.Host)
+	if err != nil {
+		return createdPR{}, err
+	}
+	pr, err := creator.CreatePR(ctx, repoEntry.Host, repoEntry.Owner, repoEntry.Name, prCreateRequest{
+		Head:  repoEntry.Branch,
+		Base:  repoEntry.Base,
+		Title: title,
+		Body:  body,
+	})
+	if err != nil {
+		return createdPR{}, err
+	}
+	if pr.URL == "" {
+		return createdPR{}, fmt.Errorf("provider returned no PR URL")
+	}
+	return pr, nil
+}
+
+// updateRecordedPRBody refreshes the "Related PRs:" section of a PR recorded by an earlier
+// run, located by its URL. The current body is fetched first, so edits made on the
+// provider since then are kept.
+func updateRecordedPRBody(ctx context.Context, rawURL, alias string, links []prcreate.Link) error {
+	req, err := parsePRURL(rawURL)
+	if err != nil {
+		return err
+	}
+	p, err := providerByName(providerNameForHost(req.Host))
+	if err != nil {
+		return err
+	}
+	creator, ok := p.(prCreator)
+	if !ok {
+		return fmt.Errorf("provider %s cannot create PRs", p.Name())
+	}
+	current, err := p.FetchPR(ctx, req.Host, req.Owner, req.Repo, req.Number)
+	if err != nil {
+		return err
+	}
+	return creator.UpdatePRBody(ctx, req.Host, req.Owner, req.Repo, req.Number, prcreate.BodyWithSiblings(current.Body, alias, links))
+}
@@ -20,24 +20,103 @@ func (githubProvider) Name() string {
 	return "github"
 }
 
+// The githubProvider methods use the REST API when a token is configured (or gh is not
+// installed) and fall back to the gh CLI otherwise; see githubAPIForHost.
 func (githubProvider) FetchIssues(ctx context.Context, host, owner, repoName string) ([]issueSummary, error) {
+	api, ok, err := githubAPIForHost(host)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return api.FetchIssues(ctx, owner, repoName)
+	}
 	return fetchGitHubIssues(ctx, host, owner, repoName)
 }
 
 func (githubProvider) FetchIssue(ctx context.Context, host, owner, repoName string, number int) (issueSummary, error) {
+	api, ok, err := githubAPIForHost(host)
+	if err != nil {
+		return issueSummary{}, err
+	}
+	if ok {
+		return api.FetchIssue(ctx, owner, repoName, number)
+	}
 	return fetchGitHubIssue(ctx, host, owner, repoName, number)
 }
 
 func (githubProvider) FetchPRs(ctx context.Context, host, owner, repoName string) ([]prSummary, error) {
+	api, ok, err := githubAPIForHost(host)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return api.FetchPRs(ctx, owner, repoName)
+	}
 	return fetchGitHubPRs(ctx, host, owner, repoName)
 }
 
 func (githubProvider) FetchPR(ctx context.Context, host, owner, repoName string, number int) (prSummary, error) {
+	api, ok, err := githubAPIForHost(host)
+	if err != nil {
+		return prSummary{}, err
+	}
+	if ok {
+		return api.FetchPR(ctx, owner, repoName, number)
+	}
 	return fetchGitHubPR(ctx, host, owner, repoName, number)
 }
 
+func (githubProvider) FetchPRStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	api, ok, err := githubAPIForHost(host)
+	if err != nil {
+		return remoteStatus{}, err
+	}
+	if ok {
+		return api.FetchPRStatus(ctx, owner, repoName, number)
+	}
+	return fetchGitHubPRStatus(ctx, host, owner, repoName, number)
+}
+
+func (githubProvider) FetchIssueStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error) {
+	api, ok, err := githubAPIForHost(host)
+	if err != nil {
+		return remoteStatus{}, err
+	}
+	if ok {
+		return api.FetchIssueStatus(ctx, owner, repoName, number)
+	}
+	return fetchGitHubIssueStatus(ctx, host, owner, repoName, number)
+}
+
+func (githubProvider) CreatePR(ctx context.Context, host, owner, repoName string, req prCreateRequest) (createdPR, error) {
+	api, ok, err := githubAPIForHost(host)
+	if err != nil {
+		return createdPR{}, err
+	}
+	if ok {
+		return api.CreatePR(ctx, owner, repoName, req)
+	}
+	return createGitHubPR(ctx, host, owner, repoName, req)
+}
+
+func (githubProvider) UpdatePRBody(ctx context.Context, host, owner, repoName string, number int, body string) error {
+	api, ok, err := githubAPIForHost(host)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return api.UpdatePRBody(ctx, owner, repoName, number, body)
+	}
+	return updateGitHubPRBody(ctx, host, owner, repoName, number, body)
+}
+
 var providers = map[string]provider{
-	"github": githubProvider{},
+	"github":           githubProvider{},
+	"gitlab":           gitlabProvider{},
+	"gitea":            giteaProvider{name: "gitea"},
+	"forgejo":          giteaProvider{name: "forgejo"},
+	"bitbucket":        bitbucketCloudProvider{},
+	"bitbucket-server": bitbucketServerProvider{},
 }
 
 func providerByName(name string) (provider, error) {
@@ -52,13 +131,62 @@ func providerByName(name string) (provider, error) {
 	return p, nil
 }
 
+// wellKnownProviderHosts maps public hosting services to their provider.
+var wellKnownProviderHosts = map[string]string{
+	"github.com":    "github",
+	"gitlab.com":    "gitlab",
+	"bitbucket.org": "bitbucket",
+	"gitea.com":     "gitea",
+	"codeberg.org":  "forgejo",
+}
+
+// providerNameForHost maps host to a provider name, or "" when it is not recognized.
+// The host's provider in hosts.yaml wins, then the hosts named by GION_GITLAB_URL,
+// GION_GITEA_HOST, GION_FORGEJO_HOST and GION_BITBUCKET_SERVER_HOST, then well-known hosts;
+// other hosts fall back to hints in the host name (github.example.com, gitea.example.com).
+// Bitbucket hosts other than bitbucket.org are Bitbucket Server/Data Center.
 func providerNameForHost(host string) string {
-	lower := strings.ToLower(strings.TrimSpace(host))
-	if strings.Contains(lower, "gitlab") {
+	if entry, err := loadHostConfig(host); err == nil {
+		if name := strings.ToLower(strings.TrimSpace(entry.Provider)); name != "" {
+			return name
+		}
+	}
+	if isConfiguredGitLabHost(host) {
 		return "gitlab"
 	}
-	if strings.Contains(lower, "bitbucket") {
-		return "bitbucket"
+	for _, configured := range []struct{ key, name string }{
+		{giteaHostEnv, "gitea"},
+		{forgejoHostEnv, "forgejo"},
+		{bitbucketServerHostEnv, "bitbucket-server"},
+	} {
+		if configuredHost := envHost(configured.key); configuredHost != "" && strings.EqualFold(configuredHost, strings.TrimSpace(host)) {
+			return configured.name
+		}
 	}
-	return "github"
+	lower := strings.ToLower(strings.TrimSpace(host))
+	if name, ok := wellKnownProviderHosts[lower]; ok {
+		return name
+	}
+	for _, hint := range []struct{ substr, name string }{
+		{"github", "github"},
+		{"gitlab", "gitlab"},
+		{"bitbucket", "bitbucket-server"},
+		{"forgejo", "forgejo"},
+		{"gitea", "gitea"},
+	} {
+		if strings.Contains(lower, hint.substr) {
+			return hint.name
+		}
+	}
+	return ""
+}
+
+// reviewProviderForHost returns the provider that serves review/issue workspaces for
+// host. Hosts without a registered provider are not offered.
+func reviewProviderForHost(host string) (string, bool) {
+	name := providerNameForHost(host)
+	if _, ok := providers[name]; !ok {
+		return "", false
+	}
+	return name, true
 }
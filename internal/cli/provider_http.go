@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/gion/internal/infra/debuglog"
+)
+
+var providerHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// providerGet sends a GET request to a provider API and returns the response with its
+// body read. name prefixes errors ("gitea api failed: ...").
+func providerGet(ctx context.Context, name, endpoint string, header map[string]string) (*http.Response, []byte, error) {
+	return providerDo(ctx, name, http.MethodGet, endpoint, header, nil)
+}
+
+// providerDo is providerGet for any method; a non-nil payload is sent as a JSON body.
+func providerDo(ctx context.Context, name, method, endpoint string, header map[string]string, payload any) (*http.Response, []byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s api request: %w", name, err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s api request: %w", name, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", "gion")
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	trace := ""
+	if debuglog.Enabled() {
+		trace = debuglog.NewTrace("http")
+		debuglog.LogCommand(trace, method+" "+endpoint)
+	}
+	resp, err := providerHTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s api failed: %w", name, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if debuglog.Enabled() {
+		debuglog.LogStdoutLines(trace, string(body))
+		debuglog.LogExit(trace, resp.StatusCode)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s api failed: %w", name, err)
+	}
+	return resp, body, nil
+}
+
+// providerDoJSON is providerDo for endpoints that answer 2xx with a JSON document. out may
+// be nil when the response is not needed.
+func providerDoJSON(ctx context.Context, name, method, endpoint string, header map[string]string, payload, out any) (http.Header, error) {
+	resp, body, err := providerDo(ctx, name, method, endpoint, header, payload)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s api failed: %s: %s", name, resp.Status, providerErrorMessage(body))
+	}
+	if out == nil {
+		return resp.Header, nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return nil, fmt.Errorf("parse %s api response: %w", name, err)
+	}
+	return resp.Header, nil
+}
+
+// providerErrorMessage extracts the message of an API error response. It understands
+// {"message": ...} (GitHub, GitLab, Gitea), {"error": "..."} and {"error": {"message": ...}}
+// (Bitbucket Cloud), and {"errors": [{"message": ...}]} (Bitbucket Server).
+func providerErrorMessage(body []byte) string {
+	var payload struct {
+		Message any             `json:"message"`
+		Error   json.RawMessage `json:"error"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Message != nil {
+			return fmt.Sprint(payload.Message)
+		}
+		if len(payload.Error) > 0 {
+			var text string
+			if err := json.Unmarshal(payload.Error, &text); err == nil && text != "" {
+				return text
+			}
+			var nested struct {
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(payload.Error, &nested); err == nil && nested.Message != "" {
+				return nested.Message
+			}
+		}
+		if len(payload.Errors) > 0 && payload.Errors[0].Message != "" {
+			return payload.Errors[0].Message
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// linkNextPage returns the rel="next" URL of a Link header (GitHub, Gitea).
+func linkNextPage(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		target := strings.Trim(strings.TrimSpace(segments[0]), "<>")
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return target
+			}
+		}
+	}
+	return ""
+}
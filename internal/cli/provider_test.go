@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setupHostsFile(t *testing.T, content string) {
+	t.Helper()
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	if err := os.MkdirAll(filepath.Join(configDir, "gion"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "gion", hostsFileName), []byte(content), 0o600); err != nil {
+		t.Fatalf("write hosts file: %v", err)
+	}
+}
+
+func TestProviderNameForHost(t *testing.T) {
+	setupHostsFile(t, "git.example.com:\n  provider: gitea\ncode.example.com:\n  provider: Forgejo\n")
+	t.Setenv(gitlabURLEnv, "https://scm.example.com")
+	t.Setenv(giteaHostEnv, "tea.internal")
+	t.Setenv(forgejoHostEnv, "forge.internal")
+	t.Setenv(bitbucketServerHostEnv, "stash.internal")
+
+	cases := map[string]string{
+		"github.com":            "github",
+		"gitlab.com":            "gitlab",
+		"bitbucket.org":         "bitbucket",
+		"codeberg.org":          "forgejo",
+		"git.example.com":       "gitea",
+		"code.example.com":      "forgejo",
+		"scm.example.com":       "gitlab",
+		"github.example.com":    "github",
+		"bitbucket.example.com": "bitbucket-server",
+		"gitea.example.com":     "gitea",
+		"tea.internal":          "gitea",
+		"forge.internal":        "forgejo",
+		"stash.internal":        "bitbucket-server",
+		"example.com":           "",
+	}
+	for host, want := range cases {
+		if got := providerNameForHost(host); got != want {
+			t.Errorf("providerNameForHost(%q) = %q, want %q", host, got, want)
+		}
+	}
+	if _, ok := reviewProviderForHost("example.com"); ok {
+		t.Fatalf("expected unknown host to have no review provider")
+	}
+}
+
+func TestHostTokenScopesEnvTokens(t *testing.T) {
+	setupHostsFile(t, "git.example.com:\n  token: file-token\n")
+	t.Setenv(gitlabTokenEnv, "env-token")
+	t.Setenv(gitlabURLEnv, "https://scm.example.com/gitlab")
+	gitlab := func(host string) string {
+		entry, err := loadHostConfig(host)
+		if err != nil {
+			t.Fatalf("load hosts: %v", err)
+		}
+		return hostToken(host, entry, envToken{key: gitlabTokenEnv, hosts: []string{"gitlab.com", configuredGitLabHost()}})
+	}
+
+	cases := map[string]string{
+		"gitlab.com":              "env-token",
+		"scm.example.com":         "env-token",
+		"gitlab.attacker.example": "",
+		"git.example.com":         "file-token",
+	}
+	for host, want := range cases {
+		if got := gitlab(host); got != want {
+			t.Errorf("token for %q = %q, want %q", host, got, want)
+		}
+	}
+
+	setupHostsFile(t, "gitlab.com:\n  token: file-token\n")
+	if got := gitlab("gitlab.com"); got != "file-token" {
+		t.Fatalf("expected the hosts.yaml token to win over the environment, got %q", got)
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/push"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runPush(ctx context.Context, rootDir string, args []string, noPrompt bool) error {
+	pushFlags := flag.NewFlagSet("push", flag.ContinueOnError)
+	var helpFlag bool
+	pushFlags.BoolVar(&helpFlag, "help", false, "show help")
+	pushFlags.BoolVar(&helpFlag, "h", false, "show help")
+	pushFlags.SetOutput(os.Stdout)
+	pushFlags.Usage = func() {
+		printPushHelp(os.Stdout)
+	}
+	positional, err := parseInterspersed(pushFlags, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printPushHelp(os.Stdout)
+		return nil
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: gion push <WORKSPACE_ID>")
+	}
+	workspaceID := positional[0]
+
+	plan, err := push.Prepare(ctx, rootDir, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	output.SetStepLogger(renderer)
+	defer output.SetStepLogger(nil)
+
+	renderer.Section("Inputs")
+	renderer.Bullet(fmt.Sprintf("workspace: %s", workspaceID))
+	renderer.Bullet(fmt.Sprintf("remote: %s", push.Remote))
+	if len(plan.Warnings) > 0 {
+		renderer.Blank()
+		renderer.Section("Info")
+		for _, warn := range plan.Warnings {
+			renderer.BulletWarn(compactError(warn))
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Plan")
+	if len(plan.Repos) == 0 {
+		renderer.Bullet("no repos")
+		return nil
+	}
+	for _, repoEntry := range plan.Repos {
+		if repoEntry.Skip != "" {
+			renderer.Bullet(renderer.MutedText(fmt.Sprintf("%s: skip (%s)", repoEntry.Alias, repoEntry.Skip)))
+			continue
+		}
+		if repoEntry.UpstreamOnly {
+			renderer.Bullet(fmt.Sprintf("%s: set upstream of %s to %s/%s (nothing to push)", repoEntry.Alias, repoEntry.Branch, push.Remote, repoEntry.Branch))
+			continue
+		}
+		row := fmt.Sprintf("%s: push %s to %s/%s (%d commit(s))", repoEntry.Alias, repoEntry.Branch, push.Remote, repoEntry.Branch, repoEntry.Commits)
+		if repoEntry.SetUpstream {
+			row += ", set upstream"
+		}
+		renderer.Bullet(row)
+	}
+	pushes := plan.Pushes()
+	if len(pushes) == 0 {
+		renderer.Blank()
+		renderer.Section("Result")
+		renderer.Bullet("nothing to push")
+		return nil
+	}
+
+	if !noPrompt {
+		renderer.Blank()
+		confirm, err := ui.PromptConfirmInlinePlan(fmt.Sprintf("Push %d repo(s)? (default: No)", len(pushes)), theme, useColor)
+		if err != nil {
+			if errors.Is(err, ui.ErrPromptCanceled) {
+				return nil
+			}
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Steps")
+	results := push.Run(ctx, plan, push.Options{Step: output.Step})
+
+	renderer.Blank()
+	renderer.Section("Result")
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			renderer.BulletError(fmt.Sprintf("%s: %s", result.Repo.Alias, compactError(result.Err)))
+			continue
+		}
+		if result.Repo.UpstreamOnly {
+			renderer.BulletSuccess(fmt.Sprintf("%s: upstream set to %s/%s", result.Repo.Alias, push.Remote, result.Repo.Branch))
+			continue
+		}
+		row := fmt.Sprintf("%s: pushed %d commit(s) to %s/%s", result.Repo.Alias, result.Repo.Commits, push.Remote, result.Repo.Branch)
+		if result.Repo.SetUpstream {
+			row += " (upstream set)"
+		}
+		renderer.BulletSuccess(row)
+	}
+	for _, repoEntry := range plan.Repos {
+		if repoEntry.Skip != "" {
+			renderer.Bullet(renderer.MutedText(fmt.Sprintf("%s: skipped (%s)", repoEntry.Alias, repoEntry.Skip)))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("push failed in %d of %d repo(s)", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasuku43/gion/internal/infra/paths"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+const (
+	remoteStatusCacheFileName = "remote-status.json"
+	remoteStatusCacheVersion  = 1
+	// remoteStatusCacheTTL is how long a fetched status is reused by `manifest ls --remote`.
+	remoteStatusCacheTTL = 5 * time.Minute
+	remoteStatusWorkers  = 4
+)
+
+const (
+	remoteKindPR    = "pr"
+	remoteKindIssue = "issue"
+
+	remoteStateOpen   = "open"
+	remoteStateDraft  = "draft"
+	remoteStateMerged = "merged"
+	remoteStateClosed = "closed"
+
+	remoteReviewApproved         = "approved"
+	remoteReviewChangesRequested = "changes-requested"
+	remoteReviewRequired         = "review-required"
+
+	remoteChecksPassing = "passing"
+	remoteChecksFailing = "failing"
+	remoteChecksPending = "pending"
+)
+
+// remoteStatus is the lifecycle of the PR/issue a workspace was created from. Review and
+// Checks are empty when the provider does not report them (or for issues).
+type remoteStatus struct {
+	Kind   string `json:"kind"`
+	Number int    `json:"number"`
+	State  string `json:"state"`
+	Review string `json:"review,omitempty"`
+	Checks string `json:"checks,omitempty"`
+}
+
+// remoteStatusProvider is implemented by providers that can report PR/issue lifecycle.
+type remoteStatusProvider interface {
+	FetchPRStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error)
+	FetchIssueStatus(ctx context.Context, host, owner, repoName string, number int) (remoteStatus, error)
+}
+
+type remoteStatusCache struct {
+	Version int                               `json:"version"`
+	Entries map[string]remoteStatusCacheEntry `json:"entries"`
+}
+
+type remoteStatusCacheEntry struct {
+	FetchedAt time.Time    `json:"fetched_at"`
+	Status    remoteStatus `json:"status"`
+}
+
+func remoteStatusCachePath(rootDir string) string {
+	return filepath.Join(paths.StateDir(rootDir), remoteStatusCacheFileName)
+}
+
+// loadRemoteStatusCache reads the cache; a missing or unreadable cache is empty.
+func loadRemoteStatusCache(rootDir string) remoteStatusCache {
+	cache := remoteStatusCache{Version: remoteStatusCacheVersion, Entries: map[string]remoteStatusCacheEntry{}}
+	data, err := os.ReadFile(remoteStatusCachePath(rootDir))
+	if err != nil {
+		return cache
+	}
+	var loaded remoteStatusCache
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Version != remoteStatusCacheVersion || loaded.Entries == nil {
+		return cache
+	}
+	return loaded
+}
+
+func saveRemoteStatusCache(rootDir string, cache remoteStatusCache) error {
+	path := remoteStatusCachePath(rootDir)
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal remote status cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	// A unique temp file per writer: concurrent `gion manifest ls --remote` runs must not
+	// rename each other's half-written file into place.
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write remote status cache: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write remote status cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write remote status cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write remote status cache: %w", err)
+	}
+	return nil
+}
+
+// fetchRemoteStatuses returns the status of each source URL, from the cache when it is
+// fresh (and refresh is false) and from the providers otherwise. Failed lookups are
+// returned as warnings and are not cached.
+func fetchRemoteStatuses(ctx context.Context, rootDir string, sourceURLs []string, refresh bool, now time.Time) (map[string]remoteStatus, []error) {
+	cache := loadRemoteStatusCache(rootDir)
+	statuses := map[string]remoteStatus{}
+	var pending []string
+	seen := map[string]bool{}
+	for _, sourceURL := range sourceURLs {
+		sourceURL = strings.TrimSpace(sourceURL)
+		if sourceURL == "" || seen[sourceURL] {
+			continue
+		}
+		seen[sourceURL] = true
+		if entry, ok := cache.Entries[sourceURL]; ok && !refresh && now.Sub(entry.FetchedAt) < remoteStatusCacheTTL {
+			statuses[sourceURL] = entry.Status
+			continue
+		}
+		pending = append(pending, sourceURL)
+	}
+	if len(pending) == 0 {
+		return statuses, nil
+	}
+
+	type fetched struct {
+		sourceURL string
+		status    remoteStatus
+		err       error
+	}
+	results := make([]fetched, len(pending))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < remoteStatusWorkers && w < len(pending); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				status, err := fetchRemoteStatus(ctx, pending[i])
+				results[i] = fetched{sourceURL: pending[i], status: status, err: err}
+			}
+		}()
+	}
+	for i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var warnings []error
+	for _, result := range results {
+		if result.err != nil {
+			warnings = append(warnings, fmt.Errorf("remote status %s: %w", result.sourceURL, result.err))
+			continue
+		}
+		statuses[result.sourceURL] = result.status
+		cache.Entries[result.sourceURL] = remoteStatusCacheEntry{FetchedAt: now, Status: result.status}
+	}
+	for sourceURL, entry := range cache.Entries {
+		if now.Sub(entry.FetchedAt) >= 24*time.Hour {
+			delete(cache.Entries, sourceURL)
+		}
+	}
+	if err := saveRemoteStatusCache(rootDir, cache); err != nil {
+		warnings = append(warnings, err)
+	}
+	return statuses, warnings
+}
+
+// fetchRemoteStatus resolves a workspace source_url (PR/MR or issue URL) and asks its
+// provider for the current status.
+func fetchRemoteStatus(ctx context.Context, sourceURL string) (remoteStatus, error) {
+	if req, err := parsePRURL(sourceURL); err == nil {
+		p, err := remoteStatusProviderByName(req.Provider)
+		if err != nil {
+			return remoteStatus{}, err
+		}
+		return p.FetchPRStatus(ctx, req.Host, req.Owner, req.Repo, req.Number)
+	}
+	req, err := parseIssueURL(sourceURL)
+	if err != nil {
+		return remoteStatus{}, fmt.Errorf("not a PR/MR or issue URL")
+	}
+	p, err := remoteStatusProviderByName(req.Provider)
+	if err != nil {
+		return remoteStatus{}, err
+	}
+	return p.FetchIssueStatus(ctx, req.Host, req.Owner, req.Repo, req.Number)
+}
+
+func remoteStatusProviderByName(name string) (remoteStatusProvider, error) {
+	p, err := providerByName(name)
+	if err != nil {
+		return nil, err
+	}
+	statusProvider, ok := p.(remoteStatusProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not report PR/issue status", p.Name())
+	}
+	return statusProvider, nil
+}
+
+// summarizeChecks folds per-check results (passing/failing/pending, or "" to ignore)
+// into one: any failing wins, then pending, then passing.
+func summarizeChecks(results []string) string {
+	summary := ""
+	for _, result := range results {
+		switch result {
+		case remoteChecksFailing:
+			return remoteChecksFailing
+		case remoteChecksPending:
+			summary = remoteChecksPending
+		case remoteChecksPassing:
+			if summary == "" {
+				summary = remoteChecksPassing
+			}
+		}
+	}
+	return summary
+}
+
+// summarizeReviews folds the latest review state per reviewer (APPROVED,
+// CHANGES_REQUESTED, ...) into a review decision.
+func summarizeReviews(latestByReviewer map[string]string) string {
+	decision := ""
+	for _, state := range latestByReviewer {
+		switch strings.ToUpper(state) {
+		case "CHANGES_REQUESTED":
+			return remoteReviewChangesRequested
+		case "APPROVED":
+			decision = remoteReviewApproved
+		}
+	}
+	return decision
+}
+
+func formatRemoteStatusTag(r *ui.Renderer, status remoteStatus) string {
+	label := "PR"
+	if status.Kind == remoteKindIssue {
+		label = "issue"
+	}
+	parts := []string{fmt.Sprintf("%s #%d %s", label, status.Number, status.State)}
+	if status.Review != "" {
+		parts = append(parts, status.Review)
+	}
+	if status.Checks != "" {
+		parts = append(parts, "checks "+status.Checks)
+	}
+	text := "[" + strings.Join(parts, ", ") + "]"
+	if r == nil {
+		return text
+	}
+	switch {
+	case status.Checks == remoteChecksFailing || status.Review == remoteReviewChangesRequested:
+		return r.WarnText(text)
+	case status.State == remoteStateMerged:
+		return r.SuccessText(text)
+	case status.State == remoteStateClosed:
+		return r.MutedText(text)
+	default:
+		return r.AccentText(text)
+	}
+}
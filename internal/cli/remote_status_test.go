@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSummarizeChecks(t *testing.T) {
+	cases := []struct {
+		results []string
+		want    string
+	}{
+		{nil, ""},
+		{[]string{"", ""}, ""},
+		{[]string{remoteChecksPassing, ""}, remoteChecksPassing},
+		{[]string{remoteChecksPassing, remoteChecksPending}, remoteChecksPending},
+		{[]string{remoteChecksPending, remoteChecksFailing, remoteChecksPassing}, remoteChecksFailing},
+	}
+	for _, tc := range cases {
+		if got := summarizeChecks(tc.results); got != tc.want {
+			t.Fatalf("summarizeChecks(%v) = %q, want %q", tc.results, got, tc.want)
+		}
+	}
+}
+
+func TestSummarizeReviews(t *testing.T) {
+	if got := summarizeReviews(map[string]string{"a": "COMMENTED"}); got != "" {
+		t.Fatalf("expected no decision, got %q", got)
+	}
+	if got := summarizeReviews(map[string]string{"a": "APPROVED"}); got != remoteReviewApproved {
+		t.Fatalf("expected approved, got %q", got)
+	}
+	if got := summarizeReviews(map[string]string{"a": "APPROVED", "b": "CHANGES_REQUESTED"}); got != remoteReviewChangesRequested {
+		t.Fatalf("expected changes-requested, got %q", got)
+	}
+}
+
+func TestFormatRemoteStatusTag(t *testing.T) {
+	got := formatRemoteStatusTag(nil, remoteStatus{Kind: remoteKindPR, Number: 12, State: remoteStateOpen, Review: remoteReviewApproved, Checks: remoteChecksPassing})
+	if got != "[PR #12 open, approved, checks passing]" {
+		t.Fatalf("unexpected tag: %q", got)
+	}
+	got = formatRemoteStatusTag(nil, remoteStatus{Kind: remoteKindIssue, Number: 3, State: remoteStateClosed})
+	if got != "[issue #3 closed]" {
+		t.Fatalf("unexpected tag: %q", got)
+	}
+}
+
+func TestFetchRemoteStatusesUsesCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/4":
+			_, _ = w.Write([]byte(`{"state": "open", "draft": true, "head": {"sha": "abc"}}`))
+		case "/repos/owner/repo/commits/abc/status":
+			_, _ = w.Write([]byte(`{"state": "failure", "total_count": 2}`))
+		case "/repos/owner/repo/issues/7":
+			_, _ = w.Write([]byte(`{"state": "closed"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GITEA_TOKEN", "")
+	setupHostsFile(t, fmt.Sprintf("git.example.com:\n  provider: gitea\n  token: secret\n  api_url: %s\n", server.URL))
+
+	rootDir := t.TempDir()
+	prURL := "https://git.example.com/owner/repo/pulls/4"
+	issueURL := "https://git.example.com/owner/repo/issues/7"
+	missingURL := "https://git.example.com/owner/repo/pulls/9"
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	statuses, warnings := fetchRemoteStatuses(context.Background(), rootDir, []string{prURL, issueURL, missingURL, ""}, false, now)
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), missingURL) {
+		t.Fatalf("expected one warning for %s, got %v", missingURL, warnings)
+	}
+	wantPR := remoteStatus{Kind: remoteKindPR, Number: 4, State: remoteStateDraft, Checks: remoteChecksFailing}
+	if statuses[prURL] != wantPR {
+		t.Fatalf("unexpected PR status: %+v", statuses[prURL])
+	}
+	wantIssue := remoteStatus{Kind: remoteKindIssue, Number: 7, State: remoteStateClosed}
+	if statuses[issueURL] != wantIssue {
+		t.Fatalf("unexpected issue status: %+v", statuses[issueURL])
+	}
+	first := requests.Load()
+
+	statuses, _ = fetchRemoteStatuses(context.Background(), rootDir, []string{prURL, issueURL}, false, now.Add(time.Minute))
+	if requests.Load() != first {
+		t.Fatalf("expected cached statuses, got %d new requests", requests.Load()-first)
+	}
+	if statuses[prURL] != wantPR || statuses[issueURL] != wantIssue {
+		t.Fatalf("unexpected cached statuses: %+v", statuses)
+	}
+
+	_, _ = fetchRemoteStatuses(context.Background(), rootDir, []string{issueURL}, true, now.Add(time.Minute))
+	if requests.Load() != first+1 {
+		t.Fatalf("expected refresh to bypass cache, got %d new requests", requests.Load()-first)
+	}
+
+	_, _ = fetchRemoteStatuses(context.Background(), rootDir, []string{prURL}, false, now.Add(remoteStatusCacheTTL+time.Second))
+	if requests.Load() != first+3 {
+		t.Fatalf("expected expired entry to be refetched, got %d new requests", requests.Load()-first)
+	}
+}
+
+func TestSaveRemoteStatusCacheConcurrentWriters(t *testing.T) {
+	rootDir := t.TempDir()
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cache := remoteStatusCache{Version: remoteStatusCacheVersion, Entries: map[string]remoteStatusCacheEntry{
+				fmt.Sprintf("https://git.example.com/owner/repo/pulls/%d", i): {Status: remoteStatus{Kind: remoteKindPR, Number: i, State: remoteStateOpen}},
+			}}
+			errs <- saveRemoteStatusCache(rootDir, cache)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+	if cache := loadRemoteStatusCache(rootDir); len(cache.Entries) != 1 {
+		t.Fatalf("expected one writer's cache to win intact, got %+v", cache)
+	}
+	entries, err := os.ReadDir(filepath.Dir(remoteStatusCachePath(rootDir)))
+	if err != nil {
+		t.Fatalf("read state dir: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != filepath.Base(remoteStatusCachePath(rootDir)) {
+			t.Fatalf("unexpected leftover file: %s", entry.Name())
+		}
+	}
+}
@@ -156,6 +156,9 @@ func renderPlanRepoChanges(renderer *ui.Renderer, changes []manifestplan.RepoCha
 		case manifestplan.RepoUpdate:
 			lines = append(lines, formatPlanRepoUpdate(change))
 			styles = append(styles, treeLineAccent)
+		case manifestplan.RepoMove:
+			lines = append(lines, fmt.Sprintf("~ move repo %s -> %s", change.FromAlias, change.Alias))
+			styles = append(styles, treeLineAccent)
 		}
 	}
 	for i, line := range lines {
@@ -208,9 +211,38 @@ func renderPlanChanges(ctx context.Context, rootDir string, renderer *ui.Rendere
 			renderWorkspaceRiskDetails(renderer, status, output.Indent)
 		case manifestplan.WorkspaceUpdate:
 			renderer.BulletAccent(fmt.Sprintf("~ update workspace %s", change.WorkspaceID))
+			renderPlanWorkspaceMetadata(renderer, change.Metadata, len(change.Repos) == 0)
 			renderPlanWorkspaceUpdateRepos(renderer, change)
+		case manifestplan.WorkspaceMetadata:
+			renderer.BulletAccent(fmt.Sprintf("~ update workspace %s (metadata)", change.WorkspaceID))
+			renderPlanWorkspaceMetadata(renderer, change.Metadata, true)
+		case manifestplan.WorkspaceRename:
+			renderer.BulletAccent(fmt.Sprintf("~ rename workspace %s -> %s", change.FromWorkspaceID, change.WorkspaceID))
+			renderPlanWorkspaceMetadata(renderer, change.Metadata, true)
+		}
+	}
+}
+
+// renderPlanWorkspaceMetadata renders metadata changes as tree lines; endsTree is false
+// when repo changes follow under the same workspace.
+func renderPlanWorkspaceMetadata(renderer *ui.Renderer, changes []manifestplan.MetadataChange, endsTree bool) {
+	if renderer == nil || len(changes) == 0 {
+		return
+	}
+	for i, change := range changes {
+		prefix := output.TreeBranchMid
+		if endsTree && i == len(changes)-1 {
+			prefix = output.TreeBranchLast
 		}
+		renderer.TreeLine(renderer.MutedText(output.Indent+prefix), fmt.Sprintf("%s: %s -> %s", change.Field, formatMetadataValue(change.From), formatMetadataValue(change.To)))
+	}
+}
+
+func formatMetadataValue(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "(none)"
 	}
+	return value
 }
 
 func renderPlanWorkspaceAddRepos(renderer *ui.Renderer, changes []manifestplan.RepoChange) {
@@ -261,6 +293,9 @@ func renderPlanWorkspaceUpdateRepos(renderer *ui.Renderer, change manifestplan.W
 		detailPrefix := baseIndent + detailTreePrefix(i == len(change.Repos)-1)
 
 		name := strings.TrimSpace(repoChange.Alias)
+		if repoChange.Kind == manifestplan.RepoMove {
+			name = strings.TrimSpace(repoChange.FromAlias)
+		}
 		if name == "" {
 			name = strings.TrimSpace(repoChange.ToRepo)
 		}
@@ -321,6 +356,8 @@ func formatRepoChangeSummary(change manifestplan.RepoChange) string {
 			return fmt.Sprintf("remove repo %s", strings.TrimSpace(change.FromRepo))
 		}
 		return "remove repo"
+	case manifestplan.RepoMove:
+		return fmt.Sprintf("alias %s -> %s", strings.TrimSpace(change.FromAlias), strings.TrimSpace(change.Alias))
 	case manifestplan.RepoUpdate:
 		fromRepo := strings.TrimSpace(change.FromRepo)
 		toRepo := strings.TrimSpace(change.ToRepo)
@@ -423,6 +460,9 @@ func buildRepoRiskDetailLines(r *ui.Renderer, repo workspace.RepoStatus) []strin
 	if strings.TrimSpace(repo.Upstream) == "" {
 		lines = append(lines, r.WarnText("note: upstream not set"))
 	}
+	if repo.StashCount > 0 {
+		lines = append(lines, r.WarnText(fmt.Sprintf("note: stash=%d (kept in the repo store)", repo.StashCount)))
+	}
 	if repo.AheadCount > 0 || repo.BehindCount > 0 {
 		lines = append(lines, formatSyncSummaryLine(r, repo))
 	} else if strings.TrimSpace(repo.Upstream) != "" {
@@ -468,11 +508,22 @@ func formatRiskLine(r *ui.Renderer, repo workspace.RepoStatus) string {
 }
 
 func repoRiskSummary(repo workspace.RepoStatus) (string, string, treeLineStyle) {
-	if repo.Error != nil || repo.Detached || repo.HeadMissing {
+	if repo.Error != nil {
+		return "unknown", "", treeLineError
+	}
+	if repo.Operation != "" {
+		return "in-progress", "(" + repo.Operation + ")", treeLineError
+	}
+	if repo.Detached || repo.HeadMissing {
 		return "unknown", "", treeLineError
 	}
 	if strings.TrimSpace(repo.Upstream) == "" {
-		return "upstream missing", "", treeLineWarn
+		switch {
+		case strings.TrimSpace(repo.BaseRef) == "":
+			return "upstream missing", "", treeLineWarn
+		case repo.LocalCommitCount > 0:
+			return "unpublished", fmt.Sprintf("(commits=%d not on %s)", repo.LocalCommitCount, displayBaseRef(repo.BaseRef)), treeLineWarn
+		}
 	}
 	if repo.Dirty {
 		detail := firstNonZeroCountKV([]struct {
@@ -495,10 +546,19 @@ func repoRiskSummary(repo workspace.RepoStatus) (string, string, treeLineStyle)
 	if repo.AheadCount > 0 {
 		return "unpushed", fmt.Sprintf("(ahead=%d)", repo.AheadCount), treeLineWarn
 	}
+	if repo.StashCount > 0 {
+		return "stashed", fmt.Sprintf("(stash=%d)", repo.StashCount), treeLineWarn
+	}
 	// Clean repos are not risks; keep the output focused by omitting the line.
 	return "", "", treeLineNormal
 }
 
+// displayBaseRef shortens refs/remotes/ and refs/heads/ prefixes for risk lines.
+func displayBaseRef(ref string) string {
+	ref = strings.TrimPrefix(ref, "refs/remotes/")
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
+
 func firstNonZeroCountKV(items []struct {
 	key   string
 	value int
@@ -1,6 +1,9 @@
 package cli
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestParsePRURLGitHub(t *testing.T) {
 	req, err := parsePRURL("https://github.com/owner/repo/pull/123")
@@ -19,7 +22,63 @@ func TestParsePRURLUnsupported(t *testing.T) {
 	if _, err := parsePRURL("https://example.com/foo/bar"); err == nil {
 		t.Fatalf("expected error for unsupported host/path")
 	}
-	if _, err := parsePRURL("https://gitlab.com/owner/repo/-/merge_requests/1"); err == nil {
-		t.Fatalf("expected error for unsupported host")
+	if _, err := parsePRURL("https://gitlab.com/owner/repo/issues/1"); err == nil {
+		t.Fatalf("expected error for non MR URL")
+	}
+	if _, err := parsePRURL("https://gitlab.com/group/sub/repo/-/merge_requests/1"); err == nil || !strings.Contains(err.Error(), "nested groups are not supported") {
+		t.Fatalf("expected error for nested groups, got %v", err)
+	}
+}
+
+func TestParsePRURLGitLab(t *testing.T) {
+	req, err := parsePRURL("https://gitlab.com/owner/repo/-/merge_requests/7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Provider != "gitlab" || req.Host != "gitlab.com" || req.Owner != "owner" || req.Repo != "repo" || req.Number != 7 {
+		t.Fatalf("unexpected result: %+v", req)
+	}
+}
+
+func TestParsePRURLGitLabSelfHosted(t *testing.T) {
+	t.Setenv(gitlabURLEnv, "https://git.example.com/gitlab")
+	req, err := parsePRURL("https://git.example.com/gitlab/owner/repo/-/merge_requests/7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Provider != "gitlab" || req.Host != "git.example.com" || req.Owner != "owner" || req.Repo != "repo" || req.Number != 7 {
+		t.Fatalf("unexpected result: %+v", req)
+	}
+	if got := buildPRURLFromParts(req.Host, req.Owner, req.Repo, req.Number); got != "https://git.example.com/gitlab/owner/repo/-/merge_requests/7" {
+		t.Fatalf("unexpected MR URL: %s", got)
+	}
+}
+
+func TestParsePRURLOtherProviders(t *testing.T) {
+	setupHostsFile(t, "git.example.com:\n  provider: gitea\n")
+	cases := []struct {
+		url      string
+		provider string
+		owner    string
+	}{
+		{"https://git.example.com/owner/repo/pulls/5", "gitea", "owner"},
+		{"https://codeberg.org/owner/repo/pulls/5", "forgejo", "owner"},
+		{"https://bitbucket.org/owner/repo/pull-requests/5", "bitbucket", "owner"},
+		{"https://bitbucket.example.com/projects/PROJ/repos/repo/pull-requests/5/overview", "bitbucket-server", "PROJ"},
+	}
+	for _, tc := range cases {
+		req, err := parsePRURL(tc.url)
+		if err != nil {
+			t.Fatalf("parsePRURL(%s): unexpected error: %v", tc.url, err)
+		}
+		if req.Provider != tc.provider || req.Owner != tc.owner || req.Repo != "repo" || req.Number != 5 {
+			t.Fatalf("parsePRURL(%s): unexpected result: %+v", tc.url, req)
+		}
+		if got := buildPRURLFromParts(req.Host, req.Owner, req.Repo, req.Number); !strings.HasPrefix(tc.url, got) {
+			t.Fatalf("buildPRURLFromParts: got %s for %s", got, tc.url)
+		}
+	}
+	if _, err := parsePRURL("https://git.example.com/owner/repo/pull/5"); err == nil {
+		t.Fatalf("expected error for GitHub-style path on Gitea")
 	}
 }
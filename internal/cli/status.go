@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/status"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runStatus(ctx context.Context, rootDir string, args []string) error {
+	statusFlags := flag.NewFlagSet("status", flag.ContinueOnError)
+	var verbose bool
+	var jsonFlag bool
+	var helpFlag bool
+	statusFlags.BoolVar(&verbose, "v", false, "list changed files")
+	statusFlags.BoolVar(&verbose, "verbose", false, "list changed files")
+	statusFlags.BoolVar(&jsonFlag, "json", false, "print JSON")
+	statusFlags.BoolVar(&helpFlag, "help", false, "show help")
+	statusFlags.BoolVar(&helpFlag, "h", false, "show help")
+	statusFlags.SetOutput(os.Stdout)
+	statusFlags.Usage = func() {
+		printStatusHelp(os.Stdout)
+	}
+	workspaceIDs, err := parseInterspersed(statusFlags, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printStatusHelp(os.Stdout)
+		return nil
+	}
+
+	results, err := status.Collect(ctx, rootDir, uniqueNonEmptyStrings(workspaceIDs), status.DefaultConcurrency)
+	if err != nil {
+		return err
+	}
+	if jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(status.Export(results)); err != nil {
+			return fmt.Errorf("encode status json: %w", err)
+		}
+		return nil
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	renderer.Section("Result")
+	if len(results) == 0 {
+		renderer.Bullet("no workspaces")
+		return nil
+	}
+	for i, result := range results {
+		if i > 0 {
+			renderer.Blank()
+		}
+		renderStatusWorkspace(os.Stdout, renderer, result, verbose)
+	}
+	return nil
+}
+
+func renderStatusWorkspace(w io.Writer, renderer *ui.Renderer, result status.Workspace, verbose bool) {
+	label := result.WorkspaceID
+	if tag := statusStateTag(renderer, result.State.Kind); tag != "" {
+		label += " " + tag
+	}
+	renderer.BulletWithDescription(label, result.Description, "")
+	indent := output.Indent2
+	if result.Err != nil {
+		fmt.Fprintf(w, "%s%s\n", indent, renderer.ErrorText(fmt.Sprintf("status error: %s", compactError(result.Err))))
+		return
+	}
+	if len(result.Status.Repos) == 0 {
+		fmt.Fprintf(w, "%s%s\n", indent, renderer.MutedText("no repos"))
+	} else {
+		rows := formatStatusTable(result.Status.Repos)
+		fmt.Fprintf(w, "%s%s\n", indent, renderer.MutedText(rows[0]))
+		for i, repo := range result.Status.Repos {
+			fmt.Fprintf(w, "%s%s%s\n", indent, rows[i+1], statusRepoStateText(renderer, result.State.Repos[i]))
+			if !verbose {
+				continue
+			}
+			for _, file := range repo.ChangedFiles {
+				fmt.Fprintf(w, "%s%s%s\n", indent, output.Indent, renderer.MutedText(file))
+			}
+		}
+	}
+	for _, warn := range result.Status.Warnings {
+		fmt.Fprintf(w, "%s%s\n", indent, renderer.WarnText(fmt.Sprintf("warning: %s", compactError(warn))))
+	}
+}
+
+// formatStatusTable returns the aligned header and one row per repo, each ending where
+// the state column starts (the state is appended by the caller, styled).
+func formatStatusTable(repos []workspace.RepoStatus) []string {
+	var buf strings.Builder
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPO\tBRANCH\tUPSTREAM\tAHEAD\tBEHIND\tSTAGED\tUNSTAGED\tUNTRACKED\tUNMERGED\tSTATE")
+	for _, repo := range repos {
+		branch := repo.Branch
+		if repo.Detached {
+			branch = fmt.Sprintf("(detached %s)", repo.Head)
+		}
+		upstream := repo.Upstream
+		if strings.TrimSpace(upstream) == "" {
+			upstream = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t\n", repo.Alias, branch, upstream, repo.AheadCount, repo.BehindCount, repo.StagedCount, repo.UnstagedCount, repo.UntrackedCount, repo.UnmergedCount)
+	}
+	_ = tw.Flush()
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+}
+
+func statusRepoStateText(renderer *ui.Renderer, repo workspace.RepoState) string {
+	text := string(repo.Kind)
+	if repo.Operation != "" {
+		text += fmt.Sprintf(" (%s)", repo.Operation)
+	}
+	if repo.Error != nil {
+		text += fmt.Sprintf(" (%s)", compactError(repo.Error))
+	}
+	switch repo.Kind {
+	case workspace.RepoStateClean:
+		return renderer.SuccessText(text)
+	case workspace.RepoStateUnknown, workspace.RepoStateInProgress:
+		return renderer.ErrorText(text)
+	default:
+		return renderer.WarnText(text)
+	}
+}
+
+// statusStateTag labels a workspace the way the workspace pickers do: clean is omitted.
+func statusStateTag(renderer *ui.Renderer, kind workspace.WorkspaceStateKind) string {
+	switch kind {
+	case workspace.WorkspaceStateClean, "":
+		return ""
+	case workspace.WorkspaceStateUnknown, workspace.WorkspaceStateDirty, workspace.WorkspaceStateInProgress:
+		return renderer.ErrorText("[" + string(kind) + "]")
+	default:
+		return renderer.WarnText("[" + string(kind) + "]")
+	}
+}
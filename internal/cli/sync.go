@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/synccmd"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runSync(ctx context.Context, rootDir string, args []string, noPrompt bool) error {
+	syncFlags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	var strategyFlag string
+	var helpFlag bool
+	syncFlags.StringVar(&strategyFlag, "strategy", string(synccmd.StrategyRebase), "rebase, merge, or ff-only")
+	syncFlags.BoolVar(&helpFlag, "help", false, "show help")
+	syncFlags.BoolVar(&helpFlag, "h", false, "show help")
+	syncFlags.SetOutput(os.Stdout)
+	syncFlags.Usage = func() {
+		printSyncHelp(os.Stdout)
+	}
+	positional, err := parseInterspersed(syncFlags, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printSyncHelp(os.Stdout)
+		return nil
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: gion sync <WORKSPACE_ID> [--strategy rebase|merge|ff-only]")
+	}
+	strategy, err := synccmd.ParseStrategy(strategyFlag)
+	if err != nil {
+		return err
+	}
+	workspaceID := positional[0]
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	output.SetStepLogger(renderer)
+	defer output.SetStepLogger(nil)
+
+	renderer.Section("Inputs")
+	renderer.Bullet(fmt.Sprintf("workspace: %s", workspaceID))
+	renderer.Bullet(fmt.Sprintf("strategy: %s", strategy))
+
+	plan, err := synccmd.Prepare(ctx, rootDir, workspaceID, strategy)
+	if err != nil {
+		return err
+	}
+	if len(plan.Warnings) > 0 {
+		renderer.Blank()
+		renderer.Section("Info")
+		for _, warn := range plan.Warnings {
+			renderer.BulletWarn(compactError(warn))
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Plan")
+	if len(plan.Repos) == 0 {
+		renderer.Bullet("no repos")
+		return nil
+	}
+	for _, repoEntry := range plan.Repos {
+		switch repoEntry.Action {
+		case synccmd.ActionUpdate:
+			renderer.Bullet(fmt.Sprintf("%s: %s %s onto %s (behind %d, ahead %d)", repoEntry.Alias, strategy, repoEntry.Branch, repoEntry.BaseRef, repoEntry.Behind, repoEntry.Ahead))
+		case synccmd.ActionUpToDate:
+			renderer.Bullet(renderer.MutedText(fmt.Sprintf("%s: up to date with %s", repoEntry.Alias, repoEntry.BaseRef)))
+		default:
+			renderer.BulletWarn(fmt.Sprintf("%s: skip (%s)", repoEntry.Alias, repoEntry.Reason))
+		}
+	}
+	updates := plan.Updates()
+	if len(updates) == 0 {
+		renderer.Blank()
+		renderer.Section("Result")
+		renderer.Bullet("nothing to sync")
+		renderSyncMergeSuggestion(renderer, plan)
+		return nil
+	}
+
+	if !noPrompt {
+		renderer.Blank()
+		confirm, err := ui.PromptConfirmInlinePlan(fmt.Sprintf("Sync %d repo(s)? (default: No)", len(updates)), theme, useColor)
+		if err != nil {
+			if errors.Is(err, ui.ErrPromptCanceled) {
+				return nil
+			}
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Steps")
+	results, runErr := synccmd.Run(ctx, plan, synccmd.Options{Step: output.Step})
+
+	renderer.Blank()
+	renderer.Section("Result")
+	var stopped *synccmd.Result
+	for i, result := range results {
+		alias := result.Repo.Alias
+		switch result.Outcome {
+		case synccmd.OutcomeUpdated:
+			renderer.BulletSuccess(fmt.Sprintf("%s: updated onto %s", alias, result.Repo.BaseRef))
+		case synccmd.OutcomeConflict:
+			stopped = &results[i]
+			renderer.BulletError(fmt.Sprintf("%s: conflict with %s (aborted, branch unchanged)", alias, result.Repo.BaseRef))
+			for _, file := range result.ConflictFiles {
+				fmt.Fprintf(os.Stdout, "%s%s\n", output.Indent2, renderer.MutedText(file))
+			}
+		case synccmd.OutcomeFailed:
+			stopped = &results[i]
+			renderer.BulletError(fmt.Sprintf("%s: %s", alias, compactError(result.Err)))
+		default:
+			renderer.BulletWarn(fmt.Sprintf("%s: not run", alias))
+		}
+	}
+	for _, repoEntry := range plan.Repos {
+		if repoEntry.Action == synccmd.ActionSkip {
+			renderer.BulletWarn(fmt.Sprintf("%s: skipped (%s)", repoEntry.Alias, repoEntry.Reason))
+		}
+	}
+	if stopped != nil && stopped.Outcome == synccmd.OutcomeConflict {
+		renderer.Blank()
+		renderer.Section("Suggestion")
+		command := fmt.Sprintf("git rebase %s", stopped.Repo.BaseRef)
+		if strategy != synccmd.StrategyRebase {
+			command = fmt.Sprintf("git merge %s", stopped.Repo.BaseRef)
+		}
+		renderer.Bullet(fmt.Sprintf("resolve by hand: cd %s && %s", stopped.Repo.Dir, command))
+	} else {
+		renderSyncMergeSuggestion(renderer, plan)
+	}
+	return runErr
+}
+
+// renderSyncMergeSuggestion suggests merging when repos were skipped because a rebase
+// would rewrite pushed commits.
+func renderSyncMergeSuggestion(renderer *ui.Renderer, plan synccmd.Plan) {
+	for _, repoEntry := range plan.Repos {
+		if repoEntry.Action == synccmd.ActionSkip && repoEntry.Published > 0 {
+			renderer.Blank()
+			renderer.Section("Suggestion")
+			renderer.Bullet(fmt.Sprintf("sync pushed branches without rewriting them: gion sync %s --strategy merge", plan.WorkspaceID))
+			return
+		}
+	}
+}
@@ -91,6 +91,12 @@ func removeConfirmLabel(state workspace.WorkspaceState) string {
 		return "This workspace has unpushed commits. Remove anyway?"
 	case workspace.WorkspaceStateDiverged:
 		return "This workspace has diverged from upstream. Remove anyway?"
+	case workspace.WorkspaceStateUnpublished:
+		return "This workspace has commits that were never pushed. Remove anyway?"
+	case workspace.WorkspaceStateStashed:
+		return "This workspace has stashed changes. Remove anyway?"
+	case workspace.WorkspaceStateInProgress:
+		return "This workspace has an unfinished rebase, merge, or cherry-pick. Remove anyway?"
 	case workspace.WorkspaceStateUnknown:
 		return "Workspace status could not be read. Remove anyway?"
 	default:
@@ -104,8 +110,14 @@ func workspaceRemoveWarningLabel(state workspace.WorkspaceState) (string, bool)
 		return "unpushed", false
 	case workspace.WorkspaceStateDiverged:
 		return "diverged", false
+	case workspace.WorkspaceStateUnpublished:
+		return "unpublished", false
+	case workspace.WorkspaceStateStashed:
+		return "stashed", false
 	case workspace.WorkspaceStateUnknown:
 		return "unknown", true
+	case workspace.WorkspaceStateInProgress:
+		return "in-progress", true
 	case workspace.WorkspaceStateDirty:
 		return "dirty", true
 	default:
@@ -186,7 +198,7 @@ func repoNeedsStatusDetails(repo workspace.RepoStatus) bool {
 	if strings.TrimSpace(repo.Upstream) == "" {
 		return true
 	}
-	if repo.AheadCount > 0 {
+	if repo.AheadCount > 0 || repo.StashCount > 0 || repo.Operation != "" {
 		return true
 	}
 	return false
@@ -0,0 +1,184 @@
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+const historyDirName = "manifest-history"
+
+const historyIndexName = "index.json"
+
+// HistoryLimit is the number of previous gion.yaml revisions kept; older ones are pruned.
+const HistoryLimit = 20
+
+// historyCommand labels the revisions written by this process (see SetHistoryCommand).
+var historyCommand string
+
+// SetHistoryCommand sets the command line recorded for gion.yaml revisions written by
+// this process.
+func SetHistoryCommand(command string) {
+	historyCommand = command
+}
+
+// Revision is a previous gion.yaml content kept in GION_ROOT/.gion/manifest-history.
+type Revision struct {
+	ID string `json:"id"`
+	// ReplacedAt is when a gion command overwrote this revision.
+	ReplacedAt time.Time `json:"replaced_at"`
+	// Command is the gion command that wrote this revision. It is empty when the
+	// revision predates the history or was edited outside gion (then Edited is set).
+	Command string `json:"command,omitempty"`
+	Edited  bool   `json:"edited,omitempty"`
+	// ReplacedBy is the gion command that overwrote it.
+	ReplacedBy string `json:"replaced_by,omitempty"`
+}
+
+type historyIndex struct {
+	// Head describes the gion.yaml content gion wrote last, so the next revision can be
+	// attributed to that command (or to a manual edit when the content no longer matches).
+	Head      historyHead `json:"head"`
+	Revisions []Revision  `json:"revisions"`
+}
+
+type historyHead struct {
+	Command string `json:"command,omitempty"`
+	SHA256  string `json:"sha256,omitempty"`
+}
+
+func HistoryDir(rootDir string) string {
+	return filepath.Join(paths.StateDir(rootDir), historyDirName)
+}
+
+// History returns the recorded revisions, newest first.
+func History(rootDir string) ([]Revision, error) {
+	index, err := loadHistoryIndex(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]Revision, 0, len(index.Revisions))
+	for i := len(index.Revisions) - 1; i >= 0; i-- {
+		revisions = append(revisions, index.Revisions[i])
+	}
+	return revisions, nil
+}
+
+// ReadRevision returns the gion.yaml content of a recorded revision.
+func ReadRevision(rootDir string, revision Revision) ([]byte, error) {
+	data, err := os.ReadFile(revisionPath(rootDir, revision.ID))
+	if err != nil {
+		return nil, fmt.Errorf("read %s revision %s: %w", FileName, revision.ID, err)
+	}
+	return data, nil
+}
+
+// Revert restores gion.yaml to data after a Save whose change was abandoned (e.g. the
+// follow-up apply was declined), dropping the revision that Save recorded for it.
+func Revert(rootDir string, data []byte) error {
+	if err := WriteBytes(rootDir, data); err != nil {
+		return err
+	}
+	index, err := loadHistoryIndex(rootDir)
+	if err != nil {
+		return err
+	}
+	if n := len(index.Revisions); n > 0 {
+		last := index.Revisions[n-1]
+		if content, err := os.ReadFile(revisionPath(rootDir, last.ID)); err == nil && bytes.Equal(content, data) {
+			index.Revisions = index.Revisions[:n-1]
+			_ = os.Remove(revisionPath(rootDir, last.ID))
+			index.Head = historyHead{Command: last.Command, SHA256: contentHash(data)}
+			return saveHistoryIndex(rootDir, index)
+		}
+	}
+	return nil
+}
+
+// recordRevision keeps previous (the gion.yaml content that next replaced) in the history.
+func recordRevision(rootDir string, previous, next []byte) error {
+	if len(previous) == 0 || bytes.Equal(previous, next) {
+		return nil
+	}
+	index, err := loadHistoryIndex(rootDir)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	revision := Revision{
+		ID:         now.Format("20060102T150405.000000000Z"),
+		ReplacedAt: now,
+		ReplacedBy: historyCommand,
+	}
+	if index.Head.SHA256 != "" {
+		if index.Head.SHA256 == contentHash(previous) {
+			revision.Command = index.Head.Command
+		} else {
+			revision.Edited = true
+		}
+	}
+	if err := os.MkdirAll(HistoryDir(rootDir), 0o750); err != nil {
+		return fmt.Errorf("create %s history dir: %w", FileName, err)
+	}
+	if err := os.WriteFile(revisionPath(rootDir, revision.ID), previous, 0o600); err != nil {
+		return fmt.Errorf("write %s history: %w", FileName, err)
+	}
+	index.Revisions = append(index.Revisions, revision)
+	for len(index.Revisions) > HistoryLimit {
+		_ = os.Remove(revisionPath(rootDir, index.Revisions[0].ID))
+		index.Revisions = index.Revisions[1:]
+	}
+	index.Head = historyHead{Command: historyCommand, SHA256: contentHash(next)}
+	return saveHistoryIndex(rootDir, index)
+}
+
+func revisionPath(rootDir, id string) string {
+	return filepath.Join(HistoryDir(rootDir), id+".yaml")
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func loadHistoryIndex(rootDir string) (historyIndex, error) {
+	data, err := os.ReadFile(filepath.Join(HistoryDir(rootDir), historyIndexName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return historyIndex{}, nil
+		}
+		return historyIndex{}, fmt.Errorf("read %s history: %w", FileName, err)
+	}
+	var index historyIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return historyIndex{}, fmt.Errorf("parse %s history: %w", FileName, err)
+	}
+	return index, nil
+}
+
+func saveHistoryIndex(rootDir string, index historyIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s history: %w", FileName, err)
+	}
+	if err := os.MkdirAll(HistoryDir(rootDir), 0o750); err != nil {
+		return fmt.Errorf("create %s history dir: %w", FileName, err)
+	}
+	path := filepath.Join(HistoryDir(rootDir), historyIndexName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write %s history: %w", FileName, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write %s history: %w", FileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestSaveRecordsHistory(t *testing.T) {
+	rootDir := t.TempDir()
+	t.Cleanup(func() { SetHistoryCommand("") })
+	if err := os.WriteFile(Path(rootDir), []byte("version: 1\nworkspaces: {}\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	SetHistoryCommand("gion manifest add WS-1")
+	first := File{Version: 1, Workspaces: map[string]Workspace{"WS-1": {Repos: []Repo{}}}}
+	if err := Save(rootDir, first); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	afterFirst, err := os.ReadFile(Path(rootDir))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	SetHistoryCommand("gion manifest rm WS-1")
+	if err := Save(rootDir, File{Version: 1}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// Saving identical content must not add a revision.
+	if err := Save(rootDir, File{Version: 1}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	revisions, err := History(rootDir)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %+v", revisions)
+	}
+	if revisions[0].Command != "gion manifest add WS-1" || revisions[0].ReplacedBy != "gion manifest rm WS-1" {
+		t.Fatalf("unexpected newest revision: %+v", revisions[0])
+	}
+	if revisions[1].Command != "" || revisions[1].Edited || revisions[1].ReplacedBy != "gion manifest add WS-1" {
+		t.Fatalf("unexpected oldest revision: %+v", revisions[1])
+	}
+	data, err := ReadRevision(rootDir, revisions[0])
+	if err != nil {
+		t.Fatalf("read revision: %v", err)
+	}
+	if string(data) != string(afterFirst) {
+		t.Fatalf("revision content mismatch:\n%s\nwant:\n%s", data, afterFirst)
+	}
+
+	// A hand edit between gion writes is attributed as such.
+	if err := os.WriteFile(Path(rootDir), []byte("version: 1\nworkspaces: {}\n# edited\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	SetHistoryCommand("gion import")
+	if err := Save(rootDir, first); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	revisions, err = History(rootDir)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(revisions) != 3 || !revisions[0].Edited || revisions[0].Command != "" {
+		t.Fatalf("expected edited revision first, got %+v", revisions)
+	}
+}
+
+func TestRevertDropsAbandonedRevision(t *testing.T) {
+	rootDir := t.TempDir()
+	original := []byte("version: 1\nworkspaces: {}\n")
+	if err := os.WriteFile(Path(rootDir), original, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := Save(rootDir, File{Version: 1, Workspaces: map[string]Workspace{"WS-1": {}}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := Revert(rootDir, original); err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	revisions, err := History(rootDir)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(revisions) != 0 {
+		t.Fatalf("expected abandoned revision to be dropped, got %+v", revisions)
+	}
+	data, err := os.ReadFile(Path(rootDir))
+	if err != nil || string(data) != string(original) {
+		t.Fatalf("expected original content, got %q (err=%v)", data, err)
+	}
+}
+
+func TestHistoryIsPruned(t *testing.T) {
+	rootDir := t.TempDir()
+	if err := os.WriteFile(Path(rootDir), []byte("version: 1\nworkspaces: {}\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for i := 0; i < HistoryLimit+3; i++ {
+		file := File{Version: 1, Workspaces: map[string]Workspace{fmt.Sprintf("WS-%d", i): {}}}
+		if err := Save(rootDir, file); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+	revisions, err := History(rootDir)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(revisions) != HistoryLimit {
+		t.Fatalf("expected %d revisions, got %d", HistoryLimit, len(revisions))
+	}
+	entries, err := os.ReadDir(HistoryDir(rootDir))
+	if err != nil {
+		t.Fatalf("readdir: %v", err)
+	}
+	if len(entries) != HistoryLimit+1 {
+		t.Fatalf("expected %d revision files plus index, got %d", HistoryLimit, len(entries))
+	}
+}
@@ -2,6 +2,7 @@ package manifest
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -23,20 +24,55 @@ type Workspace struct {
 	Mode        string `yaml:"mode,omitempty"`
 	PresetName  string `yaml:"preset_name,omitempty"`
 	SourceURL   string `yaml:"source_url,omitempty"`
+	Hooks       Hooks  `yaml:"hooks,omitempty"`
 	Repos       []Repo `yaml:"repos"`
 }
 
 type Preset struct {
 	Repos []string `yaml:"repos"`
+	Hooks Hooks    `yaml:"hooks,omitempty"`
+}
+
+// Hooks are shell commands gion apply runs in each repo worktree of a workspace.
+type Hooks struct {
+	// PostCreate runs after apply creates a worktree.
+	PostCreate []string `yaml:"post_create,omitempty"`
+	// PreRemove runs before apply removes a worktree.
+	PreRemove []string `yaml:"pre_remove,omitempty"`
+	// PostApply runs after an apply that changed the workspace completes.
+	PostApply []string `yaml:"post_apply,omitempty"`
+}
+
+func (h Hooks) IsZero() bool {
+	return len(h.PostCreate) == 0 && len(h.PreRemove) == 0 && len(h.PostApply) == 0
+}
+
+// WorkspaceHooks returns the hooks that apply to ws: those of its preset (when the
+// preset still exists in file) followed by its own.
+func WorkspaceHooks(file File, ws Workspace) Hooks {
+	var hooks Hooks
+	if preset, ok := file.Presets[strings.TrimSpace(ws.PresetName)]; ok && strings.TrimSpace(ws.PresetName) != "" {
+		hooks = appendHooks(hooks, preset.Hooks)
+	}
+	return appendHooks(hooks, ws.Hooks)
+}
+
+func appendHooks(dst, src Hooks) Hooks {
+	dst.PostCreate = append(dst.PostCreate, src.PostCreate...)
+	dst.PreRemove = append(dst.PreRemove, src.PreRemove...)
+	dst.PostApply = append(dst.PostApply, src.PostApply...)
+	return dst
 }
 
 func (p *Preset) UnmarshalYAML(value *yaml.Node) error {
 	type rawPreset struct {
 		Repos []string `yaml:"repos"`
+		Hooks Hooks    `yaml:"hooks"`
 	}
 	var direct rawPreset
 	if err := value.Decode(&direct); err == nil && len(direct.Repos) > 0 {
 		p.Repos = direct.Repos
+		p.Hooks = direct.Hooks
 		return nil
 	}
 
@@ -44,8 +80,10 @@ func (p *Preset) UnmarshalYAML(value *yaml.Node) error {
 		Repos []struct {
 			Repo string `yaml:"repo"`
 		} `yaml:"repos"`
+		Hooks Hooks `yaml:"hooks"`
 	}
 	if err := value.Decode(&legacy); err == nil && len(legacy.Repos) > 0 {
+		p.Hooks = legacy.Hooks
 		for _, item := range legacy.Repos {
 			if strings.TrimSpace(item.Repo) == "" {
 				continue
@@ -75,6 +113,11 @@ func Load(rootDir string) (File, error) {
 	if err != nil {
 		return File{}, fmt.Errorf("read %s: %w", FileName, err)
 	}
+	return Parse(data)
+}
+
+// Parse decodes gion.yaml content and fills in defaults for missing sections.
+func Parse(data []byte) (File, error) {
 	var file File
 	if err := yaml.Unmarshal(data, &file); err != nil {
 		return File{}, fmt.Errorf("parse %s: %w", FileName, err)
@@ -91,12 +134,77 @@ func Load(rootDir string) (File, error) {
 	return file, nil
 }
 
+// Save writes file to gion.yaml. When gion.yaml already exists, the changes are applied
+// to its YAML node tree so hand-written comments, key order, and blank lines survive.
+// The file is replaced atomically.
 func Save(rootDir string, file File) error {
 	data, err := Marshal(file)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(Path(rootDir), data, 0o600); err != nil {
+	current, err := os.ReadFile(Path(rootDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read %s: %w", FileName, err)
+	}
+	if len(current) > 0 {
+		if merged, ok := mergeDocument(current, data); ok {
+			data = merged
+		}
+	}
+	return saveWithHistory(rootDir, current, data)
+}
+
+// SaveBytes replaces gion.yaml with data as-is (e.g. a revision from the history),
+// recording the previous content in the history like Save does.
+func SaveBytes(rootDir string, data []byte) error {
+	current, err := os.ReadFile(Path(rootDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read %s: %w", FileName, err)
+	}
+	return saveWithHistory(rootDir, current, data)
+}
+
+func saveWithHistory(rootDir string, current, data []byte) error {
+	if err := WriteBytes(rootDir, data); err != nil {
+		return err
+	}
+	return recordRevision(rootDir, current, data)
+}
+
+// WriteBytes atomically replaces gion.yaml with data (temp file in the same directory,
+// then rename), so a crash never leaves a truncated manifest behind. Unlike Save, it does
+// not touch the history.
+func WriteBytes(rootDir string, data []byte) error {
+	path := Path(rootDir)
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+FileName+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", FileName, err)
+	}
+	tmpPath := tmp.Name()
+	cleanup := func(cause error) error {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write %s: %w", FileName, cause)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write %s: %w", FileName, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
 		return fmt.Errorf("write %s: %w", FileName, err)
 	}
 	return nil
@@ -0,0 +1,282 @@
+package manifest
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// blankLineMarker stands in for blank lines while gion.yaml is round-tripped through
+// yaml.Node, which keeps comments but not blank lines.
+const blankLineMarker = "#gion:blank"
+
+// mergeDocument applies the manifest encoded in next to the existing gion.yaml content
+// in current, preserving comments, key order, and blank lines of current. It reports
+// false when current cannot be merged safely; callers then write next as-is.
+func mergeDocument(current, next []byte) ([]byte, bool) {
+	var want File
+	if err := yaml.Unmarshal(next, &want); err != nil {
+		return nil, false
+	}
+	oldDoc, ok := parseWithBlankLines(current)
+	if !ok {
+		return nil, false
+	}
+	var newDoc yaml.Node
+	if err := yaml.Unmarshal(next, &newDoc); err != nil {
+		return nil, false
+	}
+	oldRoot := unwrapDocument(oldDoc)
+	newRoot := unwrapDocument(&newDoc)
+	if oldRoot == nil || newRoot == nil || oldRoot.Kind != yaml.MappingNode || newRoot.Kind != yaml.MappingNode {
+		return nil, false
+	}
+	mergeNode(oldRoot, newRoot)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(oldDoc); err != nil {
+		_ = enc.Close()
+		return nil, false
+	}
+	if err := enc.Close(); err != nil {
+		return nil, false
+	}
+	out := restoreBlankLines(buf.Bytes())
+
+	// The merged document must describe exactly the manifest we were asked to write.
+	var got File
+	if err := yaml.Unmarshal(out, &got); err != nil || !reflect.DeepEqual(normalizeFile(got), normalizeFile(want)) {
+		return nil, false
+	}
+	return out, true
+}
+
+// parseWithBlankLines parses data with blank lines turned into marker comments. If the
+// markers change the meaning of the document (e.g. blank lines inside a block scalar),
+// it parses the original data instead and blank lines are not preserved.
+func parseWithBlankLines(data []byte) (*yaml.Node, bool) {
+	var plain File
+	if err := yaml.Unmarshal(data, &plain); err != nil {
+		return nil, false
+	}
+	marked := markBlankLines(data)
+	var markedFile File
+	if err := yaml.Unmarshal(marked, &markedFile); err == nil && reflect.DeepEqual(markedFile, plain) {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(marked, &doc); err == nil {
+			return &doc, true
+		}
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, false
+	}
+	return &doc, true
+}
+
+func markBlankLines(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	trailing := len(lines) > 0 && lines[len(lines)-1] == ""
+	if trailing {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			continue
+		}
+		indent := ""
+		for _, nextLine := range lines[i+1:] {
+			if strings.TrimSpace(nextLine) == "" {
+				continue
+			}
+			indent = nextLine[:len(nextLine)-len(strings.TrimLeft(nextLine, " "))]
+			break
+		}
+		lines[i] = indent + blankLineMarker
+	}
+	out := strings.Join(lines, "\n")
+	if trailing {
+		out += "\n"
+	}
+	return []byte(out)
+}
+
+func restoreBlankLines(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == blankLineMarker {
+			lines[i] = ""
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func normalizeFile(file File) File {
+	if file.Workspaces == nil {
+		file.Workspaces = map[string]Workspace{}
+	}
+	if file.Presets == nil {
+		file.Presets = map[string]Preset{}
+	}
+	for id, ws := range file.Workspaces {
+		if len(ws.Repos) == 0 {
+			ws.Repos = nil
+			file.Workspaces[id] = ws
+		}
+	}
+	return file
+}
+
+// mergeNode rewrites dst in place so it encodes the same value as src, reusing dst's
+// nodes (and therefore their comments and styles) wherever the two agree.
+func mergeNode(dst, src *yaml.Node) {
+	if dst.Kind != src.Kind {
+		replaceNode(dst, src)
+		return
+	}
+	switch dst.Kind {
+	case yaml.MappingNode:
+		mergeMapping(dst, src)
+	case yaml.SequenceNode:
+		mergeSequence(dst, src)
+	case yaml.ScalarNode:
+		mergeScalar(dst, src)
+	default:
+		replaceNode(dst, src)
+	}
+}
+
+func replaceNode(dst, src *yaml.Node) {
+	head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+	*dst = *src
+	if dst.HeadComment == "" {
+		dst.HeadComment = head
+	}
+	if dst.LineComment == "" {
+		dst.LineComment = line
+	}
+	if dst.FootComment == "" {
+		dst.FootComment = foot
+	}
+}
+
+// mergeMapping keeps existing keys in their original order, drops keys that are gone,
+// and inserts new keys right after the key that precedes them in src.
+func mergeMapping(dst, src *yaml.Node) {
+	if len(dst.Content) == 0 {
+		dst.Style = src.Style
+	}
+	srcValues := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		srcValues[src.Content[i].Value] = src.Content[i+1]
+	}
+	var pairs [][2]*yaml.Node
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key, value := dst.Content[i], dst.Content[i+1]
+		next, ok := srcValues[key.Value]
+		if !ok {
+			continue
+		}
+		mergeNode(value, next)
+		pairs = append(pairs, [2]*yaml.Node{key, value})
+	}
+	last := -1
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key := src.Content[i]
+		pos := -1
+		for j, pair := range pairs {
+			if pair[0].Value == key.Value {
+				pos = j
+				break
+			}
+		}
+		if pos >= 0 {
+			last = pos
+			continue
+		}
+		last++
+		if neighbour := neighbourKey(pairs, last); neighbour != nil && strings.HasPrefix(neighbour.HeadComment, blankLineMarker) && key.HeadComment == "" {
+			key.HeadComment = blankLineMarker
+		}
+		pairs = append(pairs, [2]*yaml.Node{})
+		copy(pairs[last+1:], pairs[last:])
+		pairs[last] = [2]*yaml.Node{key, src.Content[i+1]}
+	}
+	content := make([]*yaml.Node, 0, len(pairs)*2)
+	for _, pair := range pairs {
+		content = append(content, pair[0], pair[1])
+	}
+	dst.Content = content
+}
+
+// neighbourKey returns the key a new entry inserted at pos will sit next to, so the new
+// entry can copy its blank-line separation.
+func neighbourKey(pairs [][2]*yaml.Node, pos int) *yaml.Node {
+	if pos < len(pairs) {
+		return pairs[pos][0]
+	}
+	if pos > 0 {
+		return pairs[pos-1][0]
+	}
+	return nil
+}
+
+// mergeSequence matches items by identity (repo alias or scalar value), falling back to
+// position for items without one, and follows the order of src.
+func mergeSequence(dst, src *yaml.Node) {
+	if len(dst.Content) == 0 {
+		dst.Style = src.Style
+	}
+	unused := map[string][]*yaml.Node{}
+	for _, item := range dst.Content {
+		if id := sequenceItemID(item); id != "" {
+			unused[id] = append(unused[id], item)
+		}
+	}
+	content := make([]*yaml.Node, 0, len(src.Content))
+	for i, item := range src.Content {
+		id := sequenceItemID(item)
+		if id != "" && len(unused[id]) > 0 {
+			match := unused[id][0]
+			unused[id] = unused[id][1:]
+			mergeNode(match, item)
+			content = append(content, match)
+			continue
+		}
+		if id == "" && i < len(dst.Content) && sequenceItemID(dst.Content[i]) == "" {
+			match := dst.Content[i]
+			mergeNode(match, item)
+			content = append(content, match)
+			continue
+		}
+		content = append(content, item)
+	}
+	dst.Content = content
+}
+
+func sequenceItemID(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return "value:" + node.Value
+	case yaml.MappingNode:
+		if alias := scalarValue(mappingValue(node, "alias")); alias != "" {
+			return "alias:" + alias
+		}
+	}
+	return ""
+}
+
+func mergeScalar(dst, src *yaml.Node) {
+	if dst.Value == src.Value && dst.ShortTag() == src.ShortTag() {
+		return
+	}
+	dst.Value = src.Value
+	dst.Tag = src.Tag
+	if dst.Style != yaml.SingleQuotedStyle && dst.Style != yaml.DoubleQuotedStyle {
+		dst.Style = src.Style
+	}
+}
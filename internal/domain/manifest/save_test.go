@@ -0,0 +1,123 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const commentedManifest = `# team workspaces
+version: 1
+
+presets:
+  # default set
+  web:
+    repos:
+      - git@github.com:org/web.git # frontend
+
+workspaces:
+  # keep this one around
+  WS-B:
+    description: second
+    repos:
+      - alias: web
+        repo_key: github.com/org/web.git
+        branch: WS-B # long-lived
+
+  WS-A:
+    repos:
+      - alias: api
+        repo_key: github.com/org/api.git
+        branch: WS-A
+
+  WS-OLD:
+    repos: []
+`
+
+func TestSavePreservesCommentsAndOrder(t *testing.T) {
+	rootDir := t.TempDir()
+	if err := os.WriteFile(Path(rootDir), []byte(commentedManifest), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	file, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	delete(file.Workspaces, "WS-OLD")
+	ws := file.Workspaces["WS-B"]
+	ws.Repos[0].Branch = "feature/x"
+	file.Workspaces["WS-B"] = ws
+	file.Workspaces["WS-C"] = Workspace{Repos: []Repo{{Alias: "api", RepoKey: "github.com/org/api.git", Branch: "WS-C"}}}
+	file.Presets["api"] = Preset{Repos: []string{"git@github.com:org/api.git"}}
+
+	if err := Save(rootDir, file); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, err := os.ReadFile(Path(rootDir))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	text := string(data)
+	for _, want := range []string{
+		"# team workspaces\nversion: 1\n\npresets:",
+		"# default set",
+		"web.git # frontend",
+		"# keep this one around\n  WS-B:",
+		"branch: feature/x # long-lived",
+		"\n\n  WS-A:",
+		"WS-C:",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in saved manifest:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "WS-OLD") || strings.Contains(text, blankLineMarker) {
+		t.Fatalf("unexpected content in saved manifest:\n%s", text)
+	}
+	if strings.Index(text, "WS-B:") > strings.Index(text, "WS-A:") {
+		t.Fatalf("existing key order should be kept:\n%s", text)
+	}
+
+	info, err := os.Stat(Path(rootDir))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o644 {
+		t.Fatalf("file mode should be kept, got %v", info.Mode().Perm())
+	}
+	reloaded, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if len(reloaded.Workspaces) != 3 || reloaded.Workspaces["WS-B"].Repos[0].Branch != "feature/x" || len(reloaded.Presets) != 2 {
+		t.Fatalf("unexpected reloaded manifest: %+v", reloaded)
+	}
+}
+
+func TestSaveReplacesUnparsableManifest(t *testing.T) {
+	rootDir := t.TempDir()
+	if err := os.WriteFile(Path(rootDir), []byte("workspaces: [\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	file := File{Version: 1, Workspaces: map[string]Workspace{"WS-1": {Repos: []Repo{}}}}
+	if err := Save(rootDir, file); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	reloaded, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, ok := reloaded.Workspaces["WS-1"]; !ok {
+		t.Fatalf("expected WS-1 in manifest: %+v", reloaded)
+	}
+	entries, err := os.ReadDir(filepath.Dir(Path(rootDir)))
+	if err != nil {
+		t.Fatalf("readdir: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp") {
+			t.Fatalf("temp file left behind: %s", entry.Name())
+		}
+	}
+}
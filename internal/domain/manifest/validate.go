@@ -187,6 +187,8 @@ func validateWorkspaceEntry(ctx context.Context, workspaceID string, node *yaml.
 		}
 	}
 
+	issues = append(issues, validateHooks(fmt.Sprintf("workspaces.%s.hooks", workspaceID), mappingValue(node, "hooks"))...)
+
 	reposNode := mappingValue(node, "repos")
 	if reposNode == nil {
 		issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("workspaces.%s.repos", workspaceID), Message: "missing required field"})
@@ -300,15 +302,16 @@ func validatePresetEntry(name string, node *yaml.Node) []ValidationIssue {
 	if node == nil || node.Kind != yaml.MappingNode {
 		return []ValidationIssue{{Ref: refPrefix, Message: "invalid value (preset entry must be a mapping)"}}
 	}
+	issues := validateHooks(refPrefix+".hooks", mappingValue(node, "hooks"))
 	reposNode := mappingValue(node, "repos")
 	if reposNode == nil {
-		return []ValidationIssue{{Ref: refPrefix + ".repos", Message: "missing or empty"}}
+		return append(issues, ValidationIssue{Ref: refPrefix + ".repos", Message: "missing or empty"})
 	}
 	if reposNode.Kind != yaml.SequenceNode {
-		return []ValidationIssue{{Ref: refPrefix + ".repos", Message: "invalid value (must be a list)"}}
+		return append(issues, ValidationIssue{Ref: refPrefix + ".repos", Message: "invalid value (must be a list)"})
 	}
 
-	var issues []ValidationIssue
+	hookIssues := len(issues)
 	var foundRepo bool
 	for i, entry := range reposNode.Content {
 		repoSpec, ok := presetRepoFromNode(entry)
@@ -326,12 +329,43 @@ func validatePresetEntry(name string, node *yaml.Node) []ValidationIssue {
 			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("%s.repos[%d]", refPrefix, i), Message: err.Error()})
 		}
 	}
-	if !foundRepo && len(issues) == 0 {
+	if !foundRepo && len(issues) == hookIssues {
 		issues = append(issues, ValidationIssue{Ref: refPrefix + ".repos", Message: "missing or empty"})
 	}
 	return issues
 }
 
+// validateHooks checks a hooks mapping: known events, each a list of non-empty commands.
+func validateHooks(ref string, node *yaml.Node) []ValidationIssue {
+	if node == nil {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return []ValidationIssue{{Ref: ref, Message: "invalid value (must be a mapping)"}}
+	}
+	var issues []ValidationIssue
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		event := strings.TrimSpace(nodeStringValue(node.Content[i]))
+		commands := node.Content[i+1]
+		switch event {
+		case "post_create", "pre_remove", "post_apply":
+		default:
+			issues = append(issues, ValidationIssue{Ref: ref, Message: fmt.Sprintf("unknown hook: %s (supported: post_create, pre_remove, post_apply)", event)})
+			continue
+		}
+		if commands == nil || commands.Kind != yaml.SequenceNode {
+			issues = append(issues, ValidationIssue{Ref: ref + "." + event, Message: "invalid value (must be a list of commands)"})
+			continue
+		}
+		for j, command := range commands.Content {
+			if command == nil || command.Kind != yaml.ScalarNode || strings.TrimSpace(command.Value) == "" {
+				issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("%s.%s[%d]", ref, event, j), Message: "invalid value (must be a non-empty command)"})
+			}
+		}
+	}
+	return issues
+}
+
 func presetRepoFromNode(node *yaml.Node) (string, bool) {
 	if node == nil {
 		return "", false
@@ -144,3 +144,65 @@ workspaces:
 		t.Fatalf("expected missing preset issue, got: %+v", result.Issues)
 	}
 }
+
+func TestValidate_Hooks(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	content := `
+version: 1
+presets:
+  webapp:
+    repos:
+      - git@github.com:org/api.git
+    hooks:
+      post_create:
+        - cp .env.example .env
+      on_merge:
+        - echo nope
+workspaces:
+  PROJ-4:
+    hooks:
+      post_apply: npm ci
+      pre_remove:
+        - ""
+    repos: []
+`
+	if err := os.WriteFile(filepath.Join(rootDir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	result, err := Validate(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	got := map[string]bool{}
+	for _, issue := range result.Issues {
+		got[issue.Ref] = true
+	}
+	for _, ref := range []string{"presets.webapp.hooks", "workspaces.PROJ-4.hooks.post_apply", "workspaces.PROJ-4.hooks.pre_remove[0]"} {
+		if !got[ref] {
+			t.Fatalf("expected issue for %s, got: %+v", ref, result.Issues)
+		}
+	}
+	if len(result.Issues) != 3 {
+		t.Fatalf("expected 3 issues, got: %+v", result.Issues)
+	}
+
+	file, err := Parse([]byte(`
+presets:
+  webapp:
+    repos:
+      - git@github.com:org/api.git
+    hooks:
+      post_create:
+        - cp .env.example .env
+workspaces: {}
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	hooks := WorkspaceHooks(file, Workspace{PresetName: "webapp", Hooks: Hooks{PostCreate: []string{"npm ci"}}})
+	if want := []string{"cp .env.example .env", "npm ci"}; strings.Join(hooks.PostCreate, "|") != strings.Join(want, "|") {
+		t.Fatalf("preset hooks should run before workspace hooks, got %v", hooks.PostCreate)
+	}
+}
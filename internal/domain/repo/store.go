@@ -36,7 +36,7 @@ func Get(ctx context.Context, rootDir string, repo string) (Store, error) {
 		if err := os.MkdirAll(filepath.Dir(storePath), 0o750); err != nil {
 			return Store{}, fmt.Errorf("create repo store dir: %w", err)
 		}
-		gitcmd.Logf("git clone --bare %s %s", remoteURL, storePath)
+		gitcmd.Logf(ctx, "git clone --bare %s %s", remoteURL, storePath)
 		if _, err := gitcmd.Run(ctx, []string{"clone", "--bare", remoteURL, storePath}, gitcmd.Options{}); err != nil {
 			return Store{}, err
 		}
@@ -149,7 +149,7 @@ func ensureDefaultBranch(ctx context.Context, storePath string, fetch bool, log
 
 	if fetch {
 		if log {
-			gitcmd.Logf("git fetch --prune")
+			gitcmd.Logf(ctx, "git fetch --prune")
 		}
 		if _, err := gitcmd.Run(ctx, []string{"fetch", "--prune"}, gitcmd.Options{Dir: storePath}); err != nil {
 			return "", err
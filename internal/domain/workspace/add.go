@@ -36,7 +36,7 @@ func AddWithBranch(ctx context.Context, rootDir, workspaceID, repoSpec, alias, b
 	}
 
 	if branchExists {
-		gitcmd.Logf("git worktree add %s %s", prep.worktreePath, branch)
+		gitcmd.Logf(ctx, "git worktree add %s %s", prep.worktreePath, branch)
 		if err := worktreeAddWithRetry(ctx, prep.store.StorePath, func() error {
 			return gitcmd.WorktreeAddExistingBranch(ctx, prep.store.StorePath, prep.worktreePath, branch)
 		}); err != nil {
@@ -49,14 +49,14 @@ func AddWithBranch(ctx context.Context, rootDir, workspaceID, repoSpec, alias, b
 			return Repo{}, err
 		}
 		if remoteExists {
-			gitcmd.Logf("git worktree add -b %s --track %s %s", branch, prep.worktreePath, remoteName)
+			gitcmd.Logf(ctx, "git worktree add -b %s --track %s %s", branch, prep.worktreePath, remoteName)
 			if err := worktreeAddWithRetry(ctx, prep.store.StorePath, func() error {
 				return gitcmd.WorktreeAddTrackingBranch(ctx, prep.store.StorePath, branch, prep.worktreePath, remoteName)
 			}); err != nil {
 				return Repo{}, err
 			}
 		} else {
-			gitcmd.Logf("git worktree add -b %s %s %s", branch, prep.worktreePath, baseRef)
+			gitcmd.Logf(ctx, "git worktree add -b %s %s %s", branch, prep.worktreePath, baseRef)
 			if err := worktreeAddWithRetry(ctx, prep.store.StorePath, func() error {
 				return gitcmd.WorktreeAddNewBranch(ctx, prep.store.StorePath, branch, prep.worktreePath, baseRef)
 			}); err != nil {
@@ -101,7 +101,7 @@ func AddWithTrackingBranch(ctx context.Context, rootDir, workspaceID, repoSpec,
 		return Repo{}, fmt.Errorf("ref not found: %s", remoteRef)
 	}
 
-	gitcmd.Logf("git worktree add -b %s --track %s %s", branch, prep.worktreePath, remoteName)
+	gitcmd.Logf(ctx, "git worktree add -b %s --track %s %s", branch, prep.worktreePath, remoteName)
 	if err := worktreeAddWithRetry(ctx, prep.store.StorePath, func() error {
 		return gitcmd.WorktreeAddTrackingBranch(ctx, prep.store.StorePath, branch, prep.worktreePath, remoteName)
 	}); err != nil {
@@ -120,6 +120,38 @@ func AddWithTrackingBranch(ctx context.Context, rootDir, workspaceID, repoSpec,
 	return repoEntry, nil
 }
 
+// AddWithStartPoint adds a worktree on a new branch created at startPoint, a commit-ish
+// of the repo store. Unlike AddWithBranch, existing local or origin branches of the same
+// name are not used.
+func AddWithStartPoint(ctx context.Context, rootDir, workspaceID, repoSpec, alias, branch, startPoint string) (Repo, error) {
+	if err := validateBranchName(ctx, branch); err != nil {
+		return Repo{}, err
+	}
+	if strings.TrimSpace(startPoint) == "" {
+		return Repo{}, fmt.Errorf("start point is required")
+	}
+	prep, err := prepareAdd(ctx, rootDir, workspaceID, repoSpec, alias, false)
+	if err != nil {
+		return Repo{}, err
+	}
+
+	gitcmd.Logf(ctx, "git worktree add -b %s %s %s", branch, prep.worktreePath, startPoint)
+	if err := worktreeAddWithRetry(ctx, prep.store.StorePath, func() error {
+		return gitcmd.WorktreeAddNewBranch(ctx, prep.store.StorePath, branch, prep.worktreePath, startPoint)
+	}); err != nil {
+		return Repo{}, err
+	}
+
+	return Repo{
+		Alias:        prep.alias,
+		RepoSpec:     repoSpec,
+		RepoKey:      prep.spec.RepoKey,
+		StorePath:    prep.store.StorePath,
+		WorktreePath: prep.worktreePath,
+		Branch:       branch,
+	}, nil
+}
+
 type addPrep struct {
 	spec         repo.Spec
 	alias        string
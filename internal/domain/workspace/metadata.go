@@ -29,6 +29,46 @@ type Metadata struct {
 	PresetName  string `json:"preset_name,omitempty"`
 	SourceURL   string `json:"source_url,omitempty"`
 	BaseBranch  string `json:"base_branch,omitempty"`
+	// PullRequests records the PRs opened by `gion pr create`, one per repo alias.
+	PullRequests []PullRequest `json:"pull_requests,omitempty"`
+}
+
+type PullRequest struct {
+	Alias string `json:"alias"`
+	URL   string `json:"url"`
+}
+
+// PullRequestURL returns the recorded PR URL for alias ("" when none).
+func (m Metadata) PullRequestURL(alias string) string {
+	for _, pr := range m.PullRequests {
+		if pr.Alias == alias {
+			return pr.URL
+		}
+	}
+	return ""
+}
+
+// SetPullRequest records url as the PR of alias, replacing a previous entry.
+func (m *Metadata) SetPullRequest(alias, url string) {
+	for i := range m.PullRequests {
+		if m.PullRequests[i].Alias == alias {
+			m.PullRequests[i].URL = url
+			return
+		}
+	}
+	m.PullRequests = append(m.PullRequests, PullRequest{Alias: alias, URL: url})
+}
+
+// RenamePullRequest moves the PR recorded for from to alias to (a repo alias rename). It
+// reports whether there was one.
+func (m *Metadata) RenamePullRequest(from, to string) bool {
+	for i := range m.PullRequests {
+		if m.PullRequests[i].Alias == from {
+			m.PullRequests[i].Alias = to
+			return true
+		}
+	}
+	return false
 }
 
 func LoadMetadata(wsDir string) (Metadata, error) {
@@ -55,7 +95,11 @@ func SaveMetadata(wsDir string, meta Metadata) error {
 		return fmt.Errorf("workspace dir is required")
 	}
 	meta = normalizeMetadata(meta)
-	if meta == (Metadata{}) {
+	if isEmptyMetadata(meta) {
+		// Nothing to record; drop a previous file so cleared fields stay cleared.
+		if err := os.Remove(metadataPath(wsDir)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove metadata: %w", err)
+		}
 		return nil
 	}
 	if err := validateMetadata(meta); err != nil {
@@ -93,9 +137,23 @@ func normalizeMetadata(meta Metadata) Metadata {
 	meta.PresetName = strings.TrimSpace(meta.PresetName)
 	meta.SourceURL = strings.TrimSpace(meta.SourceURL)
 	meta.BaseBranch = strings.TrimSpace(meta.BaseBranch)
+	var prs []PullRequest
+	for _, pr := range meta.PullRequests {
+		pr.Alias = strings.TrimSpace(pr.Alias)
+		pr.URL = strings.TrimSpace(pr.URL)
+		if pr.Alias == "" || pr.URL == "" {
+			continue
+		}
+		prs = append(prs, pr)
+	}
+	meta.PullRequests = prs
 	return meta
 }
 
+func isEmptyMetadata(meta Metadata) bool {
+	return meta.Description == "" && meta.Mode == "" && meta.PresetName == "" && meta.SourceURL == "" && meta.BaseBranch == "" && len(meta.PullRequests) == 0
+}
+
 func validateMetadata(meta Metadata) error {
 	if meta.Mode != "" {
 		switch meta.Mode {
@@ -113,6 +171,12 @@ func validateMetadata(meta Metadata) error {
 			return fmt.Errorf("invalid metadata source_url: %s", meta.SourceURL)
 		}
 	}
+	for _, pr := range meta.PullRequests {
+		parsed, err := url.ParseRequestURI(pr.URL)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("invalid metadata pull request url for %s: %s", pr.Alias, pr.URL)
+		}
+	}
 	if meta.BaseBranch != "" {
 		if strings.ContainsAny(meta.BaseBranch, " \t\r\n") {
 			return fmt.Errorf("invalid metadata base_branch: %s", meta.BaseBranch)
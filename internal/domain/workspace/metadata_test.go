@@ -80,3 +80,29 @@ func TestSaveMetadataBaseBranchValidation(t *testing.T) {
 		t.Fatalf("expected error for base_branch origin/ with empty branch")
 	}
 }
+
+func TestSaveMetadataPullRequests(t *testing.T) {
+	wsDir := t.TempDir()
+
+	meta := Metadata{Description: "desc"}
+	meta.SetPullRequest("api", "https://example.com/org/api/pull/1")
+	meta.SetPullRequest("web", "https://example.com/org/web/pull/2")
+	meta.SetPullRequest("api", "https://example.com/org/api/pull/3")
+	if err := SaveMetadata(wsDir, meta); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+	loaded, err := LoadMetadata(wsDir)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if len(loaded.PullRequests) != 2 || loaded.PullRequestURL("api") != "https://example.com/org/api/pull/3" || loaded.PullRequestURL("web") != "https://example.com/org/web/pull/2" {
+		t.Fatalf("unexpected pull requests: %+v", loaded.PullRequests)
+	}
+	if loaded.PullRequestURL("missing") != "" {
+		t.Fatalf("expected no URL for unknown alias")
+	}
+
+	if err := SaveMetadata(wsDir, Metadata{PullRequests: []PullRequest{{Alias: "api", URL: "not a url"}}}); err == nil {
+		t.Fatalf("expected error for invalid pull request url")
+	}
+}
@@ -0,0 +1,116 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// Move renames a workspace. Worktrees are moved with `git worktree move` so the repo
+// stores keep tracking them; uncommitted changes, untracked files, and stashes are kept.
+// Everything else in the workspace directory (e.g. .gion) is moved as is.
+// Move can be re-run after an interruption: what already reached the new directory is
+// left in place.
+func Move(ctx context.Context, rootDir, fromID, toID string) error {
+	if rootDir == "" {
+		return fmt.Errorf("root directory is required")
+	}
+	if err := validateWorkspaceID(ctx, fromID); err != nil {
+		return err
+	}
+	if err := validateWorkspaceID(ctx, toID); err != nil {
+		return err
+	}
+	if fromID == toID {
+		return nil
+	}
+
+	fromDir := WorkspaceDir(rootDir, fromID)
+	toDir := WorkspaceDir(rootDir, toID)
+	fromExists, err := paths.DirExists(fromDir)
+	if err != nil {
+		return err
+	}
+	if !fromExists {
+		if toExists, err := paths.DirExists(toDir); err != nil || toExists {
+			return err
+		}
+		return fmt.Errorf("workspace does not exist: %s", fromDir)
+	}
+	if err := os.MkdirAll(toDir, 0o750); err != nil {
+		return fmt.Errorf("create workspace dir: %w", err)
+	}
+
+	repos, _, err := ScanRepos(ctx, fromDir)
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if repo.StorePath == "" {
+			continue
+		}
+		dest := filepath.Join(toDir, repo.Alias)
+		if _, err := os.Lstat(dest); err == nil {
+			return fmt.Errorf("move worktree %q: %s already exists", repo.Alias, dest)
+		}
+		gitcmd.Logf(ctx, "git worktree move %s %s", repo.WorktreePath, dest)
+		if err := gitcmd.WorktreeMove(ctx, repo.StorePath, repo.WorktreePath, dest); err != nil {
+			return fmt.Errorf("move worktree %q: %w", repo.Alias, err)
+		}
+	}
+
+	entries, err := os.ReadDir(fromDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		dest := filepath.Join(toDir, entry.Name())
+		if _, err := os.Lstat(dest); err == nil {
+			return fmt.Errorf("move %s: %s already exists", entry.Name(), dest)
+		}
+		if err := os.Rename(filepath.Join(fromDir, entry.Name()), dest); err != nil {
+			return fmt.Errorf("move %s: %w", entry.Name(), err)
+		}
+	}
+	if err := os.Remove(fromDir); err != nil {
+		return fmt.Errorf("remove workspace dir: %w", err)
+	}
+	return nil
+}
+
+// MoveRepo renames the alias of a repo in a workspace by moving its worktree with
+// `git worktree move`; the branch and local changes are kept. Like Move, it can be
+// re-run: a worktree already at toAlias (and gone from fromAlias) is left in place.
+func MoveRepo(ctx context.Context, rootDir, workspaceID, fromAlias, toAlias string) error {
+	if fromAlias == "" || toAlias == "" {
+		return fmt.Errorf("alias is required")
+	}
+	repos, _, err := ScanRepos(ctx, WorkspaceDir(rootDir, workspaceID))
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if repo.Alias != fromAlias {
+			continue
+		}
+		if repo.StorePath == "" {
+			return fmt.Errorf("missing store path for %s", fromAlias)
+		}
+		dest := WorktreePath(rootDir, workspaceID, toAlias)
+		if _, err := os.Lstat(dest); err == nil {
+			return fmt.Errorf("move worktree %q: %s already exists", fromAlias, dest)
+		}
+		gitcmd.Logf(ctx, "git worktree move %s %s", repo.WorktreePath, dest)
+		return gitcmd.WorktreeMove(ctx, repo.StorePath, repo.WorktreePath, dest)
+	}
+	for _, repo := range repos {
+		if repo.Alias == toAlias {
+			return nil
+		}
+	}
+	return fmt.Errorf("repo not found in workspace %s: %s", workspaceID, fromAlias)
+}
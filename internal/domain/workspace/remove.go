@@ -70,9 +70,9 @@ func RemoveWithOptions(ctx context.Context, rootDir, workspaceID string, opts Re
 		}
 		force := opts.AllowDirty
 		if force {
-			gitcmd.Logf("git worktree remove --force %s", repo.WorktreePath)
+			gitcmd.Logf(ctx, "git worktree remove --force %s", repo.WorktreePath)
 		} else {
-			gitcmd.Logf("git worktree remove %s", repo.WorktreePath)
+			gitcmd.Logf(ctx, "git worktree remove %s", repo.WorktreePath)
 		}
 		if err := gitcmd.WorktreeRemove(ctx, repo.StorePath, repo.WorktreePath, force); err != nil {
 			return fmt.Errorf("remove worktree %q: %w", repo.Alias, err)
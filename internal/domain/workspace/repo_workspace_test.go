@@ -348,6 +348,172 @@ func TestResolveBaseRefFallsBackWhenRemoteHeadRefMissing(t *testing.T) {
 	}
 }
 
+func TestMoveKeepsLocalChanges(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	repoSpec := "https://example.com/org/repo.git"
+	store, err := repo.Get(ctx, rootDir, repoSpec)
+	if err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := workspace.New(ctx, rootDir, "WS-1"); err != nil {
+		t.Fatalf("workspace new: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	if err := workspace.SaveMetadata(workspace.WorkspaceDir(rootDir, "WS-1"), workspace.Metadata{Description: "moved"}); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	if err := os.WriteFile(filepath.Join(worktreePath, "stashed.txt"), []byte("stash\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, worktreePath, "stash", "push", "--include-untracked")
+	if err := os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	if err := workspace.Move(ctx, rootDir, "WS-1", "WS-2"); err != nil {
+		t.Fatalf("workspace move: %v", err)
+	}
+	if _, err := os.Stat(workspace.WorkspaceDir(rootDir, "WS-1")); !os.IsNotExist(err) {
+		t.Fatalf("old workspace still exists: %v", err)
+	}
+	movedPath := workspace.WorktreePath(rootDir, "WS-2", "repo")
+	data, err := os.ReadFile(filepath.Join(movedPath, "README.md"))
+	if err != nil || string(data) != "dirty\n" {
+		t.Fatalf("expected local change to be kept, got %q (%v)", data, err)
+	}
+	if meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-2")); err != nil || meta.Description != "moved" {
+		t.Fatalf("expected metadata to be moved, got %+v (%v)", meta, err)
+	}
+	if got := revParse(t, movedPath, "refs/stash"); got == "" {
+		t.Fatalf("expected stash to be kept")
+	}
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd.Dir = store.StorePath
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git worktree list: %v", err)
+	}
+	if !strings.Contains(string(out), "worktree "+movedPath+"\n") {
+		t.Fatalf("expected store to track %s, got:\n%s", movedPath, out)
+	}
+}
+
+func TestMoveRepoKeepsLocalChangesAndCanBeRerun(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	repoSpec := "https://example.com/org/repo.git"
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := workspace.New(ctx, rootDir, "WS-1"); err != nil {
+		t.Fatalf("workspace new: %v", err)
+	}
+	if _, err := workspace.Add(ctx, rootDir, "WS-1", repoSpec, "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	if err := os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	if err := workspace.MoveRepo(ctx, rootDir, "WS-1", "repo", "app"); err != nil {
+		t.Fatalf("move repo: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(workspace.WorktreePath(rootDir, "WS-1", "app"), "README.md"))
+	if err != nil || string(data) != "dirty\n" {
+		t.Fatalf("expected local change to be kept after alias move, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(worktreePath); !os.IsNotExist(err) {
+		t.Fatalf("old alias still exists: %v", err)
+	}
+
+	// A resumed apply repeats the move after it already happened.
+	if err := workspace.MoveRepo(ctx, rootDir, "WS-1", "repo", "app"); err != nil {
+		t.Fatalf("re-run move repo: %v", err)
+	}
+	if err := workspace.MoveRepo(ctx, rootDir, "WS-1", "missing", "other"); err == nil {
+		t.Fatalf("expected error for an alias that is in neither place")
+	}
+}
+
 func runGit(t *testing.T, dir string, args ...string) {
 	t.Helper()
 	cmd := exec.Command("git", args...)
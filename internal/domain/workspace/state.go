@@ -13,6 +13,12 @@ const (
 	WorkspaceStateUnpushed WorkspaceStateKind = "unpushed"
 	WorkspaceStateDiverged WorkspaceStateKind = "diverged"
 	WorkspaceStateUnknown  WorkspaceStateKind = "unknown"
+	// WorkspaceStateUnpublished: a branch without upstream has commits on no origin branch.
+	WorkspaceStateUnpublished WorkspaceStateKind = "unpublished"
+	// WorkspaceStateStashed: stash entries were made on a workspace branch.
+	WorkspaceStateStashed WorkspaceStateKind = "stashed"
+	// WorkspaceStateInProgress: a rebase, merge, cherry-pick, or revert was left unfinished.
+	WorkspaceStateInProgress WorkspaceStateKind = "in-progress"
 )
 
 type RepoStateKind string
@@ -23,6 +29,12 @@ const (
 	RepoStateUnpushed RepoStateKind = "unpushed"
 	RepoStateDiverged RepoStateKind = "diverged"
 	RepoStateUnknown  RepoStateKind = "unknown"
+	// RepoStateUnpublished: no upstream, and commits that are on no origin branch.
+	RepoStateUnpublished RepoStateKind = "unpublished"
+	// RepoStateStashed: otherwise clean, but the branch has stash entries.
+	RepoStateStashed RepoStateKind = "stashed"
+	// RepoStateInProgress: a rebase, merge, cherry-pick, or revert was left unfinished.
+	RepoStateInProgress RepoStateKind = "in-progress"
 )
 
 type WorkspaceState struct {
@@ -42,8 +54,13 @@ type RepoState struct {
 	UnstagedCount  int
 	UntrackedCount int
 	UnmergedCount  int
-	Kind           RepoStateKind
-	Error          error
+	StashCount     int
+	Operation      string
+	// LocalCommitCount and BaseRef are only set for branches without upstream.
+	LocalCommitCount int
+	BaseRef          string
+	Kind             RepoStateKind
+	Error            error
 }
 
 func State(ctx context.Context, rootDir, workspaceID string) (WorkspaceState, error) {
@@ -69,21 +86,31 @@ func StateFromStatus(status StatusResult) WorkspaceState {
 
 func repoStateFromStatus(repo RepoStatus) RepoState {
 	state := RepoState{
-		Alias:          repo.Alias,
-		WorktreePath:   repo.WorktreePath,
-		Upstream:       repo.Upstream,
-		AheadCount:     repo.AheadCount,
-		BehindCount:    repo.BehindCount,
-		StagedCount:    repo.StagedCount,
-		UnstagedCount:  repo.UnstagedCount,
-		UntrackedCount: repo.UntrackedCount,
-		UnmergedCount:  repo.UnmergedCount,
-		Error:          repo.Error,
+		Alias:            repo.Alias,
+		WorktreePath:     repo.WorktreePath,
+		Upstream:         repo.Upstream,
+		AheadCount:       repo.AheadCount,
+		BehindCount:      repo.BehindCount,
+		StagedCount:      repo.StagedCount,
+		UnstagedCount:    repo.UnstagedCount,
+		UntrackedCount:   repo.UntrackedCount,
+		UnmergedCount:    repo.UnmergedCount,
+		StashCount:       repo.StashCount,
+		Operation:        repo.Operation,
+		LocalCommitCount: repo.LocalCommitCount,
+		BaseRef:          repo.BaseRef,
+		Error:            repo.Error,
 	}
 	if repo.Error != nil {
 		state.Kind = RepoStateUnknown
 		return state
 	}
+	// An unfinished rebase usually also means a detached HEAD and conflicts; report the
+	// cause rather than its symptoms.
+	if repo.Operation != "" {
+		state.Kind = RepoStateInProgress
+		return state
+	}
 	if repo.Dirty {
 		state.Kind = RepoStateDirty
 		return state
@@ -93,7 +120,16 @@ func repoStateFromStatus(repo RepoStatus) RepoState {
 		return state
 	}
 	if strings.TrimSpace(repo.Upstream) == "" {
-		state.Kind = RepoStateUnknown
+		switch {
+		case strings.TrimSpace(repo.BaseRef) == "":
+			state.Kind = RepoStateUnknown
+		case repo.LocalCommitCount > 0:
+			state.Kind = RepoStateUnpublished
+		case repo.StashCount > 0:
+			state.Kind = RepoStateStashed
+		default:
+			state.Kind = RepoStateClean
+		}
 		return state
 	}
 	if repo.AheadCount > 0 && repo.BehindCount > 0 {
@@ -104,6 +140,10 @@ func repoStateFromStatus(repo RepoStatus) RepoState {
 		state.Kind = RepoStateUnpushed
 		return state
 	}
+	if repo.StashCount > 0 {
+		state.Kind = RepoStateStashed
+		return state
+	}
 	if repo.BehindCount > 0 {
 		state.Kind = RepoStateClean
 		return state
@@ -113,31 +153,46 @@ func repoStateFromStatus(repo RepoStatus) RepoState {
 }
 
 func aggregateWorkspaceState(repos []RepoState) WorkspaceStateKind {
+	hasInProgress := false
 	hasDirty := false
 	hasUnknown := false
 	hasDiverged := false
+	hasUnpublished := false
 	hasUnpushed := false
+	hasStashed := false
 	for _, repo := range repos {
 		switch repo.Kind {
+		case RepoStateInProgress:
+			hasInProgress = true
 		case RepoStateDirty:
 			hasDirty = true
 		case RepoStateUnknown:
 			hasUnknown = true
 		case RepoStateDiverged:
 			hasDiverged = true
+		case RepoStateUnpublished:
+			hasUnpublished = true
 		case RepoStateUnpushed:
 			hasUnpushed = true
+		case RepoStateStashed:
+			hasStashed = true
 		}
 	}
 	switch {
+	case hasInProgress:
+		return WorkspaceStateInProgress
 	case hasDirty:
 		return WorkspaceStateDirty
 	case hasUnknown:
 		return WorkspaceStateUnknown
 	case hasDiverged:
 		return WorkspaceStateDiverged
+	case hasUnpublished:
+		return WorkspaceStateUnpublished
 	case hasUnpushed:
 		return WorkspaceStateUnpushed
+	case hasStashed:
+		return WorkspaceStateStashed
 	default:
 		return WorkspaceStateClean
 	}
@@ -145,7 +200,8 @@ func aggregateWorkspaceState(repos []RepoState) WorkspaceStateKind {
 
 func RequiresRemoveConfirmation(kind WorkspaceStateKind) bool {
 	switch kind {
-	case WorkspaceStateDirty, WorkspaceStateUnpushed, WorkspaceStateDiverged, WorkspaceStateUnknown:
+	case WorkspaceStateDirty, WorkspaceStateUnpushed, WorkspaceStateDiverged, WorkspaceStateUnknown,
+		WorkspaceStateUnpublished, WorkspaceStateStashed, WorkspaceStateInProgress:
 		return true
 	default:
 		return false
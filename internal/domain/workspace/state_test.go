@@ -75,6 +75,47 @@ func TestStateFromStatus(t *testing.T) {
 			},
 			want: WorkspaceStateUnknown,
 		},
+		{
+			name: "upstream_missing_without_local_commits_is_clean",
+			in: StatusResult{
+				WorkspaceID: "ws-new-branch",
+				Repos: []RepoStatus{
+					{Alias: "app", BaseRef: "origin/main"},
+				},
+			},
+			want: WorkspaceStateClean,
+		},
+		{
+			name: "upstream_missing_with_local_commits_is_unpublished",
+			in: StatusResult{
+				WorkspaceID: "ws-unpublished",
+				Repos: []RepoStatus{
+					{Alias: "app", BaseRef: "origin/main", LocalCommitCount: 2},
+				},
+			},
+			want: WorkspaceStateUnpublished,
+		},
+		{
+			name: "stash_only_is_stashed",
+			in: StatusResult{
+				WorkspaceID: "ws-stashed",
+				Repos: []RepoStatus{
+					{Alias: "app", Upstream: "origin/main", StashCount: 1},
+				},
+			},
+			want: WorkspaceStateStashed,
+		},
+		{
+			name: "rebase_in_progress_overrides_dirty",
+			in: StatusResult{
+				WorkspaceID: "ws-rebase",
+				Repos: []RepoStatus{
+					{Alias: "app", Detached: true, Dirty: true, UnmergedCount: 1, Operation: "rebase"},
+					{Alias: "api", Dirty: true},
+				},
+			},
+			want: WorkspaceStateInProgress,
+		},
 		{
 			name: "detached_is_unknown",
 			in: StatusResult{
@@ -129,7 +170,7 @@ func TestRequiresRemoveConfirmation(t *testing.T) {
 	if !RequiresRemoveConfirmation(WorkspaceStateDirty) {
 		t.Fatalf("WorkspaceStateDirty should require confirmation")
 	}
-	for _, kind := range []WorkspaceStateKind{WorkspaceStateUnpushed, WorkspaceStateDiverged, WorkspaceStateUnknown} {
+	for _, kind := range []WorkspaceStateKind{WorkspaceStateUnpushed, WorkspaceStateDiverged, WorkspaceStateUnknown, WorkspaceStateUnpublished, WorkspaceStateStashed, WorkspaceStateInProgress} {
 		if !RequiresRemoveConfirmation(kind) {
 			t.Fatalf("%s should require confirmation", kind)
 		}
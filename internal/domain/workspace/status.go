@@ -3,6 +3,8 @@ package workspace
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -10,6 +12,9 @@ import (
 	"github.com/tasuku43/gion/internal/infra/paths"
 )
 
+// unpublishedRemote is the remote whose branches count as published.
+const unpublishedRemote = "origin"
+
 type StatusResult struct {
 	WorkspaceID string
 	Repos       []RepoStatus
@@ -30,10 +35,20 @@ type RepoStatus struct {
 	UnmergedCount  int
 	AheadCount     int
 	BehindCount    int
-	WorktreePath   string
-	RawStatus      string
-	ChangedFiles   []string
-	Error          error
+	// StashCount counts the stash entries made on Branch. The stash lives in the repo
+	// store, so git status does not report it.
+	StashCount int
+	// Operation is the git operation left in progress (rebase, merge, cherry-pick, revert).
+	Operation string
+	// LocalCommitCount counts the commits of a branch without upstream that are not on
+	// BaseRef: "origin" (any origin branch), or when origin has no branches the workspace
+	// base_branch or the store's default branch. BaseRef is empty when it was not checked.
+	LocalCommitCount int
+	BaseRef          string
+	WorktreePath     string
+	RawStatus        string
+	ChangedFiles     []string
+	Error            error
 }
 
 func Status(ctx context.Context, rootDir, workspaceID string) (StatusResult, error) {
@@ -58,6 +73,8 @@ func Status(ctx context.Context, rootDir, workspaceID string) (StatusResult, err
 	if err != nil {
 		return StatusResult{}, err
 	}
+	// base_branch only matters as a fallback; a broken metadata file must not fail status.
+	meta, _ := LoadMetadata(wsDir)
 
 	result := StatusResult{
 		WorkspaceID: workspaceID,
@@ -80,6 +97,9 @@ func Status(ctx context.Context, rootDir, workspaceID string) (StatusResult, err
 		repoStatus.RawStatus = statusOut
 		repoStatus.Branch, repoStatus.Upstream, repoStatus.Head, repoStatus.Detached, repoStatus.HeadMissing, repoStatus.Dirty, repoStatus.UntrackedCount, repoStatus.StagedCount, repoStatus.UnstagedCount, repoStatus.UnmergedCount, repoStatus.AheadCount, repoStatus.BehindCount = parseStatusPorcelainV2(statusOut, repoStatus.Branch)
 		repoStatus.ChangedFiles = parseChangedFilesPorcelainV2(statusOut)
+		if err := collectHiddenState(ctx, repo.StorePath, meta.BaseBranch, &repoStatus); err != nil {
+			repoStatus.Error = err
+		}
 		result.Repos = append(result.Repos, repoStatus)
 	}
 
@@ -90,6 +110,102 @@ func gitStatusPorcelain(ctx context.Context, worktreePath string) (string, error
 	return gitcmd.StatusPorcelainV2(ctx, worktreePath)
 }
 
+// collectHiddenState fills in the local work git status does not show: stash entries,
+// an interrupted rebase/merge/cherry-pick/revert, and commits of a branch without upstream
+// that were never pushed.
+func collectHiddenState(ctx context.Context, storePath, baseBranch string, repoStatus *RepoStatus) error {
+	gitDir, err := gitcmd.RevParse(ctx, repoStatus.WorktreePath, "--absolute-git-dir")
+	if err != nil {
+		return err
+	}
+	repoStatus.Operation = detectOperation(gitDir)
+
+	if !repoStatus.Detached && !repoStatus.HeadMissing && repoStatus.Branch != "" {
+		subjects, err := gitcmd.StashSubjects(ctx, repoStatus.WorktreePath)
+		if err != nil {
+			return err
+		}
+		repoStatus.StashCount = CountBranchStashes(subjects, repoStatus.Branch)
+	}
+
+	if repoStatus.Detached || repoStatus.HeadMissing || strings.TrimSpace(repoStatus.Upstream) != "" {
+		return nil
+	}
+	// Without an upstream, ahead/behind says nothing; count the commits that are on no
+	// origin branch instead, so a branch pushed without -u is not reported. When origin
+	// has no branches, compare with the base branch; when it cannot be resolved the repo
+	// stays unknown.
+	baseRef := unpublishedRemote
+	exclude := "--remotes=" + unpublishedRemote
+	remoteHeads, err := gitcmd.RevParse(ctx, repoStatus.WorktreePath, exclude)
+	if err != nil {
+		return err
+	}
+	if remoteHeads == "" {
+		baseRef = fallbackBaseRef(ctx, storePath, repoStatus.WorktreePath, baseBranch)
+		if baseRef == "" {
+			return nil
+		}
+		exclude = baseRef
+	}
+	count, err := gitcmd.RevListCount(ctx, repoStatus.WorktreePath, "HEAD", "--not", exclude)
+	if err != nil {
+		return err
+	}
+	repoStatus.BaseRef = baseRef
+	repoStatus.LocalCommitCount = count
+	return nil
+}
+
+// fallbackBaseRef returns the workspace base_branch when it resolves, else the store's
+// default branch, else "".
+func fallbackBaseRef(ctx context.Context, storePath, worktreePath, baseBranch string) string {
+	if baseBranch = strings.TrimSpace(baseBranch); baseBranch != "" {
+		if _, err := gitcmd.RevParse(ctx, worktreePath, "--verify", "--quiet", baseBranch+"^{commit}"); err == nil {
+			return baseBranch
+		}
+	}
+	if storePath == "" {
+		return ""
+	}
+	baseRef, err := resolveBaseRef(ctx, storePath)
+	if err != nil {
+		return ""
+	}
+	return baseRef
+}
+
+// detectOperation reports the operation whose state files are in gitDir.
+func detectOperation(gitDir string) string {
+	for _, marker := range []struct {
+		name      string
+		operation string
+	}{
+		{name: "rebase-merge", operation: "rebase"},
+		{name: "rebase-apply", operation: "rebase"},
+		{name: "MERGE_HEAD", operation: "merge"},
+		{name: "CHERRY_PICK_HEAD", operation: "cherry-pick"},
+		{name: "REVERT_HEAD", operation: "revert"},
+	} {
+		if _, err := os.Stat(filepath.Join(gitDir, marker.name)); err == nil {
+			return marker.operation
+		}
+	}
+	return ""
+}
+
+// CountBranchStashes counts the stash entries git made on branch ("WIP on <branch>: ..."
+// or "On <branch>: ..." for `git stash push -m`).
+func CountBranchStashes(subjects []string, branch string) int {
+	count := 0
+	for _, subject := range subjects {
+		if strings.HasPrefix(subject, "WIP on "+branch+": ") || strings.HasPrefix(subject, "On "+branch+": ") {
+			count++
+		}
+	}
+	return count
+}
+
 func parseStatusPorcelainV2(output, fallbackBranch string) (string, string, string, bool, bool, bool, int, int, int, int, int, int) {
 	branch := fallbackBranch
 	var upstream string
@@ -1,6 +1,10 @@
 package workspace
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestParseStatusPorcelainV2Counts(t *testing.T) {
 	out := "# branch.oid 94a67ef\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +2 -1\n1 .M N... 100644 100644 100644 abcdef0 abcdef0 file.txt\n? new.txt\nu UU N... 100644 100644 100644 abcdef0 abcdef0 abcdef0 conflict.txt\n"
@@ -62,3 +66,39 @@ func TestParseChangedFilesPorcelainV2(t *testing.T) {
 		}
 	}
 }
+
+func TestCountBranchStashes(t *testing.T) {
+	subjects := []string{
+		"WIP on WS-1: 94a67ef init",
+		"On WS-1: wip",
+		"WIP on WS-10: 94a67ef init",
+		"On main: other",
+	}
+	if got := CountBranchStashes(subjects, "WS-1"); got != 2 {
+		t.Fatalf("CountBranchStashes() = %d, want 2", got)
+	}
+	if got := CountBranchStashes(subjects, "feature"); got != 0 {
+		t.Fatalf("CountBranchStashes() = %d, want 0", got)
+	}
+}
+
+func TestDetectOperation(t *testing.T) {
+	gitDir := t.TempDir()
+	if got := detectOperation(gitDir); got != "" {
+		t.Fatalf("detectOperation() = %q, want empty", got)
+	}
+	if err := os.Mkdir(filepath.Join(gitDir, "rebase-merge"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if got := detectOperation(gitDir); got != "rebase" {
+		t.Fatalf("detectOperation() = %q, want rebase", got)
+	}
+
+	gitDir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(gitDir, "CHERRY_PICK_HEAD"), []byte("94a67ef\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := detectOperation(gitDir); got != "cherry-pick" {
+		t.Fatalf("detectOperation() = %q, want cherry-pick", got)
+	}
+}
@@ -0,0 +1,20 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Apply applies the patch file at patchPath to the worktree at dir (the index is not
+// touched, so added files come back untracked).
+func Apply(ctx context.Context, dir, patchPath string) error {
+	res, err := Run(ctx, []string{"apply", "--binary", patchPath}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git apply failed: %w: %s", err, errorLine(res.Stderr))
+		}
+		return fmt.Errorf("git apply failed: %w", err)
+	}
+	return nil
+}
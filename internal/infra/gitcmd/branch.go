@@ -21,3 +21,26 @@ func BranchMove(ctx context.Context, dir, fromBranch, toBranch string) error {
 	_, err := Run(ctx, []string{"branch", "-m", from, to}, Options{Dir: dir, ShowOutput: true})
 	return err
 }
+
+func BranchDelete(ctx context.Context, dir, branch string) error {
+	name := strings.TrimSpace(branch)
+	if name == "" {
+		return fmt.Errorf("branch is required")
+	}
+	_, err := Run(ctx, []string{"branch", "-D", name}, Options{Dir: dir, ShowOutput: true})
+	return err
+}
+
+// BranchSetUpstream sets the upstream of branch to upstream (e.g. origin/main).
+func BranchSetUpstream(ctx context.Context, dir, branch, upstream string) error {
+	name := strings.TrimSpace(branch)
+	upstream = strings.TrimSpace(upstream)
+	if name == "" {
+		return fmt.Errorf("branch is required")
+	}
+	if upstream == "" {
+		return fmt.Errorf("upstream is required")
+	}
+	_, err := Run(ctx, []string{"branch", "--set-upstream-to=" + upstream, name}, Options{Dir: dir})
+	return err
+}
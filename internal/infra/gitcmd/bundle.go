@@ -0,0 +1,24 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BundleCreate writes the commits selected by revs (rev-list arguments, e.g.
+// "refs/heads/main", "--not", "--remotes=origin") into the bundle file at path.
+func BundleCreate(ctx context.Context, dir, path string, revs ...string) error {
+	if strings.TrimSpace(path) == "" {
+		return fmt.Errorf("bundle path is required")
+	}
+	args := append([]string{"bundle", "create", path}, revs...)
+	res, err := Run(ctx, args, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git bundle create failed: %w: %s", err, errorLine(res.Stderr))
+		}
+		return fmt.Errorf("git bundle create failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CherryEntry is one line of git cherry: a commit of head that upstream does not contain,
+// and whether upstream has a commit with the same patch-id.
+type CherryEntry struct {
+	Commit     string
+	Equivalent bool
+}
+
+// Cherry lists the commits of head missing from upstream (git cherry upstream head).
+func Cherry(ctx context.Context, dir, upstream, head string) ([]CherryEntry, error) {
+	res, err := Run(ctx, []string{"cherry", upstream, head}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return nil, fmt.Errorf("git cherry failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return nil, fmt.Errorf("git cherry failed: %w", err)
+	}
+	var entries []CherryEntry
+	for _, line := range strings.Split(strings.TrimSpace(res.Stdout), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 || (fields[0] != "+" && fields[0] != "-") {
+			return nil, fmt.Errorf("unexpected git cherry output: %q", line)
+		}
+		entries = append(entries, CherryEntry{Commit: fields[1], Equivalent: fields[0] == "-"})
+	}
+	return entries, nil
+}
+
+// CommitTree writes a commit object for tree with the given parent without updating any
+// ref. The committer identity is fixed so it works without user.name/user.email.
+func CommitTree(ctx context.Context, dir, tree, parent, message string) (string, error) {
+	res, err := Run(ctx, []string{"commit-tree", tree, "-p", parent, "-m", message}, Options{
+		Dir: dir,
+		Env: []string{
+			"GIT_AUTHOR_NAME=gion",
+			"GIT_AUTHOR_EMAIL=gion@localhost",
+			"GIT_COMMITTER_NAME=gion",
+			"GIT_COMMITTER_EMAIL=gion@localhost",
+		},
+	})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return "", fmt.Errorf("git commit-tree failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return "", fmt.Errorf("git commit-tree failed: %w", err)
+	}
+	return strings.TrimSpace(res.Stdout), nil
+}
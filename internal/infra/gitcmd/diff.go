@@ -0,0 +1,32 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DiffUncommitted returns a binary patch of every uncommitted change in the worktree at
+// dir against HEAD, untracked (not ignored) files included. The changes are staged into
+// the scratch index file at indexPath, so the worktree's own index is left alone; the
+// caller removes indexPath afterwards.
+func DiffUncommitted(ctx context.Context, dir, indexPath string) (string, error) {
+	if strings.TrimSpace(indexPath) == "" {
+		return "", fmt.Errorf("index path is required")
+	}
+	env := []string{"GIT_INDEX_FILE=" + indexPath}
+	for _, args := range [][]string{{"read-tree", "HEAD"}, {"add", "--all"}} {
+		res, err := Run(ctx, args, Options{Dir: dir, Env: env})
+		if err != nil {
+			if strings.TrimSpace(res.Stderr) != "" {
+				return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, errorLine(res.Stderr))
+			}
+			return "", fmt.Errorf("git %s failed: %w", args[0], err)
+		}
+	}
+	res, err := Run(ctx, []string{"diff", "--cached", "--binary", "HEAD"}, Options{Dir: dir, Env: env})
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return res.Stdout, nil
+}
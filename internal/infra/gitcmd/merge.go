@@ -0,0 +1,35 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Merge merges ref into the branch checked out in dir without opening an editor.
+// With ffOnly, the merge fails unless the branch can be fast-forwarded.
+func Merge(ctx context.Context, dir, ref string, ffOnly bool) error {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return fmt.Errorf("ref is required")
+	}
+	args := []string{"merge", "--no-edit"}
+	if ffOnly {
+		args = append(args, "--ff-only")
+	}
+	args = append(args, ref)
+	res, err := Run(ctx, args, Options{Dir: dir, ShowOutput: true})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git merge %s failed: %w: %s", ref, err, errorLine(res.Stderr))
+		}
+		return fmt.Errorf("git merge %s failed: %w", ref, err)
+	}
+	return nil
+}
+
+// MergeAbort aborts a merge in progress in dir.
+func MergeAbort(ctx context.Context, dir string) error {
+	_, err := Run(ctx, []string{"merge", "--abort"}, Options{Dir: dir})
+	return err
+}
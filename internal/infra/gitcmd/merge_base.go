@@ -28,3 +28,15 @@ func IsAncestor(ctx context.Context, dir, ancestor, descendant string) (bool, er
 	}
 	return false, fmt.Errorf("git merge-base --is-ancestor failed: %w", err)
 }
+
+// MergeBase returns the best common ancestor of a and b.
+func MergeBase(ctx context.Context, dir, a, b string) (string, error) {
+	res, err := Run(ctx, []string{"merge-base", a, b}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return "", fmt.Errorf("git merge-base %s %s failed: %w: %s", a, b, err, strings.TrimSpace(res.Stderr))
+		}
+		return "", fmt.Errorf("git merge-base %s %s failed: %w", a, b, err)
+	}
+	return strings.TrimSpace(res.Stdout), nil
+}
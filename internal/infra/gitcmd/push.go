@@ -0,0 +1,33 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Push pushes branch to the same branch name on remote. With setUpstream, the remote
+// branch becomes the branch's upstream.
+func Push(ctx context.Context, dir, remote, branch string, setUpstream bool) error {
+	remote = strings.TrimSpace(remote)
+	branch = strings.TrimSpace(branch)
+	if remote == "" {
+		return fmt.Errorf("remote is required")
+	}
+	if branch == "" {
+		return fmt.Errorf("branch is required")
+	}
+	args := []string{"push"}
+	if setUpstream {
+		args = append(args, "--set-upstream")
+	}
+	args = append(args, remote, fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch))
+	res, err := Run(ctx, args, Options{Dir: dir, ShowOutput: true})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git push %s %s failed: %w: %s", remote, branch, err, errorLine(res.Stderr))
+		}
+		return fmt.Errorf("git push %s %s failed: %w", remote, branch, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Rebase rebases the branch checked out in dir onto upstream. Progress output is
+// suppressed; conflicts are still reported.
+func Rebase(ctx context.Context, dir, upstream string) error {
+	upstream = strings.TrimSpace(upstream)
+	if upstream == "" {
+		return fmt.Errorf("upstream is required")
+	}
+	res, err := Run(ctx, []string{"rebase", "--quiet", upstream}, Options{Dir: dir, ShowOutput: true})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git rebase %s failed: %w: %s", upstream, err, errorLine(res.Stderr))
+		}
+		return fmt.Errorf("git rebase %s failed: %w", upstream, err)
+	}
+	return nil
+}
+
+// RebaseAbort aborts a rebase in progress in dir.
+func RebaseAbort(ctx context.Context, dir string) error {
+	_, err := Run(ctx, []string{"rebase", "--abort"}, Options{Dir: dir})
+	return err
+}
+
+// errorLine picks the line of git's stderr that explains a failure. Rebase, merge, and
+// push print progress before the actual error, so the first line is often not the reason.
+func errorLine(stderr string) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "fatal: ") || strings.HasPrefix(line, "error: ") || strings.HasPrefix(line, "! [") {
+			return line
+		}
+	}
+	return strings.TrimSpace(lines[0])
+}
@@ -0,0 +1,51 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RevListCount returns the number of commits git rev-list selects for args.
+func RevListCount(ctx context.Context, dir string, args ...string) (int, error) {
+	fullArgs := append([]string{"rev-list", "--count"}, args...)
+	res, err := Run(ctx, fullArgs, Options{Dir: dir})
+	if err != nil {
+		argLabel := strings.Join(args, " ")
+		if strings.TrimSpace(res.Stderr) != "" {
+			return 0, fmt.Errorf("git rev-list %s failed: %w: %s", argLabel, err, strings.TrimSpace(res.Stderr))
+		}
+		return 0, fmt.Errorf("git rev-list %s failed: %w", argLabel, err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(res.Stdout))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected git rev-list output: %q", strings.TrimSpace(res.Stdout))
+	}
+	return count, nil
+}
+
+// RevListLeftRightCount returns how many commits are only in left and only in right.
+func RevListLeftRightCount(ctx context.Context, dir, left, right string) (int, int, error) {
+	rangeSpec := fmt.Sprintf("%s...%s", strings.TrimSpace(left), strings.TrimSpace(right))
+	res, err := Run(ctx, []string{"rev-list", "--left-right", "--count", rangeSpec}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return 0, 0, fmt.Errorf("git rev-list %s failed: %w: %s", rangeSpec, err, strings.TrimSpace(res.Stderr))
+		}
+		return 0, 0, fmt.Errorf("git rev-list %s failed: %w", rangeSpec, err)
+	}
+	fields := strings.Fields(res.Stdout)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected git rev-list output: %q", strings.TrimSpace(res.Stdout))
+	}
+	leftCount, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected git rev-list output: %q", strings.TrimSpace(res.Stdout))
+	}
+	rightCount, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected git rev-list output: %q", strings.TrimSpace(res.Stdout))
+	}
+	return leftCount, rightCount, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 
 	"github.com/tasuku43/gion/internal/infra/debuglog"
@@ -21,6 +22,8 @@ type Options struct {
 	Dir string
 	// ShowOutput prints stdout/stderr even when debug logging is off.
 	ShowOutput bool
+	// Env holds extra KEY=VALUE entries added to the environment of git.
+	Env []string
 }
 
 func Run(ctx context.Context, args []string, opts Options) (Result, error) {
@@ -35,6 +38,9 @@ func Run(ctx context.Context, args []string, opts Options) (Result, error) {
 	if opts.Dir != "" {
 		cmd.Dir = opts.Dir
 	}
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -59,10 +65,10 @@ func Run(ctx context.Context, args []string, opts Options) (Result, error) {
 	}
 	if opts.ShowOutput {
 		if result.Stdout != "" {
-			output.LogLines(result.Stdout)
+			output.LogLinesContext(ctx, result.Stdout)
 		}
 		if result.Stderr != "" {
-			output.LogLines(result.Stderr)
+			output.LogLinesContext(ctx, result.Stderr)
 		}
 	}
 	if err != nil {
@@ -87,17 +93,29 @@ func isAllowedSubcommand(subcommand string) bool {
 }
 
 var allowedSubcommands = map[string]struct{}{
+	"add":              {},
+	"apply":            {},
 	"branch":           {},
+	"bundle":           {},
 	"check-ref-format": {},
+	"cherry":           {},
 	"clone":            {},
+	"commit-tree":      {},
 	"config":           {},
+	"diff":             {},
 	"fetch":            {},
 	"init":             {},
 	"ls-remote":        {},
+	"merge":            {},
 	"merge-base":       {},
+	"push":             {},
+	"read-tree":        {},
+	"rebase":           {},
+	"rev-list":         {},
 	"rev-parse":        {},
 	"remote":           {},
 	"show-ref":         {},
+	"stash":            {},
 	"symbolic-ref":     {},
 	"status":           {},
 	"update-ref":       {},
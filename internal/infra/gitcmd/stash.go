@@ -0,0 +1,26 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// StashSubjects returns the reflog subject of every stash entry (e.g. "WIP on main: abc123 msg").
+// The stash is shared by all worktrees of a repository.
+func StashSubjects(ctx context.Context, dir string) ([]string, error) {
+	res, err := Run(ctx, []string{"stash", "list", "--format=%gs"}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return nil, fmt.Errorf("git stash list failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return nil, fmt.Errorf("git stash list failed: %w", err)
+	}
+	var subjects []string
+	for _, line := range strings.Split(res.Stdout, "\n") {
+		if strings.TrimSpace(line) != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
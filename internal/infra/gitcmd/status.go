@@ -18,6 +18,19 @@ func StatusPorcelainV2(ctx context.Context, dir string) (string, error) {
 	return res.Stdout, nil
 }
 
+// StatusPorcelainV2Ignored is StatusPorcelainV2 that also lists ignored files ("! <path>";
+// an ignored directory is one entry).
+func StatusPorcelainV2Ignored(ctx context.Context, dir string) (string, error) {
+	res, err := Run(ctx, []string{"status", "--porcelain=v2", "-b", "--ignored"}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return "", fmt.Errorf("git status failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return "", fmt.Errorf("git status failed: %w", err)
+	}
+	return res.Stdout, nil
+}
+
 // StatusShortBranch returns short status output with branch info.
 func StatusShortBranch(ctx context.Context, dir string) (string, error) {
 	res, err := Run(ctx, []string{"status", "--short", "--branch"}, Options{Dir: dir})
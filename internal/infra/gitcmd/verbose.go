@@ -1,7 +1,12 @@
 package gitcmd
 
-import "github.com/tasuku43/gion/internal/infra/output"
+import (
+	"context"
+	"fmt"
 
-func Logf(format string, args ...any) {
-	output.Logf("$ "+format, args...)
+	"github.com/tasuku43/gion/internal/infra/output"
+)
+
+func Logf(ctx context.Context, format string, args ...any) {
+	output.LogContext(ctx, fmt.Sprintf("$ "+format, args...))
 }
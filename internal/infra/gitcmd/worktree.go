@@ -81,3 +81,15 @@ func WorktreeRemove(ctx context.Context, dir, path string, force bool) error {
 	}
 	return nil
 }
+
+// WorktreeMove moves a worktree to a new path; the destination's parent must exist.
+func WorktreeMove(ctx context.Context, dir, path, newPath string) error {
+	res, err := Run(ctx, []string{"worktree", "move", path, newPath}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git worktree move failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git worktree move failed: %w", err)
+	}
+	return nil
+}
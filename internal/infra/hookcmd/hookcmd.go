@@ -0,0 +1,40 @@
+package hookcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/tasuku43/gion/internal/infra/debuglog"
+	"github.com/tasuku43/gion/internal/infra/output"
+)
+
+// Run runs command through sh -c in dir with env appended to the process environment.
+// The combined stdout/stderr is logged to the current step and returned.
+func Run(ctx context.Context, dir, command string, env []string) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	trace := ""
+	if debuglog.Enabled() {
+		trace = debuglog.NewTrace("hook")
+		debuglog.LogCommand(trace, debuglog.FormatCommand("sh", []string{"-c", command}))
+	}
+	output.LogContext(ctx, "$ "+command)
+	err := cmd.Run()
+	if debuglog.Enabled() {
+		debuglog.LogStdoutLines(trace, out.String())
+		debuglog.LogExit(trace, debuglog.ExitCode(err))
+	}
+	output.LogLinesContext(ctx, out.String())
+	if err != nil {
+		return out.String(), fmt.Errorf("%s: %w", command, err)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+type loggerKey struct{}
+
+// WithLogger returns a context whose LogContext/LogLinesContext output goes to logger
+// instead of the global step logger. Concurrent jobs use it to keep their git logs
+// attached to their own step.
+func WithLogger(ctx context.Context, logger StepLogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+func loggerFromContext(ctx context.Context) StepLogger {
+	if ctx == nil {
+		return nil
+	}
+	logger, _ := ctx.Value(loggerKey{}).(StepLogger)
+	return logger
+}
+
+func LogContext(ctx context.Context, text string) {
+	if logger := loggerFromContext(ctx); logger != nil {
+		logger.Log(text)
+		return
+	}
+	Log(text)
+}
+
+func LogLinesContext(ctx context.Context, text string) {
+	logger := loggerFromContext(ctx)
+	if logger == nil {
+		LogLines(text)
+		return
+	}
+	for _, line := range splitLogLines(text) {
+		logger.LogOutput(line)
+	}
+}
+
+type bufferedLine struct {
+	kind string
+	text string
+}
+
+// Buffer records step output so it can be written later as one uninterrupted block.
+type Buffer struct {
+	mu    sync.Mutex
+	lines []bufferedLine
+}
+
+func (b *Buffer) Step(text string) {
+	b.append("step", text)
+}
+
+func (b *Buffer) Log(text string) {
+	b.append("log", text)
+}
+
+func (b *Buffer) LogOutput(text string) {
+	b.append("output", text)
+}
+
+func (b *Buffer) append(kind, text string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines = append(b.lines, bufferedLine{kind: kind, text: text})
+}
+
+var flushMu sync.Mutex
+
+// Flush writes the buffered lines through Step/Log/LogOutput and clears the buffer.
+// Flushes from different goroutines never interleave.
+func (b *Buffer) Flush() {
+	b.mu.Lock()
+	lines := b.lines
+	b.lines = nil
+	b.mu.Unlock()
+
+	flushMu.Lock()
+	defer flushMu.Unlock()
+	for _, line := range lines {
+		switch line.kind {
+		case "step":
+			Step(line.text)
+		case "log":
+			Log(line.text)
+		default:
+			LogOutput(line.text)
+		}
+	}
+}
+
+func splitLogLines(text string) []string {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -75,14 +75,7 @@ func LogOutputf(format string, args ...any) {
 }
 
 func LogLines(text string) {
-	if strings.TrimSpace(text) == "" {
-		return
-	}
-	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
+	for _, line := range splitLogLines(text) {
 		LogOutput(line)
 	}
 }
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"unicode/utf8"
@@ -45,3 +46,34 @@ func TestLogLinesUsesLogOutput(t *testing.T) {
 		t.Fatalf("logs = %d, want 0", len(logger.logs))
 	}
 }
+
+func TestBufferFlushKeepsBlockTogether(t *testing.T) {
+	logger := &captureLogger{}
+	SetStepLogger(logger)
+	defer SetStepLogger(nil)
+
+	buf := &Buffer{}
+	ctx := WithLogger(context.Background(), buf)
+	buf.Step("worktree add app")
+	LogContext(ctx, "$ git worktree add")
+	LogLinesContext(ctx, "line1\n\nline2\n")
+	if len(logger.steps) != 0 || len(logger.logs) != 0 || len(logger.logOutputs) != 0 {
+		t.Fatalf("buffered output leaked before flush: %+v", logger)
+	}
+
+	buf.Flush()
+	if strings.Join(logger.steps, ",") != "worktree add app" {
+		t.Fatalf("steps: got %v", logger.steps)
+	}
+	if strings.Join(logger.logs, ",") != "$ git worktree add" {
+		t.Fatalf("logs: got %v", logger.logs)
+	}
+	if strings.Join(logger.logOutputs, ",") != "line1,line2" {
+		t.Fatalf("log outputs: got %v", logger.logOutputs)
+	}
+
+	buf.Flush()
+	if len(logger.steps) != 1 {
+		t.Fatalf("flush should clear the buffer: %v", logger.steps)
+	}
+}
@@ -11,3 +11,13 @@ func BareRoot(rootDir string) string {
 func WorkspacesRoot(rootDir string) string {
 	return filepath.Join(rootDir, "workspaces")
 }
+
+// ArchiveRoot returns the path to the archived workspaces root.
+func ArchiveRoot(rootDir string) string {
+	return filepath.Join(rootDir, "archive")
+}
+
+// StateDir returns the path to gion's own state directory under the root.
+func StateDir(rootDir string) string {
+	return filepath.Join(rootDir, ".gion")
+}
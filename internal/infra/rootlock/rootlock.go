@@ -0,0 +1,219 @@
+package rootlock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+const lockFileName = "lock"
+
+// takeoverSuffix names the file that is flocked while a stale lock is removed.
+const takeoverSuffix = ".takeover"
+
+// pollInterval is how often a waiting process re-checks the lock.
+const pollInterval = 100 * time.Millisecond
+
+// unreadableGrace is how long a lock file that cannot be parsed is trusted. A holder
+// writes its owner record right after creating the file, so an empty or broken file
+// older than this was left by a crashed process.
+const unreadableGrace = 10 * time.Second
+
+// ErrLocked is returned (wrapped) when the lock could not be taken before the timeout.
+var ErrLocked = errors.New("gion root is locked")
+
+// Owner identifies the process holding the lock.
+type Owner struct {
+	PID       int       `json:"pid"`
+	Hostname  string    `json:"hostname"`
+	Command   string    `json:"command,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (o Owner) String() string {
+	text := fmt.Sprintf("pid %d on %s", o.PID, o.Hostname)
+	if o.Command != "" {
+		text += fmt.Sprintf(" (%s)", o.Command)
+	}
+	if !o.CreatedAt.IsZero() {
+		text += fmt.Sprintf(" since %s", o.CreatedAt.Local().Format("15:04:05"))
+	}
+	return text
+}
+
+type Options struct {
+	// Timeout is how long to wait for another process to release the lock.
+	// Zero fails immediately.
+	Timeout time.Duration
+	// Command is recorded in the lock file to tell waiting processes who holds it.
+	Command string
+	// OnWait is called once, when the lock is found held by a live process.
+	OnWait func(owner Owner)
+}
+
+// Lock is an advisory, exclusive lock on a gion root (GION_ROOT/.gion/lock).
+type Lock struct {
+	path string
+}
+
+func Path(rootDir string) string {
+	return filepath.Join(paths.StateDir(rootDir), lockFileName)
+}
+
+// Acquire takes the lock, waiting up to opts.Timeout for a live holder to release it.
+// Locks left by processes that no longer exist on this host are removed.
+func Acquire(ctx context.Context, rootDir string, opts Options) (*Lock, error) {
+	path := Path(rootDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("create state dir: %w", err)
+	}
+	hostname, _ := os.Hostname()
+	self := Owner{
+		PID:       os.Getpid(),
+		Hostname:  hostname,
+		Command:   opts.Command,
+		CreatedAt: time.Now().UTC(),
+	}
+	data, err := json.Marshal(self)
+	if err != nil {
+		return nil, fmt.Errorf("marshal lock owner: %w", err)
+	}
+
+	deadline := time.Now().Add(opts.Timeout)
+	waited := false
+	for {
+		created, err := tryCreate(path, data)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return &Lock{path: path}, nil
+		}
+
+		owner, raw, readErr := readOwner(path)
+		if errors.Is(readErr, os.ErrNotExist) {
+			continue
+		}
+		if isStale(path, owner, readErr, hostname) {
+			if err := removeIfUnchanged(path, raw); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !waited && opts.OnWait != nil && opts.Timeout > 0 {
+			opts.OnWait(owner)
+		}
+		waited = true
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("%w by %s (lock file: %s); retry later or raise --lock-timeout", ErrLocked, owner, path)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Release removes the lock file. It is safe to call on a nil Lock.
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("release lock: %w", err)
+	}
+	return nil
+}
+
+func tryCreate(path string, data []byte) (bool, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("create lock file: %w", err)
+	}
+	_, writeErr := file.Write(data)
+	closeErr := file.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(path)
+		return false, fmt.Errorf("write lock file: %w", errors.Join(writeErr, closeErr))
+	}
+	return true, nil
+}
+
+func readOwner(path string) (Owner, []byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Owner{}, nil, err
+	}
+	var owner Owner
+	if err := json.Unmarshal(raw, &owner); err != nil {
+		return Owner{}, raw, err
+	}
+	return owner, raw, nil
+}
+
+// isStale reports whether the lock can be taken over: its holder ran on this host and
+// is gone, or the file has been unreadable for longer than a holder needs to write it.
+// Locks held from other hosts are never considered stale (their PID cannot be checked).
+func isStale(path string, owner Owner, readErr error, hostname string) bool {
+	if readErr != nil {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false
+		}
+		return time.Since(info.ModTime()) > unreadableGrace
+	}
+	if owner.Hostname != hostname || owner.PID <= 0 {
+		return false
+	}
+	if owner.PID == os.Getpid() {
+		// Left by an earlier process that had our PID (e.g. a restarted container).
+		return true
+	}
+	return !processAlive(owner.PID)
+}
+
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// removeIfUnchanged deletes a stale lock unless another process replaced it in the
+// meantime. Takeovers are serialized by an flock on a companion file (released by the
+// kernel when its holder exits), so only one process at a time compares and removes the
+// lock file, and a lock created after the stale one was read is never removed.
+func removeIfUnchanged(path string, stale []byte) error {
+	guard, err := os.OpenFile(path+takeoverSuffix, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("remove stale lock: %w", err)
+	}
+	defer guard.Close()
+	if err := syscall.Flock(int(guard.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("remove stale lock: %w", err)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("remove stale lock: %w", err)
+	}
+	if !bytes.Equal(current, stale) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove stale lock: %w", err)
+	}
+	return nil
+}
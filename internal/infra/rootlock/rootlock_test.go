@@ -0,0 +1,147 @@
+package rootlock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeOwner(t *testing.T, rootDir string, owner Owner) {
+	t.Helper()
+	data, err := json.Marshal(owner)
+	if err != nil {
+		t.Fatalf("marshal owner: %v", err)
+	}
+	path := Path(rootDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+}
+
+func TestAcquireRelease(t *testing.T) {
+	rootDir := t.TempDir()
+
+	lock, err := Acquire(context.Background(), rootDir, Options{Command: "gion apply"})
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	data, err := os.ReadFile(Path(rootDir))
+	if err != nil {
+		t.Fatalf("read lock: %v", err)
+	}
+	var owner Owner
+	if err := json.Unmarshal(data, &owner); err != nil {
+		t.Fatalf("parse lock: %v", err)
+	}
+	if owner.PID != os.Getpid() || owner.Command != "gion apply" {
+		t.Fatalf("unexpected owner: %+v", owner)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, err := os.Stat(Path(rootDir)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("lock file should be removed, stat err=%v", err)
+	}
+	if err := (*Lock)(nil).Release(); err != nil {
+		t.Fatalf("nil release: %v", err)
+	}
+}
+
+func TestAcquireHeldByLiveProcess(t *testing.T) {
+	rootDir := t.TempDir()
+	hostname, _ := os.Hostname()
+	// The test binary's parent is alive for the duration of the test.
+	writeOwner(t, rootDir, Owner{PID: os.Getppid(), Hostname: hostname, Command: "gion apply", CreatedAt: time.Now()})
+
+	waited := false
+	start := time.Now()
+	_, err := Acquire(context.Background(), rootDir, Options{
+		Timeout: 250 * time.Millisecond,
+		OnWait:  func(Owner) { waited = true },
+	})
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "gion apply") {
+		t.Fatalf("error should name the holder: %v", err)
+	}
+	if !waited || time.Since(start) < 250*time.Millisecond {
+		t.Fatalf("expected to wait for the timeout (waited=%v)", waited)
+	}
+
+	if _, err := Acquire(context.Background(), rootDir, Options{}); !errors.Is(err, ErrLocked) {
+		t.Fatalf("zero timeout: expected ErrLocked, got %v", err)
+	}
+}
+
+func TestAcquireTakesOverStaleLock(t *testing.T) {
+	rootDir := t.TempDir()
+	hostname, _ := os.Hostname()
+	writeOwner(t, rootDir, Owner{PID: deadPID(t), Hostname: hostname, CreatedAt: time.Now()})
+
+	lock, err := Acquire(context.Background(), rootDir, Options{})
+	if err != nil {
+		t.Fatalf("stale lock should be taken over: %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+}
+
+func TestRemoveIfUnchangedKeepsReplacedLock(t *testing.T) {
+	rootDir := t.TempDir()
+	hostname, _ := os.Hostname()
+	writeOwner(t, rootDir, Owner{PID: deadPID(t), Hostname: hostname, CreatedAt: time.Now()})
+	stale, err := os.ReadFile(Path(rootDir))
+	if err != nil {
+		t.Fatalf("read lock: %v", err)
+	}
+
+	// Another process took the stale lock over and holds it now.
+	writeOwner(t, rootDir, Owner{PID: os.Getpid(), Hostname: hostname, CreatedAt: time.Now()})
+	live, err := os.ReadFile(Path(rootDir))
+	if err != nil {
+		t.Fatalf("read lock: %v", err)
+	}
+	if err := removeIfUnchanged(Path(rootDir), stale); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if current, err := os.ReadFile(Path(rootDir)); err != nil || string(current) != string(live) {
+		t.Fatalf("expected the live lock to be kept, got %q (%v)", current, err)
+	}
+
+	if err := removeIfUnchanged(Path(rootDir), live); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := os.Stat(Path(rootDir)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected an unchanged lock to be removed, got %v", err)
+	}
+}
+
+func TestAcquireKeepsLockFromOtherHost(t *testing.T) {
+	rootDir := t.TempDir()
+	writeOwner(t, rootDir, Owner{PID: deadPID(t), Hostname: "elsewhere.invalid", CreatedAt: time.Now()})
+
+	if _, err := Acquire(context.Background(), rootDir, Options{}); !errors.Is(err, ErrLocked) {
+		t.Fatalf("lock from another host must not be taken over, got %v", err)
+	}
+}
+
+func deadPID(t *testing.T) int {
+	t.Helper()
+	for pid := 4_000_000; pid > 3_000_000; pid -= 7919 {
+		if !processAlive(pid) {
+			return pid
+		}
+	}
+	t.Skip("no unused pid found")
+	return 0
+}
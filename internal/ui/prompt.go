@@ -207,7 +207,7 @@ func (m *createFlowModel) startMode(mode, presetName string) {
 		m.presetModel = newInputsModelWithLabel(m.title, m.presets, presetName, m.defaultWorkspaceID, "preset", m.validateWorkspaceID, m.theme, m.useColor)
 	case "review":
 		if len(m.reviewRepos) == 0 {
-			m.err = fmt.Errorf("no GitHub repos found")
+			m.err = fmt.Errorf("no repos with a supported provider found")
 			return
 		}
 		m.mode = mode
@@ -312,7 +312,7 @@ func (m createFlowModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.presetModel = newInputsModel(m.title, m.presets, "", "", m.theme, m.useColor)
 				case "review":
 					if len(m.reviewRepos) == 0 {
-						m.err = fmt.Errorf("no GitHub repos found")
+						m.err = fmt.Errorf("no repos with a supported provider found")
 						return m, tea.Quit
 					}
 					m.stage = createStageReviewRepo
@@ -605,8 +605,8 @@ func (m createFlowModel) filterModes() []PromptChoice {
 	q := strings.ToLower(strings.TrimSpace(m.modeInput.Value()))
 	choices := []PromptChoice{
 		{Label: "repo", Value: "repo", Description: "1 repo only"},
-		{Label: "issue", Value: "issue", Description: "From an issue (multi-select, GitHub only)"},
-		{Label: "review", Value: "review", Description: "From a review request (multi-select, GitHub only)"},
+		{Label: "issue", Value: "issue", Description: "From an issue (multi-select, GitHub/GitLab/Gitea/Bitbucket)"},
+		{Label: "review", Value: "review", Description: "From a review request (multi-select, GitHub/GitLab/Gitea/Bitbucket)"},
 		{Label: "preset", Value: "preset", Description: "From preset"},
 	}
 	if q == "" {